@@ -0,0 +1,91 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+)
+
+// ListArgumentPresets fetches all argument presets.
+func (c *Client) ListArgumentPresets() ([]*types.ArgumentPreset, error) {
+	u, _ := c.constructAPIEndpoint("/argument-presets")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var presets []*types.ArgumentPreset
+	if err := json.NewDecoder(resp.Body).Decode(&presets); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return presets, nil
+}
+
+// SetArgumentPreset creates the argument preset of a tool (for a client) or replaces the existing one.
+func (c *Client) SetArgumentPreset(preset *types.ArgumentPreset) error {
+	u, _ := c.constructAPIEndpoint("/argument-presets")
+	body, err := json.Marshal(preset)
+	if err != nil {
+		return fmt.Errorf("failed to serialize argument preset into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// DeleteArgumentPreset deletes the argument preset of a tool, identified by the tool's canonical name.
+// If client is empty, the preset that applies to all clients is deleted.
+func (c *Client) DeleteArgumentPreset(tool, client string) error {
+	u, _ := c.constructAPIEndpoint("/argument-presets/" + tool)
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if client != "" {
+		q := req.URL.Query()
+		q.Add("client", client)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
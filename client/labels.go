@@ -0,0 +1,92 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// EnableToolsByLabels enables all tools whose labels match the label selector (eg- team=data).
+func (c *Client) EnableToolsByLabels(selector string) ([]string, error) {
+	return c.setToolsEnabledByLabels("/tools/enable", selector)
+}
+
+// DisableToolsByLabels disables all tools whose labels match the label selector (eg- team=data).
+func (c *Client) DisableToolsByLabels(selector string) ([]string, error) {
+	return c.setToolsEnabledByLabels("/tools/disable", selector)
+}
+
+func (c *Client) setToolsEnabledByLabels(endpoint, selector string) ([]string, error) {
+	u, _ := c.constructAPIEndpoint(endpoint)
+	req, err := c.newRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	q := req.URL.Query()
+	q.Add("labels", selector)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var tools []string
+	if err := json.NewDecoder(resp.Body).Decode(&tools); err != nil {
+		return nil, fmt.Errorf("failed to decode API response: %w", err)
+	}
+	return tools, nil
+}
+
+// UpdateServerLabels adds, changes or removes (nil value) labels of an MCP server and returns its new labels.
+func (c *Client) UpdateServerLabels(name string, labels map[string]*string) (map[string]string, error) {
+	u, _ := c.constructAPIEndpoint("/servers/" + name + "/labels")
+	return c.updateLabels(u, labels)
+}
+
+// UpdateToolLabels adds, changes or removes (nil value) labels of a tool and returns its new labels.
+// The returned labels do not include the ones the tool inherits from its MCP server.
+func (c *Client) UpdateToolLabels(name string, labels map[string]*string) (map[string]string, error) {
+	u, _ := c.constructAPIEndpoint("/tool/labels")
+	return c.updateLabels(u+"?name="+url.QueryEscape(name), labels)
+}
+
+func (c *Client) updateLabels(u string, labels map[string]*string) (map[string]string, error) {
+	body, err := json.Marshal(&types.LabelsUpdate{Labels: labels})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal labels: %w", err)
+	}
+	req, err := c.newRequest(http.MethodPatch, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, respBody)
+	}
+
+	var updated map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		return nil, fmt.Errorf("failed to decode API response: %w", err)
+	}
+	return updated, nil
+}
@@ -10,30 +10,8 @@ import (
 )
 
 func (c *Client) ListMcpClients() ([]types.McpClient, error) {
-	u, _ := c.constructAPIEndpoint("/clients")
-
-	req, err := c.newRequest(http.MethodGet, u, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
-	}
-
-	var clients []types.McpClient
-	if err := json.NewDecoder(resp.Body).Decode(&clients); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return clients, nil
+	clients, _, err := c.ListMcpClientsPage(types.ListOptions{})
+	return clients, err
 }
 
 func (c *Client) DeleteMcpClient(name string) error {
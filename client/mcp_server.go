@@ -43,28 +43,14 @@ func (c *Client) RegisterServer(server *types.RegisterServerInput) (*types.McpSe
 
 // ListServers fetches the list of registered servers.
 func (c *Client) ListServers() ([]*types.McpServer, error) {
-	u, _ := c.constructAPIEndpoint("/servers")
-	req, err := c.newRequest(http.MethodGet, u, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
-	}
+	return c.ListServersByLabels("")
+}
 
-	var servers []*types.McpServer
-	if err := json.NewDecoder(resp.Body).Decode(&servers); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-	return servers, nil
+// ListServersByLabels fetches the servers whose labels match the label selector (eg- team=data).
+// An empty selector matches all servers.
+func (c *Client) ListServersByLabels(selector string) ([]*types.McpServer, error) {
+	servers, _, err := c.ListServersPage(selector, types.ListOptions{})
+	return servers, err
 }
 
 // DeregisterServer deletes a server by name.
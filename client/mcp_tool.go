@@ -11,29 +11,14 @@ import (
 
 // ListTools fetches the list of tools, optionally filtered by server name.
 func (c *Client) ListTools(server string) ([]*types.Tool, error) {
-	u, _ := c.constructAPIEndpoint("/tools")
-	req, _ := c.newRequest(http.MethodGet, u, nil)
-	if server != "" {
-		q := req.URL.Query()
-		q.Add("server", server)
-		req.URL.RawQuery = q.Encode()
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
-	}
+	return c.SearchTools(types.ToolFilter{Server: server})
+}
 
-	var tools []*types.Tool
-	if err := json.NewDecoder(resp.Body).Decode(&tools); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-	return tools, nil
+// SearchTools fetches the tools that match the filter.
+// If the filter has a query, the tools are ranked by relevance.
+func (c *Client) SearchTools(filter types.ToolFilter) ([]*types.Tool, error) {
+	tools, _, err := c.ListToolsPage(filter, types.ListOptions{})
+	return tools, err
 }
 
 // EnableTools enables a tool or all tools provided by an MCP server.
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+)
+
+// StartOAuthAuthorization starts the authorization of mcpjungle by the user to access an MCP server.
+// It returns the URL that the user must visit.
+func (c *Client) StartOAuthAuthorization(input *types.OAuthAuthorizeInput) (*types.OAuthAuthorizeResponse, error) {
+	u, _ := c.constructAPIEndpoint("/oauth/authorize")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize authorization input into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var out types.OAuthAuthorizeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode API response: %w", err)
+	}
+	return &out, nil
+}
+
+// CompleteOAuthAuthorization hands the authorization code received from the authorization server to mcpjungle,
+// which exchanges it for tokens.
+func (c *Client) CompleteOAuthAuthorization(input *types.OAuthCallbackInput) error {
+	u, _ := c.constructAPIEndpoint("/oauth/callback")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to serialize authorization code into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// CreateOAuthApproval issues a one-time approval code that lets a user approve an OAuth authorization
+// on mcpjungle's consent page. The authorization grants the identity of the given MCP client.
+func (c *Client) CreateOAuthApproval(input *types.OAuthApprovalInput) (*types.OAuthApproval, error) {
+	u, _ := c.constructAPIEndpoint("/oauth-approvals")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize approval input into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var out types.OAuthApproval
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode API response: %w", err)
+	}
+	return &out, nil
+}
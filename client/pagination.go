@@ -0,0 +1,144 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// defaultPageSize is the number of results fetched per request when iterating over a list.
+const defaultPageSize = 200
+
+// ListToolsPage fetches a page of the tools that match the filter, along with the cursor of the next page,
+// which is empty on the last page.
+func (c *Client) ListToolsPage(filter types.ToolFilter, opts types.ListOptions) ([]*types.Tool, string, error) {
+	q := url.Values{}
+	if filter.Query != "" {
+		q.Set("q", filter.Query)
+	}
+	if filter.Server != "" {
+		q.Set("server", filter.Server)
+	}
+	if filter.Enabled != nil {
+		q.Set("enabled", strconv.FormatBool(*filter.Enabled))
+	}
+	if filter.Labels != "" {
+		q.Set("labels", filter.Labels)
+	}
+	if filter.Tag != "" {
+		q.Set("tag", filter.Tag)
+	}
+	var tools []*types.Tool
+	next, err := c.getPage("/tools", q, opts, &tools)
+	return tools, next, err
+}
+
+// ListServersPage fetches a page of the servers whose labels match the label selector, along with the cursor
+// of the next page, which is empty on the last page. An empty selector matches all servers.
+func (c *Client) ListServersPage(selector string, opts types.ListOptions) ([]*types.McpServer, string, error) {
+	q := url.Values{}
+	if selector != "" {
+		q.Set("labels", selector)
+	}
+	var servers []*types.McpServer
+	next, err := c.getPage("/servers", q, opts, &servers)
+	return servers, next, err
+}
+
+// ListMcpClientsPage fetches a page of the MCP clients, along with the cursor of the next page,
+// which is empty on the last page.
+func (c *Client) ListMcpClientsPage(opts types.ListOptions) ([]types.McpClient, string, error) {
+	var clients []types.McpClient
+	next, err := c.getPage("/clients", url.Values{}, opts, &clients)
+	return clients, next, err
+}
+
+// AllTools iterates over the tools that match the filter, fetching them page by page.
+// Iteration stops at the first error.
+func (c *Client) AllTools(filter types.ToolFilter, opts types.ListOptions) iter.Seq2[*types.Tool, error] {
+	return paginate(opts, func(o types.ListOptions) ([]*types.Tool, string, error) {
+		return c.ListToolsPage(filter, o)
+	})
+}
+
+// AllServers iterates over the servers whose labels match the label selector, fetching them page by page.
+// Iteration stops at the first error.
+func (c *Client) AllServers(selector string, opts types.ListOptions) iter.Seq2[*types.McpServer, error] {
+	return paginate(opts, func(o types.ListOptions) ([]*types.McpServer, string, error) {
+		return c.ListServersPage(selector, o)
+	})
+}
+
+// AllMcpClients iterates over the MCP clients, fetching them page by page.
+// Iteration stops at the first error.
+func (c *Client) AllMcpClients(opts types.ListOptions) iter.Seq2[types.McpClient, error] {
+	return paginate(opts, c.ListMcpClientsPage)
+}
+
+// paginate iterates over the results of a list, fetching the next page whenever the current one is exhausted.
+// If the options do not set a page size, defaultPageSize is used.
+func paginate[T any](opts types.ListOptions, fetch func(types.ListOptions) ([]T, string, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if opts.Limit == 0 {
+			opts.Limit = defaultPageSize
+		}
+		for {
+			page, next, err := fetch(opts)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, v := range page {
+				if !yield(v, nil) {
+					return
+				}
+			}
+			if next == "" {
+				return
+			}
+			opts.Cursor = next
+		}
+	}
+}
+
+// getPage sends a list request with the list options added to its query and decodes the page of results
+// into dst. It returns the cursor of the next page, which is empty on the last page.
+func (c *Client) getPage(path string, q url.Values, opts types.ListOptions, dst any) (string, error) {
+	u, _ := c.constructAPIEndpoint(path)
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	if opts.Sort != "" {
+		q.Set("sort", opts.Sort)
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		q.Set("cursor", opts.Cursor)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return resp.Header.Get(types.NextCursorHeader), nil
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+)
+
+// ListToolOverrides fetches all tool overrides.
+func (c *Client) ListToolOverrides() ([]*types.ToolOverride, error) {
+	u, _ := c.constructAPIEndpoint("/tool-overrides")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var overrides []*types.ToolOverride
+	if err := json.NewDecoder(resp.Body).Decode(&overrides); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return overrides, nil
+}
+
+// SetToolOverride creates the override of a tool or replaces its existing override.
+func (c *Client) SetToolOverride(override *types.ToolOverride) error {
+	u, _ := c.constructAPIEndpoint("/tool-overrides")
+	body, err := json.Marshal(override)
+	if err != nil {
+		return fmt.Errorf("failed to serialize tool override into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// DeleteToolOverride deletes the override of a tool, identified by the tool's canonical name.
+func (c *Client) DeleteToolOverride(tool string) error {
+	u, _ := c.constructAPIEndpoint("/tool-overrides/" + tool)
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+	"net"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// oauthCallbackTimeout is how long the CLI waits for the user to authorize mcpjungle in their browser.
+const oauthCallbackTimeout = 5 * time.Minute
+
+var authorizeCmdCallbackPort int
+
+var authorizeCmd = &cobra.Command{
+	Use:   "authorize <server>",
+	Short: "Authorize mcpjungle to access an MCP server using OAuth",
+	Long: "Authorize mcpjungle to access an MCP server that uses the OAuth authorization_code grant.\n" +
+		"This opens the authorization page of the MCP server in your browser. Once you approve access,\n" +
+		"mcpjungle stores the tokens and keeps them fresh.\n" +
+		"Run this again whenever mcpjungle reports that the server requires authorization.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runOAuthAuthorizationFlow(args[0], nil, authorizeCmdCallbackPort)
+	},
+}
+
+func init() {
+	authorizeCmd.Flags().IntVar(
+		&authorizeCmdCallbackPort,
+		"callback-port",
+		0,
+		"Local port to receive the authorization response on (default: a random free port)",
+	)
+	rootCmd.AddCommand(authorizeCmd)
+}
+
+// runOAuthAuthorizationFlow lets the user authorize mcpjungle to access an MCP server in their browser.
+// The browser is redirected to a temporary local HTTP server, which passes the authorization code on to mcpjungle.
+// conf is the OAuth configuration of the server, it is only needed if the server is not registered yet.
+func runOAuthAuthorizationFlow(server string, conf *types.UpstreamOAuthConfig, callbackPort int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", callbackPort))
+	if err != nil {
+		return fmt.Errorf("failed to listen for the authorization response: %w", err)
+	}
+	defer listener.Close()
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr().String())
+
+	auth, err := apiClient.StartOAuthAuthorization(&types.OAuthAuthorizeInput{
+		Server:      server,
+		RedirectURI: redirectURI,
+		OAuth:       conf,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start authorization: %w", err)
+	}
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	results := make(chan callbackResult, 1)
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/callback" {
+				http.NotFound(w, r)
+				return
+			}
+			q := r.URL.Query()
+			if q.Get("state") != auth.State {
+				http.Error(w, "unexpected state", http.StatusBadRequest)
+				return
+			}
+			var res callbackResult
+			if e := q.Get("error"); e != "" {
+				res.err = fmt.Errorf("authorization denied: %s %s", e, q.Get("error_description"))
+				fmt.Fprintln(w, "Authorization failed, check the mcpjungle CLI for details.")
+			} else {
+				res.code = q.Get("code")
+				fmt.Fprintln(w, "mcpjungle has been authorized, you can close this window.")
+			}
+			select {
+			case results <- res:
+			default:
+			}
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Shutdown(context.Background())
+
+	fmt.Printf("Open the following URL in your browser to authorize mcpjungle to access %s:\n\n", server)
+	fmt.Println(auth.AuthorizationURL)
+	fmt.Println()
+	openBrowser(auth.AuthorizationURL)
+
+	var res callbackResult
+	select {
+	case res = <-results:
+	case <-time.After(oauthCallbackTimeout):
+		return errors.New("timed out waiting for authorization")
+	}
+	if res.err != nil {
+		return res.err
+	}
+
+	err = apiClient.CompleteOAuthAuthorization(&types.OAuthCallbackInput{State: auth.State, Code: res.code})
+	if err != nil {
+		return fmt.Errorf("failed to complete authorization: %w", err)
+	}
+	fmt.Printf("mcpjungle is now authorized to access %s\n", server)
+	return nil
+}
+
+// openBrowser opens the URL in the user's browser on best-effort basis.
+func openBrowser(url string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	_ = cmd.Start()
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the tool result cache",
+	Long: "MCPJungle can cache the results of read-only tools as configured in the policy of their MCP servers.\n" +
+		"Use these commands to inspect and clear the cache.",
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache hits and misses per MCP server",
+	RunE:  runCacheStats,
+}
+
+var cachePurgeCmd = &cobra.Command{
+	Use:   "purge [name]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Remove cached tool results",
+	Long: "Specify the name of a tool or MCP server to remove its cached results.\n" +
+		"If a server is specified, the cached results of all its tools are removed.\n" +
+		"If no name is specified, the entire cache is cleared.",
+	RunE: runCachePurge,
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cachePurgeCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	stats, err := apiClient.GetCacheStats()
+	if err != nil {
+		return fmt.Errorf("failed to get cache stats: %w", err)
+	}
+	if len(stats) == 0 {
+		cmd.Println("No tool results have been cached yet")
+		return nil
+	}
+	for i, s := range stats {
+		hitRate := 0.0
+		if total := s.Hits + s.Misses; total > 0 {
+			hitRate = float64(s.Hits) / float64(total) * 100
+		}
+		cmd.Printf("%d. %s\n", i+1, s.Server)
+		cmd.Printf("Cached results: %d\n", s.Entries)
+		cmd.Printf("Hits: %d, Misses: %d (%.1f%% hit rate)\n", s.Hits, s.Misses, hitRate)
+		if i < len(stats)-1 {
+			cmd.Println()
+		}
+	}
+	return nil
+}
+
+func runCachePurge(cmd *cobra.Command, args []string) error {
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+	purged, err := apiClient.PurgeCache(name)
+	if err != nil {
+		return fmt.Errorf("failed to purge cache: %w", err)
+	}
+	cmd.Printf("Removed %d cached result(s)\n", purged)
+	return nil
+}
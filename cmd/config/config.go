@@ -11,6 +11,9 @@ const ClientConfigFileName = ".mcpjungle.conf"
 // ClientConfig represents the MCPJungle client configuration stored in the user's home directory.
 type ClientConfig struct {
 	AccessToken string `yaml:"access_token"`
+
+	// McpClientAccessToken is the access token of an MCP client, used to connect to the MCP proxy.
+	McpClientAccessToken string `yaml:"mcp_client_access_token,omitempty"`
 }
 
 // AbsPath returns the absolute path to the client configuration file.
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
+	"os"
 	"strings"
+	"time"
 )
 
 var createCmd = &cobra.Command{
@@ -24,9 +27,115 @@ var createMcpClientCmd = &cobra.Command{
 	RunE: runCreateMcpClient,
 }
 
+var createOAuthApprovalCmd = &cobra.Command{
+	Use:   "oauth-approval [mcp-client]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Issue a one-time code to approve an OAuth authorization (Production mode)",
+	Long: "Issue a one-time approval code for an MCP client application that connects to the MCP proxy using OAuth.\n" +
+		"The user enters the code on MCPJungle's consent page to approve the application.\n" +
+		"The application then acts as the given MCP client and can only access the servers in its allow list.\n" +
+		"The code expires after a few minutes and can only be used once.\n" +
+		"This command is only available in Production mode.",
+	RunE: runCreateOAuthApproval,
+}
+
+var createRateLimitCmd = &cobra.Command{
+	Use:   "rate-limit [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create a rate limit or quota for tool calls",
+	Long: "Create a rate limit that restricts how often tools can be called via MCPJungle.\n" +
+		"A limit applies to exactly one of the following:\n" +
+		"  - all calls made by an MCP client (--client)\n" +
+		"  - calls made by an MCP client to a specific MCP server (--client & --server)\n" +
+		"  - all calls to a specific tool, regardless of the caller (--tool)\n" +
+		"A limit can combine a request rate (token bucket) with daily and monthly call quotas.\n" +
+		"Calls exceeding a limit are rejected with a retry-after hint.",
+	RunE: runCreateRateLimit,
+}
+
+var createGroupCmd = &cobra.Command{
+	Use:   "group [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create a tool group served on its own MCP endpoint",
+	Long: "Create a named group of tools picked across MCP servers.\n" +
+		"The group is served as a separate MCP server at /mcp/groups/<name> which only exposes the tools in it.\n" +
+		"This keeps the context of your agents small.\n" +
+		"A group includes the tools listed in --tools and all tools of the servers listed in --servers.\n" +
+		"With --selector, the group also includes all tools whose labels match the label selector (eg- team=data),\n" +
+		"including tools registered after the group was created.\n" +
+		"Disabled tools and tools of deregistered servers are removed from the group automatically.",
+	RunE: runCreateGroup,
+}
+
+var createToolOverrideCmd = &cobra.Command{
+	Use:   "tool-override [tool]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Customize how a tool is presented to MCP clients",
+	Long: "Give a tool an alias or override its description and the descriptions of its parameters.\n" +
+		"The tool is identified by its canonical name (eg- github__search_issues_and_pull_requests_v2).\n" +
+		"An alias is an alternate name under which the MCP proxy also exposes the tool (eg- search_issues).\n" +
+		"Overrides are stored separately from the tool definitions supplied by MCP servers, so they are preserved\n" +
+		"when a server is re-registered. Running this again for the same tool replaces its override.",
+	RunE: runCreateToolOverride,
+}
+
+var createArgumentPresetCmd = &cobra.Command{
+	Use:   "argument-preset [tool]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Pin arguments of a tool so that MCP clients cannot set them",
+	Long: "Pin the values of some input parameters of a tool (eg- the org of a github tool).\n" +
+		"The pinned parameters are removed from the tool's input schema shown to MCP clients and their values\n" +
+		"are injected into every call to the tool, replacing any values supplied by the client.\n" +
+		"Use --client to pin arguments for a single MCP client only. They take precedence over the arguments\n" +
+		"pinned for all clients. Running this again for the same tool and client replaces the preset.",
+	RunE: runCreateArgumentPreset,
+}
+
+var createCompositeToolCmd = &cobra.Command{
+	Use:   "composite-tool",
+	Short: "Create a tool implemented as a pipeline of existing tools",
+	Long: "Create a new tool whose implementation is a sequence of calls to the tools of registered MCP servers.\n" +
+		"The MCP proxy exposes it like any other tool, with its own name, description and input schema.\n" +
+		"The tool is defined in a JSON configuration file, eg-\n\n" +
+		`{
+  "name": "star_and_summarize",
+  "description": "Star a repository and summarize it",
+  "input_schema": {"type": "object", "properties": {"repo": {"type": "string"}}, "required": ["repo"]},
+  "steps": [
+    {"id": "repo", "tool": "github__get_repository", "arguments": {"name": "{{.input.repo}}"}},
+    {"id": "star", "tool": "github__star_repository", "arguments": {"id": "{{.steps.repo.id}}"}}
+  ],
+  "output": "Starred {{.steps.repo.full_name}}: {{.steps.repo.description}}"
+}` + "\n\nArguments and output are Go templates that can refer to the input and to the results of earlier steps.",
+	RunE: runCreateCompositeTool,
+}
+
 var (
+	createCompositeToolCmdConfigFilePath string
+
+	createArgumentPresetCmdArgs   []string
+	createArgumentPresetCmdClient string
+
+	createToolOverrideCmdAlias       string
+	createToolOverrideCmdDescription string
+	createToolOverrideCmdParams      []string
+
+	createGroupCmdTools       string
+	createGroupCmdServers     string
+	createGroupCmdDescription string
+	createGroupCmdSelector    string
+
 	createMcpClientCmdAllowedServers string
 	createMcpClientCmdDescription    string
+	createMcpClientCmdAllowLabels    []string
+
+	createRateLimitCmdClient       string
+	createRateLimitCmdServer       string
+	createRateLimitCmdTool         string
+	createRateLimitCmdRPM          int
+	createRateLimitCmdBurst        int
+	createRateLimitCmdDailyQuota   int
+	createRateLimitCmdMonthlyQuota int
 )
 
 func init() {
@@ -43,26 +152,123 @@ func init() {
 		"",
 		"Description of the MCP client. This is optional and can be used to provide additional context.",
 	)
+	createMcpClientCmd.Flags().StringArrayVar(
+		&createMcpClientCmdAllowLabels,
+		"allow-labels",
+		nil,
+		"Label selector of MCP servers that this client is allowed to access (eg- team=data) (can be repeated).\n"+
+			"The client can access the servers in --allow and all servers whose labels match any of the selectors.",
+	)
+
+	createRateLimitCmd.Flags().StringVar(&createRateLimitCmdClient, "client", "", "Name of the MCP client to limit")
+	createRateLimitCmd.Flags().StringVar(
+		&createRateLimitCmdServer,
+		"server",
+		"",
+		"Name of the MCP server to limit the client's calls to (requires --client)",
+	)
+	createRateLimitCmd.Flags().StringVar(
+		&createRateLimitCmdTool, "tool", "", "Canonical name of the tool to limit (eg- github__git_commit)",
+	)
+	createRateLimitCmd.Flags().IntVar(&createRateLimitCmdRPM, "rpm", 0, "Maximum number of calls per minute")
+	createRateLimitCmd.Flags().IntVar(
+		&createRateLimitCmdBurst,
+		"burst",
+		0,
+		"Maximum number of calls that can be made at once (defaults to --rpm)",
+	)
+	createRateLimitCmd.Flags().IntVar(&createRateLimitCmdDailyQuota, "daily", 0, "Maximum number of calls per day (UTC)")
+	createRateLimitCmd.Flags().IntVar(
+		&createRateLimitCmdMonthlyQuota, "monthly", 0, "Maximum number of calls per month (UTC)",
+	)
+
+	createGroupCmd.Flags().StringVar(
+		&createGroupCmdTools,
+		"tools",
+		"",
+		"Comma-separated list of canonical names of the tools to include (eg- github__git_commit)",
+	)
+	createGroupCmd.Flags().StringVar(
+		&createGroupCmdServers,
+		"servers",
+		"",
+		"Comma-separated list of MCP servers whose tools should all be included",
+	)
+	createGroupCmd.Flags().StringVar(&createGroupCmdDescription, "description", "", "Description of the group")
+	createGroupCmd.Flags().StringVar(
+		&createGroupCmdSelector,
+		"selector",
+		"",
+		"Label selector of the tools to include (eg- team=data,env!=prod)",
+	)
+
+	createToolOverrideCmd.Flags().StringVar(
+		&createToolOverrideCmdAlias, "alias", "", "Alternate name under which the tool is exposed by the proxy",
+	)
+	createToolOverrideCmd.Flags().StringVar(
+		&createToolOverrideCmdDescription, "description", "", "Description that replaces the tool's own description",
+	)
+	createToolOverrideCmd.Flags().StringArrayVar(
+		&createToolOverrideCmdParams,
+		"param",
+		nil,
+		"Description of an input parameter in the form 'name=description' (can be repeated)",
+	)
+
+	createArgumentPresetCmd.Flags().StringArrayVar(
+		&createArgumentPresetCmdArgs,
+		"arg",
+		nil,
+		"Pinned argument in the form 'name=value' (can be repeated).\n"+
+			"The value is parsed as JSON if possible (eg- 42, true, [\"a\"]), otherwise it is used as a string.",
+	)
+	createArgumentPresetCmd.Flags().StringVar(
+		&createArgumentPresetCmdClient, "client", "", "Name of the MCP client to pin the arguments for",
+	)
+
+	createCompositeToolCmd.Flags().StringVarP(
+		&createCompositeToolCmdConfigFilePath,
+		"conf",
+		"c",
+		"",
+		"Path to the JSON configuration file of the composite tool",
+	)
+	_ = createCompositeToolCmd.MarkFlagRequired("conf")
 
 	createCmd.AddCommand(createMcpClientCmd)
+	createCmd.AddCommand(createOAuthApprovalCmd)
+	createCmd.AddCommand(createToolOverrideCmd)
+	createCmd.AddCommand(createArgumentPresetCmd)
+	createCmd.AddCommand(createCompositeToolCmd)
+	createCmd.AddCommand(createGroupCmd)
+	createCmd.AddCommand(createRateLimitCmd)
 	rootCmd.AddCommand(createCmd)
 }
 
-func runCreateMcpClient(cmd *cobra.Command, args []string) error {
-	// convert the comma-separated list of allowed servers into a slice
-	allowList := make([]string, 0)
-	for _, s := range strings.Split(createMcpClientCmdAllowedServers, ",") {
+// splitCommaSeparated converts a comma-separated list supplied by the user into a slice, ignoring empty items.
+func splitCommaSeparated(v string) []string {
+	list := make([]string, 0)
+	for _, s := range strings.Split(v, ",") {
 		trimmed := strings.TrimSpace(s)
 		if trimmed != "" {
-			allowList = append(allowList, trimmed)
+			list = append(list, trimmed)
 		}
 	}
+	return list
+}
+
+func runCreateMcpClient(cmd *cobra.Command, args []string) error {
+	// convert the comma-separated list of allowed servers into a slice
+	allowList := splitCommaSeparated(createMcpClientCmdAllowedServers)
 
 	c := &types.McpClient{
 		Name:        args[0],
 		Description: createMcpClientCmdDescription,
 		AllowList:   allowList,
 	}
+	if len(createMcpClientCmdAllowLabels) > 0 {
+		c.AllowSelectors = createMcpClientCmdAllowLabels
+	}
 
 	token, err := apiClient.CreateMcpClient(c)
 	if err != nil {
@@ -76,7 +282,11 @@ func runCreateMcpClient(cmd *cobra.Command, args []string) error {
 
 	if len(c.AllowList) > 0 {
 		fmt.Println("Servers accessible: " + strings.Join(c.AllowList, ","))
-	} else {
+	}
+	if len(c.AllowSelectors) > 0 {
+		fmt.Println("Servers accessible by labels: " + strings.Join(c.AllowSelectors, " OR "))
+	}
+	if len(c.AllowList) == 0 && len(c.AllowSelectors) == 0 {
 		fmt.Println("This client does not have access to any MCP servers.")
 	}
 
@@ -85,3 +295,153 @@ func runCreateMcpClient(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runCreateOAuthApproval(cmd *cobra.Command, args []string) error {
+	a, err := apiClient.CreateOAuthApproval(&types.OAuthApprovalInput{McpClient: args[0]})
+	if err != nil {
+		return fmt.Errorf("failed to create approval code: %w", err)
+	}
+	fmt.Printf("Approval code for MCP client '%s': %s\n", a.McpClient, a.ApprovalCode)
+	fmt.Printf(
+		"Enter it on the consent page within %s to approve the application. It can only be used once.\n",
+		time.Duration(a.ExpiresIn)*time.Second,
+	)
+	return nil
+}
+
+func runCreateRateLimit(cmd *cobra.Command, args []string) error {
+	// infer the scope of the limit from the targets supplied by the user
+	var scope string
+	switch {
+	case createRateLimitCmdTool != "":
+		if createRateLimitCmdClient != "" || createRateLimitCmdServer != "" {
+			return fmt.Errorf("--tool cannot be combined with --client or --server")
+		}
+		scope = "tool"
+	case createRateLimitCmdClient != "" && createRateLimitCmdServer != "":
+		scope = "client_server"
+	case createRateLimitCmdClient != "":
+		scope = "client"
+	default:
+		return fmt.Errorf("either --client or --tool must be specified")
+	}
+
+	l := &types.RateLimit{
+		Name:              args[0],
+		Scope:             scope,
+		Client:            createRateLimitCmdClient,
+		Server:            createRateLimitCmdServer,
+		Tool:              createRateLimitCmdTool,
+		RequestsPerMinute: createRateLimitCmdRPM,
+		Burst:             createRateLimitCmdBurst,
+		DailyQuota:        createRateLimitCmdDailyQuota,
+		MonthlyQuota:      createRateLimitCmdMonthlyQuota,
+	}
+	if err := apiClient.CreateRateLimit(l); err != nil {
+		return fmt.Errorf("failed to create rate limit: %w", err)
+	}
+	fmt.Printf("Rate limit '%s' created successfully!\n", l.Name)
+	return nil
+}
+
+func runCreateGroup(cmd *cobra.Command, args []string) error {
+	g := &types.ToolGroup{
+		Name:            args[0],
+		Description:     createGroupCmdDescription,
+		IncludedTools:   splitCommaSeparated(createGroupCmdTools),
+		IncludedServers: splitCommaSeparated(createGroupCmdServers),
+		Selector:        createGroupCmdSelector,
+	}
+	if len(g.IncludedTools) == 0 && len(g.IncludedServers) == 0 && g.Selector == "" {
+		return fmt.Errorf("at least one of --tools, --servers or --selector must be specified")
+	}
+
+	created, err := apiClient.CreateToolGroup(g)
+	if err != nil {
+		return fmt.Errorf("failed to create tool group: %w", err)
+	}
+	fmt.Printf("Tool group '%s' created successfully!\n", created.Name)
+	fmt.Printf("Point your MCP client to %s%s to use it.\n", strings.TrimSuffix(registryServerURL, "/"), created.Endpoint)
+	return nil
+}
+
+func runCreateToolOverride(cmd *cobra.Command, args []string) error {
+	o := &types.ToolOverride{
+		Tool:        args[0],
+		Alias:       createToolOverrideCmdAlias,
+		Description: createToolOverrideCmdDescription,
+	}
+	for _, p := range createToolOverrideCmdParams {
+		name, desc, ok := strings.Cut(p, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid --param '%s', expected the form 'name=description'", p)
+		}
+		if o.ParameterDescriptions == nil {
+			o.ParameterDescriptions = make(map[string]string)
+		}
+		o.ParameterDescriptions[strings.TrimSpace(name)] = desc
+	}
+	if o.Alias == "" && o.Description == "" && len(o.ParameterDescriptions) == 0 {
+		return fmt.Errorf("at least one of --alias, --description or --param must be specified")
+	}
+
+	if err := apiClient.SetToolOverride(o); err != nil {
+		return fmt.Errorf("failed to create tool override: %w", err)
+	}
+	fmt.Printf("Override of tool '%s' saved successfully!\n", o.Tool)
+	if o.Alias != "" {
+		fmt.Printf("MCP clients can now also call it as '%s'.\n", o.Alias)
+	}
+	return nil
+}
+
+func runCreateArgumentPreset(cmd *cobra.Command, args []string) error {
+	p := &types.ArgumentPreset{
+		Tool:      args[0],
+		Client:    createArgumentPresetCmdClient,
+		Arguments: make(map[string]any),
+	}
+	for _, a := range createArgumentPresetCmdArgs {
+		name, raw, ok := strings.Cut(a, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid --arg '%s', expected the form 'name=value'", a)
+		}
+		var value any
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		p.Arguments[strings.TrimSpace(name)] = value
+	}
+	if len(p.Arguments) == 0 {
+		return fmt.Errorf("at least one --arg must be specified")
+	}
+
+	if err := apiClient.SetArgumentPreset(p); err != nil {
+		return fmt.Errorf("failed to create argument preset: %w", err)
+	}
+	if p.Client == "" {
+		fmt.Printf("Arguments of tool '%s' pinned for all MCP clients!\n", p.Tool)
+	} else {
+		fmt.Printf("Arguments of tool '%s' pinned for MCP client '%s'!\n", p.Tool, p.Client)
+	}
+	return nil
+}
+
+func runCreateCompositeTool(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(createCompositeToolCmdConfigFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", createCompositeToolCmdConfigFilePath, err)
+	}
+	var t types.CompositeTool
+	if err := json.Unmarshal(data, &t); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	created, err := apiClient.CreateCompositeTool(&t)
+	if err != nil {
+		return fmt.Errorf("failed to create composite tool: %w", err)
+	}
+	fmt.Printf("Composite tool '%s' created successfully!\n", created.Name)
+	fmt.Printf("It calls %d tools in sequence and is now available to MCP clients.\n", len(created.Steps))
+	return nil
+}
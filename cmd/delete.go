@@ -19,8 +19,60 @@ var deleteMcpClientCmd = &cobra.Command{
 	RunE: runDeleteMcpClient,
 }
 
+var deleteRateLimitCmd = &cobra.Command{
+	Use:   "rate-limit [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a rate limit",
+	Long:  "Delete a rate limit along with its usage counters. Calls covered by it are no longer restricted.",
+	RunE:  runDeleteRateLimit,
+}
+
+var deleteGroupCmd = &cobra.Command{
+	Use:   "group [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a tool group",
+	Long:  "Delete a tool group and stop serving its MCP endpoint. The tools themselves are not affected.",
+	RunE:  runDeleteGroup,
+}
+
+var deleteToolOverrideCmd = &cobra.Command{
+	Use:   "tool-override [tool]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete the override of a tool",
+	Long:  "Delete the alias and description overrides of a tool, so that it is presented as supplied by its MCP server.",
+	RunE:  runDeleteToolOverride,
+}
+
+var deleteCompositeToolCmd = &cobra.Command{
+	Use:   "composite-tool [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a composite tool",
+	Long:  "Delete a composite tool and remove it from the MCP proxy. The tools it calls are not affected.",
+	RunE:  runDeleteCompositeTool,
+}
+
+var deleteArgumentPresetCmd = &cobra.Command{
+	Use:   "argument-preset [tool]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete the argument preset of a tool",
+	Long: "Delete the arguments pinned for a tool, so that MCP clients supply them again.\n" +
+		"Without --client, the preset that applies to all clients is deleted.",
+	RunE: runDeleteArgumentPreset,
+}
+
+var deleteArgumentPresetCmdClient string
+
 func init() {
+	deleteArgumentPresetCmd.Flags().StringVar(
+		&deleteArgumentPresetCmdClient, "client", "", "Name of the MCP client whose preset should be deleted",
+	)
+
 	deleteCmd.AddCommand(deleteMcpClientCmd)
+	deleteCmd.AddCommand(deleteRateLimitCmd)
+	deleteCmd.AddCommand(deleteGroupCmd)
+	deleteCmd.AddCommand(deleteToolOverrideCmd)
+	deleteCmd.AddCommand(deleteArgumentPresetCmd)
+	deleteCmd.AddCommand(deleteCompositeToolCmd)
 	rootCmd.AddCommand(deleteCmd)
 }
 
@@ -32,3 +84,48 @@ func runDeleteMcpClient(cmd *cobra.Command, args []string) error {
 	fmt.Printf("MCP client '%s' deleted successfully (if it existed)!\n", name)
 	return nil
 }
+
+func runDeleteRateLimit(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteRateLimit(name); err != nil {
+		return fmt.Errorf("failed to delete the rate limit: %w", err)
+	}
+	fmt.Printf("Rate limit '%s' deleted successfully (if it existed)!\n", name)
+	return nil
+}
+
+func runDeleteGroup(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteToolGroup(name); err != nil {
+		return fmt.Errorf("failed to delete the tool group: %w", err)
+	}
+	fmt.Printf("Tool group '%s' deleted successfully (if it existed)!\n", name)
+	return nil
+}
+
+func runDeleteToolOverride(cmd *cobra.Command, args []string) error {
+	tool := args[0]
+	if err := apiClient.DeleteToolOverride(tool); err != nil {
+		return fmt.Errorf("failed to delete the tool override: %w", err)
+	}
+	fmt.Printf("Override of tool '%s' deleted successfully (if it existed)!\n", tool)
+	return nil
+}
+
+func runDeleteArgumentPreset(cmd *cobra.Command, args []string) error {
+	tool := args[0]
+	if err := apiClient.DeleteArgumentPreset(tool, deleteArgumentPresetCmdClient); err != nil {
+		return fmt.Errorf("failed to delete the argument preset: %w", err)
+	}
+	fmt.Printf("Argument preset of tool '%s' deleted successfully (if it existed)!\n", tool)
+	return nil
+}
+
+func runDeleteCompositeTool(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteCompositeTool(name); err != nil {
+		return fmt.Errorf("failed to delete the composite tool: %w", err)
+	}
+	fmt.Printf("Composite tool '%s' deleted successfully (if it existed)!\n", name)
+	return nil
+}
@@ -2,26 +2,47 @@ package cmd
 
 import "github.com/spf13/cobra"
 
+var disableCmdLabels string
+
 var disableCmd = &cobra.Command{
 	Use:   "disable [name]",
-	Args:  cobra.ExactArgs(1),
+	Args:  nameOrLabelsArgs(&disableCmdLabels),
 	Short: "Disable one or more MCP tools globally",
 	Long: "Specify the name of a tool or MCP server to disable it in the mcp proxy.\n" +
 		"If a server is specified, all tools provided by that server will be disabled.\n" +
+		"Alternatively, use --labels to disable all tools whose labels match a label selector (eg- env=staging).\n" +
 		"If a tool is disabled, it cannot be viewed or called by mcp clients.",
 	RunE: runDisableTools,
 }
 
 func init() {
+	disableCmd.Flags().StringVarP(
+		&disableCmdLabels,
+		"labels",
+		"l",
+		"",
+		"Disable all tools whose labels match this label selector instead of a named tool or server",
+	)
 	rootCmd.AddCommand(disableCmd)
 }
 
 func runDisableTools(cmd *cobra.Command, args []string) error {
-	name := args[0]
-	toolsDisabled, err := apiClient.DisableTools(name)
+	var (
+		toolsDisabled []string
+		err           error
+	)
+	if disableCmdLabels != "" {
+		toolsDisabled, err = apiClient.DisableToolsByLabels(disableCmdLabels)
+	} else {
+		toolsDisabled, err = apiClient.DisableTools(args[0])
+	}
 	if err != nil {
 		return err
 	}
+	if len(toolsDisabled) == 0 {
+		cmd.Println("No MCP tools needed to be disabled")
+		return nil
+	}
 	if len(toolsDisabled) == 1 {
 		cmd.Printf("MCP tool '%s' disabled successfully!\n", toolsDisabled[0])
 		return nil
@@ -5,26 +5,63 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var enableCmdLabels string
+
 var enableCmd = &cobra.Command{
 	Use:   "enable [name]",
-	Args:  cobra.ExactArgs(1),
+	Args:  nameOrLabelsArgs(&enableCmdLabels),
 	Short: "Enable one or more MCP tools globally",
 	Long: "Specify the name of a tool or MCP server to enable it in the mcp proxy.\n" +
 		"If a server is specified, all tools provided by that server will be enabled.\n" +
+		"Alternatively, use --labels to enable all tools whose labels match a label selector (eg- team=data).\n" +
 		"If a tool is enabled, it can be viewed and called by mcp clients.",
 	RunE: runEnableTools,
 }
 
 func init() {
+	enableCmd.Flags().StringVarP(
+		&enableCmdLabels,
+		"labels",
+		"l",
+		"",
+		"Enable all tools whose labels match this label selector instead of a named tool or server",
+	)
 	rootCmd.AddCommand(enableCmd)
 }
 
+// nameOrLabelsArgs validates that a command receives either a name argument or a label selector flag, not both.
+func nameOrLabelsArgs(labels *string) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if *labels != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("a name cannot be combined with --labels")
+			}
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	}
+}
+
 func runEnableTools(cmd *cobra.Command, args []string) error {
-	name := args[0]
-	toolsEnabled, err := apiClient.EnableTools(name)
+	var (
+		name         string
+		toolsEnabled []string
+		err          error
+	)
+	if enableCmdLabels != "" {
+		name = "tools with labels " + enableCmdLabels
+		toolsEnabled, err = apiClient.EnableToolsByLabels(enableCmdLabels)
+	} else {
+		name = args[0]
+		toolsEnabled, err = apiClient.EnableTools(name)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to enable %s: %w", name, err)
 	}
+	if len(toolsEnabled) == 0 {
+		cmd.Println("No MCP tools needed to be enabled")
+		return nil
+	}
 	if len(toolsEnabled) == 1 {
 		cmd.Printf("MCP tool '%s' enabled successfully!\n", toolsEnabled[0])
 		return nil
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/spf13/cobra"
+	"mime"
 	"os"
+	"path"
 	"time"
 )
 
@@ -14,7 +16,7 @@ var invokeCmdInput string
 var invokeToolCmd = &cobra.Command{
 	Use:   "invoke <name>",
 	Short: "Invoke a tool",
-	Long:  "Invokes a tool supplied by a registered MCP server",
+	Long:  "Invokes a tool supplied by a registered MCP server, or a composite tool",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runInvokeTool,
 }
@@ -92,6 +94,41 @@ func getAudioContent(c map[string]any) ([]byte, string, error) {
 	return audioData, ext, nil
 }
 
+// getEmbeddedResource returns the contents of an embedded resource and a file extension for it.
+func getEmbeddedResource(c map[string]any) ([]byte, string, error) {
+	resource, ok := c["resource"].(map[string]any)
+	if !ok {
+		return nil, "", fmt.Errorf("resource content item does not have a valid 'resource' field: %v", c)
+	}
+	uri, _ := resource["uri"].(string)
+
+	var data []byte
+	if text, ok := resource["text"].(string); ok {
+		data = []byte(text)
+	} else if blob, ok := resource["blob"].(string); ok {
+		var err error
+		data, err = base64.StdEncoding.DecodeString(blob)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to decode base64 resource data of %s: %w", uri, err)
+		}
+	} else {
+		return nil, "", fmt.Errorf("resource %s has neither 'text' nor 'blob' contents", uri)
+	}
+
+	// Determine file extension from the resource URI, falling back to the MIME type
+	ext := path.Ext(uri)
+	if ext == "" || len(ext) > 10 {
+		ext = ".bin"
+		if mimeType, _ := resource["mimeType"].(string); mimeType != "" {
+			if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+				ext = exts[0]
+			}
+		}
+	}
+
+	return data, ext, nil
+}
+
 func runInvokeTool(cmd *cobra.Command, args []string) error {
 	var input map[string]any
 	if err := json.Unmarshal([]byte(invokeCmdInput), &input); err != nil {
@@ -151,7 +188,50 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("failed to write audio to disk: %w", err)
 			}
 			fmt.Printf("[Audio saved as %s]\n", filename)
+
+		case "resource":
+			data, ext, err := getEmbeddedResource(c)
+			if err != nil {
+				return err
+			}
+			filename := fmt.Sprintf("resource_%d%s", time.Now().UnixNano(), ext)
+			if err := os.WriteFile(filename, data, 0644); err != nil {
+				return fmt.Errorf("failed to write resource to disk: %w", err)
+			}
+			if r, ok := c["resource"].(map[string]any); ok && r["uri"] != nil {
+				fmt.Printf("[Resource %v saved as %s]\n", r["uri"], filename)
+			} else {
+				fmt.Printf("[Resource saved as %s]\n", filename)
+			}
+
+		case "resource_link":
+			fmt.Printf("Link to resource: %v\n", c["uri"])
+			if name, ok := c["name"].(string); ok && name != "" {
+				fmt.Println("Name: " + name)
+			}
+			if desc, ok := c["description"].(string); ok && desc != "" {
+				fmt.Println("Description: " + desc)
+			}
+
+		default:
+			// print content types unknown to this version of the CLI as-is
+			j, err := json.MarshalIndent(c, "", "  ")
+			if err != nil {
+				fmt.Println(c)
+			} else {
+				fmt.Println(string(j))
+			}
+		}
+	}
+
+	if result.StructuredContent != nil {
+		fmt.Println()
+		fmt.Println("[Structured content]")
+		j, err := json.MarshalIndent(result.StructuredContent, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to render structured content: %w", err)
 		}
+		fmt.Println(string(j))
 	}
 
 	return nil
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Add, change or remove labels of MCP servers and tools",
+	Long: "Labels are key/value pairs attached to MCP servers and tools (eg- team=data, env=prod).\n" +
+		"A tool inherits the labels of its MCP server, its own labels take precedence.\n" +
+		"Labels can be used to filter lists and to enable, disable, group or grant access to tools in bulk.",
+}
+
+var labelServerCmd = &cobra.Command{
+	Use:   "server [name] [key=value | key-]...",
+	Args:  cobra.MinimumNArgs(2),
+	Short: "Add, change or remove labels of an MCP server",
+	Long: "Set labels of an MCP server with 'key=value' and remove them with 'key-', eg-\n" +
+		"  mcpjungle label server github team=platform env-",
+	RunE: runLabelServer,
+}
+
+var labelToolCmd = &cobra.Command{
+	Use:   "tool [name] [key=value | key-]...",
+	Args:  cobra.MinimumNArgs(2),
+	Short: "Add, change or remove labels of a tool",
+	Long: "Set labels of a tool with 'key=value' and remove them with 'key-', eg-\n" +
+		"  mcpjungle label tool github__create_issue risk=write",
+	RunE: runLabelTool,
+}
+
+func init() {
+	labelCmd.AddCommand(labelServerCmd)
+	labelCmd.AddCommand(labelToolCmd)
+	rootCmd.AddCommand(labelCmd)
+}
+
+// parseLabelUpdates parses label changes supplied in the form 'key=value' (set) or 'key-' (remove).
+func parseLabelUpdates(args []string) (map[string]*string, error) {
+	update := make(map[string]*string, len(args))
+	for _, a := range args {
+		if key, value, ok := strings.Cut(a, "="); ok {
+			if strings.TrimSpace(key) == "" {
+				return nil, fmt.Errorf("invalid label '%s', expected the form 'key=value' or 'key-'", a)
+			}
+			v := strings.TrimSpace(value)
+			update[strings.TrimSpace(key)] = &v
+			continue
+		}
+		key, ok := strings.CutSuffix(a, "-")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid label '%s', expected the form 'key=value' or 'key-'", a)
+		}
+		update[strings.TrimSpace(key)] = nil
+	}
+	return update, nil
+}
+
+func runLabelServer(cmd *cobra.Command, args []string) error {
+	update, err := parseLabelUpdates(args[1:])
+	if err != nil {
+		return err
+	}
+	labels, err := apiClient.UpdateServerLabels(args[0], update)
+	if err != nil {
+		return fmt.Errorf("failed to update labels of server %s: %w", args[0], err)
+	}
+	printLabels(args[0], labels)
+	return nil
+}
+
+func runLabelTool(cmd *cobra.Command, args []string) error {
+	update, err := parseLabelUpdates(args[1:])
+	if err != nil {
+		return err
+	}
+	labels, err := apiClient.UpdateToolLabels(args[0], update)
+	if err != nil {
+		return fmt.Errorf("failed to update labels of tool %s: %w", args[0], err)
+	}
+	printLabels(args[0], labels)
+	return nil
+}
+
+func printLabels(name string, labels map[string]string) {
+	if len(labels) == 0 {
+		fmt.Printf("%s has no labels\n", name)
+		return
+	}
+	fmt.Printf("Labels of %s: %s\n", name, strings.Join(types.FormatLabels(labels), ", "))
+}
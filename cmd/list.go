@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -12,7 +15,16 @@ var listCmd = &cobra.Command{
 	Short: "List resources",
 }
 
-var listToolsCmdServerName string
+var (
+	listToolsCmdServerName string
+	listToolsCmdLabels     string
+	listToolsCmdSort       string
+
+	listServersCmdLabels string
+	listServersCmdSort   string
+
+	listMcpClientsCmdSort string
+)
 
 var listToolsCmd = &cobra.Command{
 	Use:   "tools",
@@ -35,6 +47,36 @@ var listMcpClientsCmd = &cobra.Command{
 	RunE: runListMcpClients,
 }
 
+var listRateLimitsCmd = &cobra.Command{
+	Use:   "rate-limits",
+	Short: "List rate limits and their current usage",
+	RunE:  runListRateLimits,
+}
+
+var listGroupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "List tool groups",
+	RunE:  runListGroups,
+}
+
+var listToolOverridesCmd = &cobra.Command{
+	Use:   "tool-overrides",
+	Short: "List tool aliases and description overrides",
+	RunE:  runListToolOverrides,
+}
+
+var listArgumentPresetsCmd = &cobra.Command{
+	Use:   "argument-presets",
+	Short: "List the arguments pinned for tools",
+	RunE:  runListArgumentPresets,
+}
+
+var listCompositeToolsCmd = &cobra.Command{
+	Use:   "composite-tools",
+	Short: "List composite tools along with their steps",
+	RunE:  runListCompositeTools,
+}
+
 func init() {
 	listToolsCmd.Flags().StringVar(
 		&listToolsCmdServerName,
@@ -42,33 +84,75 @@ func init() {
 		"",
 		"Filter tools by server name",
 	)
+	listToolsCmd.Flags().StringVarP(
+		&listToolsCmdLabels,
+		"labels",
+		"l",
+		"",
+		"Filter tools by label selector (eg- team=data,env!=prod)",
+	)
+	listServersCmd.Flags().StringVarP(
+		&listServersCmdLabels,
+		"labels",
+		"l",
+		"",
+		"Filter servers by label selector (eg- team=data,env!=prod)",
+	)
+	listToolsCmd.Flags().StringVar(
+		&listToolsCmdSort,
+		"sort",
+		"name",
+		"Sort tools by 'name' or 'created_at', prefix with '-' for descending order",
+	)
+	listServersCmd.Flags().StringVar(
+		&listServersCmdSort,
+		"sort",
+		"name",
+		"Sort servers by 'name' or 'created_at', prefix with '-' for descending order",
+	)
+	listMcpClientsCmd.Flags().StringVar(
+		&listMcpClientsCmdSort,
+		"sort",
+		"name",
+		"Sort clients by 'name' or 'created_at', prefix with '-' for descending order",
+	)
 
 	listCmd.AddCommand(listToolsCmd)
 	listCmd.AddCommand(listServersCmd)
 	listCmd.AddCommand(listMcpClientsCmd)
+	listCmd.AddCommand(listRateLimitsCmd)
+	listCmd.AddCommand(listGroupsCmd)
+	listCmd.AddCommand(listToolOverridesCmd)
+	listCmd.AddCommand(listArgumentPresetsCmd)
+	listCmd.AddCommand(listCompositeToolsCmd)
 
 	rootCmd.AddCommand(listCmd)
 }
 
 func runListTools(cmd *cobra.Command, args []string) error {
-	tools, err := apiClient.ListTools(listToolsCmdServerName)
-	if err != nil {
-		return fmt.Errorf("failed to list tools: %w", err)
-	}
-
-	if len(tools) == 0 {
-		fmt.Println("There are no tools in the registry")
-		return nil
-	}
-	for i, t := range tools {
+	filter := types.ToolFilter{Server: listToolsCmdServerName, Labels: listToolsCmdLabels}
+	// tools are fetched page by page, so that large registries are listed as they are fetched
+	i := 0
+	for t, err := range apiClient.AllTools(filter, types.ListOptions{Sort: listToolsCmdSort}) {
+		if err != nil {
+			return fmt.Errorf("failed to list tools: %w", err)
+		}
+		i++
 		ed := "ENABLED"
 		if !t.Enabled {
 			ed = "DISABLED"
 		}
-		fmt.Printf("%d. %s  [%s]\n", i+1, t.Name, ed)
+		fmt.Printf("%d. %s  [%s]\n", i, t.Name, ed)
+		if len(t.Labels) > 0 {
+			fmt.Println("Labels: " + strings.Join(types.FormatLabels(t.Labels), ", "))
+		}
 		fmt.Println(t.Description)
 		fmt.Println()
 	}
+	if i == 0 {
+		fmt.Println("There are no tools in the registry")
+		return nil
+	}
 
 	fmt.Println("Run 'usage <tool name>' to see a tool's usage or 'invoke <tool name>' to call one")
 
@@ -76,7 +160,7 @@ func runListTools(cmd *cobra.Command, args []string) error {
 }
 
 func runListServers(cmd *cobra.Command, args []string) error {
-	servers, err := apiClient.ListServers()
+	servers, _, err := apiClient.ListServersPage(listServersCmdLabels, types.ListOptions{Sort: listServersCmdSort})
 	if err != nil {
 		return fmt.Errorf("failed to list servers: %w", err)
 	}
@@ -94,9 +178,51 @@ func runListServers(cmd *cobra.Command, args []string) error {
 
 		fmt.Println("Transport: " + s.Transport)
 
+		if len(s.Labels) > 0 {
+			fmt.Println("Labels: " + strings.Join(types.FormatLabels(s.Labels), ", "))
+		}
+
+		if s.Status != nil && s.Status.Health != types.HealthUnknown {
+			if s.Status.LastError != "" {
+				fmt.Printf("Health: %s (%s)\n", s.Status.Health, s.Status.LastError)
+			} else {
+				fmt.Printf("Health: %s\n", s.Status.Health)
+			}
+		}
+		if s.Status != nil && s.Status.CircuitBreaker != "" && s.Status.CircuitBreaker != types.CircuitClosed {
+			fmt.Printf("Circuit breaker: %s\n", s.Status.CircuitBreaker)
+		}
+		if s.Policy != nil && s.Policy.MaxConcurrency > 0 && s.Status != nil {
+			fmt.Printf(
+				"Calls: %d/%d in progress, %d queued\n",
+				s.Status.ActiveCalls, s.Policy.MaxConcurrency, s.Status.QueuedCalls,
+			)
+		}
+
 		t, _ := types.ValidateTransport(s.Transport)
-		if t == types.TransportStreamableHTTP {
+		if t == types.TransportStreamableHTTP || t == types.TransportSSE || t == types.TransportOpenAPI {
 			fmt.Println("URL: " + s.URL)
+			if s.SpecURL != "" {
+				fmt.Println("OpenAPI spec: " + s.SpecURL)
+			}
+			if len(s.Headers) > 0 {
+				names := slices.Sorted(maps.Keys(s.Headers))
+				fmt.Println("Custom headers: " + strings.Join(names, ", "))
+			}
+			if s.TLS != nil {
+				if s.TLS.ClientCert != "" {
+					fmt.Println("Mutual TLS: enabled")
+				}
+				if s.TLS.CACert != "" {
+					fmt.Println("Server certificate verified with: custom CA bundle")
+				}
+				if s.TLS.InsecureSkipVerify {
+					fmt.Println("Server certificate verification: DISABLED")
+				}
+			}
+			if s.OAuth != nil {
+				fmt.Printf("OAuth: %s grant (client ID %s)\n", s.OAuth.GrantType, s.OAuth.ClientID)
+			}
 		} else {
 			if len(s.Args) > 0 {
 				fmt.Println("Command: " + s.Command + " " + strings.Join(s.Args, " "))
@@ -118,7 +244,7 @@ func runListServers(cmd *cobra.Command, args []string) error {
 }
 
 func runListMcpClients(cmd *cobra.Command, args []string) error {
-	clients, err := apiClient.ListMcpClients()
+	clients, _, err := apiClient.ListMcpClientsPage(types.ListOptions{Sort: listMcpClientsCmdSort})
 	if err != nil {
 		return fmt.Errorf("failed to list MCP clients: %w", err)
 	}
@@ -136,7 +262,11 @@ func runListMcpClients(cmd *cobra.Command, args []string) error {
 
 		if len(c.AllowList) > 0 {
 			fmt.Println("Allowed servers: " + strings.Join(c.AllowList, ","))
-		} else {
+		}
+		if len(c.AllowSelectors) > 0 {
+			fmt.Println("Allowed servers by labels: " + strings.Join(c.AllowSelectors, " OR "))
+		}
+		if len(c.AllowList) == 0 && len(c.AllowSelectors) == 0 {
 			fmt.Println("This client does not have access to any MCP servers.")
 		}
 
@@ -147,3 +277,157 @@ func runListMcpClients(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runListRateLimits(cmd *cobra.Command, args []string) error {
+	limits, err := apiClient.ListRateLimits()
+	if err != nil {
+		return fmt.Errorf("failed to list rate limits: %w", err)
+	}
+
+	if len(limits) == 0 {
+		fmt.Println("There are no rate limits in the registry")
+		return nil
+	}
+	for i, l := range limits {
+		fmt.Printf("%d. %s\n", i+1, l.Name)
+
+		switch l.Scope {
+		case "tool":
+			fmt.Println("Applies to: calls to tool " + l.Tool)
+		case "client_server":
+			fmt.Printf("Applies to: calls by client %s to server %s\n", l.Client, l.Server)
+		default:
+			fmt.Println("Applies to: calls by client " + l.Client)
+		}
+
+		if l.RequestsPerMinute > 0 {
+			burst := l.Burst
+			if burst == 0 {
+				burst = l.RequestsPerMinute
+			}
+			fmt.Printf("Rate: %d calls per minute (burst %d)\n", l.RequestsPerMinute, burst)
+		}
+		if l.Usage != nil {
+			if l.DailyQuota > 0 {
+				fmt.Printf("Daily quota: %d / %d\n", l.Usage.Today, l.DailyQuota)
+			} else {
+				fmt.Printf("Calls today: %d\n", l.Usage.Today)
+			}
+			if l.MonthlyQuota > 0 {
+				fmt.Printf("Monthly quota: %d / %d\n", l.Usage.ThisMonth, l.MonthlyQuota)
+			} else {
+				fmt.Printf("Calls this month: %d\n", l.Usage.ThisMonth)
+			}
+		}
+
+		if i < len(limits)-1 {
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+func runListGroups(cmd *cobra.Command, args []string) error {
+	groups, err := apiClient.ListToolGroups()
+	if err != nil {
+		return fmt.Errorf("failed to list tool groups: %w", err)
+	}
+
+	if len(groups) == 0 {
+		fmt.Println("There are no tool groups in the registry")
+		return nil
+	}
+	for i, g := range groups {
+		fmt.Printf("%d. %s\n", i+1, g.Name)
+		if g.Description != "" {
+			fmt.Println(g.Description)
+		}
+		fmt.Println("Endpoint: " + g.Endpoint)
+		if len(g.IncludedTools) > 0 {
+			fmt.Println("Tools: " + strings.Join(g.IncludedTools, ", "))
+		}
+		if len(g.IncludedServers) > 0 {
+			fmt.Println("All tools of servers: " + strings.Join(g.IncludedServers, ", "))
+		}
+		if g.Selector != "" {
+			fmt.Println("All tools with labels: " + g.Selector)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runListToolOverrides(cmd *cobra.Command, args []string) error {
+	overrides, err := apiClient.ListToolOverrides()
+	if err != nil {
+		return fmt.Errorf("failed to list tool overrides: %w", err)
+	}
+
+	if len(overrides) == 0 {
+		fmt.Println("There are no tool overrides in the registry")
+		return nil
+	}
+	for i, o := range overrides {
+		fmt.Printf("%d. %s\n", i+1, o.Tool)
+		if o.Alias != "" {
+			fmt.Println("Alias: " + o.Alias)
+		}
+		if o.Description != "" {
+			fmt.Println("Description: " + o.Description)
+		}
+		for _, p := range slices.Sorted(maps.Keys(o.ParameterDescriptions)) {
+			fmt.Printf("Parameter %s: %s\n", p, o.ParameterDescriptions[p])
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runListArgumentPresets(cmd *cobra.Command, args []string) error {
+	presets, err := apiClient.ListArgumentPresets()
+	if err != nil {
+		return fmt.Errorf("failed to list argument presets: %w", err)
+	}
+
+	if len(presets) == 0 {
+		fmt.Println("There are no argument presets in the registry")
+		return nil
+	}
+	for i, p := range presets {
+		fmt.Printf("%d. %s\n", i+1, p.Tool)
+		if p.Client == "" {
+			fmt.Println("Client: all")
+		} else {
+			fmt.Println("Client: " + p.Client)
+		}
+		for _, name := range slices.Sorted(maps.Keys(p.Arguments)) {
+			v, _ := json.Marshal(p.Arguments[name])
+			fmt.Printf("%s = %s\n", name, v)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runListCompositeTools(cmd *cobra.Command, args []string) error {
+	tools, err := apiClient.ListCompositeTools()
+	if err != nil {
+		return fmt.Errorf("failed to list composite tools: %w", err)
+	}
+
+	if len(tools) == 0 {
+		fmt.Println("There are no composite tools in the registry")
+		return nil
+	}
+	for i, t := range tools {
+		fmt.Printf("%d. %s\n", i+1, t.Name)
+		if t.Description != "" {
+			fmt.Println(t.Description)
+		}
+		for j, s := range t.Steps {
+			fmt.Printf("  step %d (%s): %s\n", j+1, s.ID, s.Tool)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/spf13/cobra"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var (
+	proxyCmdStdio       bool
+	proxyCmdAccessToken string
+)
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Run a local MCP server that forwards to the MCPJungle proxy",
+	Long: "Runs a local MCP server that forwards all tools, prompts and resources to the MCP proxy of the " +
+		"MCPJungle registry server.\n" +
+		"This is useful for MCP clients that only support STDIO MCP servers.\n\n" +
+		"In Production mode, the MCP client access token is read from the 'mcp_client_access_token' field of " +
+		config.ClientConfigFileName + " in your home directory, unless it is passed using --access-token.",
+	RunE: runProxy,
+}
+
+func init() {
+	proxyCmd.Flags().BoolVar(
+		&proxyCmdStdio,
+		"stdio",
+		false,
+		"Serve the MCP proxy over STDIO",
+	)
+	proxyCmd.Flags().StringVar(
+		&proxyCmdAccessToken,
+		"access-token",
+		"",
+		"Access token of the MCP client to connect to the MCP proxy with (Production mode)",
+	)
+
+	rootCmd.AddCommand(proxyCmd)
+}
+
+func runProxy(cmd *cobra.Command, args []string) error {
+	if !proxyCmdStdio {
+		return errors.New("only STDIO is supported currently, use the --stdio flag")
+	}
+
+	// stdout is reserved for MCP messages, so all logs must go to stderr
+	log.SetOutput(os.Stderr)
+
+	token := proxyCmdAccessToken
+	if token == "" {
+		token = config.Load().McpClientAccessToken
+	}
+	proxyURL, err := url.JoinPath(registryServerURL, "/mcp")
+	if err != nil {
+		return fmt.Errorf("invalid registry URL: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	remote, err := connectToMcpProxy(ctx, proxyURL, token)
+	if err != nil {
+		return err
+	}
+	defer remote.Close()
+
+	local, err := newLocalProxyServer(ctx, remote)
+	if err != nil {
+		return err
+	}
+
+	stdioServer := server.NewStdioServer(local)
+	if err := stdioServer.Listen(ctx, os.Stdin, os.Stdout); err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("stdio MCP server failed: %w", err)
+	}
+	return nil
+}
+
+// connectToMcpProxy opens a session with the MCP proxy of the registry server.
+func connectToMcpProxy(ctx context.Context, proxyURL, token string) (*client.Client, error) {
+	// continuous listening lets the proxy notify us when its list of tools changes
+	opts := []transport.StreamableHTTPCOption{transport.WithContinuousListening()}
+	if token != "" {
+		opts = append(opts, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}))
+	}
+	t, err := transport.NewStreamableHTTP(proxyURL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for the MCP proxy: %w", err)
+	}
+	c := client.NewClient(t)
+	if err := c.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start client for the MCP proxy: %w", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "mcpjungle stdio proxy",
+		Version: Version,
+	}
+	if _, err := c.Initialize(ctx, initRequest); err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("failed to connect to the MCP proxy at %s: %w", proxyURL, err)
+	}
+	return c, nil
+}
+
+// newLocalProxyServer creates an MCP server that exposes everything the remote MCP proxy offers
+// and forwards all calls to it.
+func newLocalProxyServer(ctx context.Context, remote *client.Client) (*server.MCPServer, error) {
+	capabilities := remote.GetServerCapabilities()
+
+	var opts []server.ServerOption
+	if capabilities.Tools != nil {
+		opts = append(opts, server.WithToolCapabilities(true))
+	}
+	if capabilities.Prompts != nil {
+		opts = append(opts, server.WithPromptCapabilities(false))
+	}
+	if capabilities.Resources != nil {
+		opts = append(opts, server.WithResourceCapabilities(false, false))
+	}
+	local := server.NewMCPServer("mcpjungle stdio proxy", Version, opts...)
+
+	if capabilities.Tools != nil {
+		if err := syncProxyTools(ctx, remote, local); err != nil {
+			return nil, err
+		}
+		remote.OnNotification(func(n mcp.JSONRPCNotification) {
+			if n.Method != mcp.MethodNotificationToolsListChanged {
+				return
+			}
+			if err := syncProxyTools(ctx, remote, local); err != nil {
+				log.Printf("[ERROR] failed to refresh tools from the MCP proxy: %v", err)
+			}
+		})
+	}
+
+	if capabilities.Prompts != nil {
+		prompts, err := remote.ListPrompts(ctx, mcp.ListPromptsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list prompts of the MCP proxy: %w", err)
+		}
+		for _, p := range prompts.Prompts {
+			local.AddPrompt(p, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				return remote.GetPrompt(ctx, request)
+			})
+		}
+	}
+
+	if capabilities.Resources != nil {
+		readResource := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			result, err := remote.ReadResource(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			return result.Contents, nil
+		}
+
+		resources, err := remote.ListResources(ctx, mcp.ListResourcesRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources of the MCP proxy: %w", err)
+		}
+		for _, r := range resources.Resources {
+			local.AddResource(r, readResource)
+		}
+
+		templates, err := remote.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resource templates of the MCP proxy: %w", err)
+		}
+		for _, t := range templates.ResourceTemplates {
+			local.AddResourceTemplate(t, readResource)
+		}
+	}
+
+	return local, nil
+}
+
+// syncProxyTools replaces the tools of the local server with the ones currently offered by the MCP proxy.
+func syncProxyTools(ctx context.Context, remote *client.Client, local *server.MCPServer) error {
+	result, err := remote.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to list tools of the MCP proxy: %w", err)
+	}
+
+	callTool := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return remote.CallTool(ctx, request)
+	}
+	tools := make([]server.ServerTool, 0, len(result.Tools))
+	for _, t := range result.Tools {
+		tools = append(tools, server.ServerTool{Tool: t, Handler: callTool})
+	}
+	local.SetTools(tools...)
+	return nil
+}
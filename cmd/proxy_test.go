@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLocalProxyServerSyncsTools(t *testing.T) {
+	remoteServer := server.NewMCPServer("mcpjungle", "0.0.1", server.WithToolCapabilities(true))
+	echo := func(ctx context.Context, r mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(r.Params.Name), nil
+	}
+	remoteServer.AddTool(mcp.NewTool("github__create_issue"), echo)
+	ts := httptest.NewServer(server.NewStreamableHTTPServer(remoteServer))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	remote, err := connectToMcpProxy(ctx, ts.URL+"/mcp", "")
+	if err != nil {
+		t.Fatalf("failed to connect to the MCP proxy: %v", err)
+	}
+	defer remote.Close()
+
+	local, err := newLocalProxyServer(ctx, remote)
+	if err != nil {
+		t.Fatalf("failed to create the local proxy server: %v", err)
+	}
+
+	// calls to the tools of the local server are forwarded to the MCP proxy
+	tool := local.GetTool("github__create_issue")
+	if tool == nil {
+		t.Fatalf("expected the tools of the MCP proxy to be served locally")
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "github__create_issue"
+	result, err := tool.Handler(ctx, request)
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "github__create_issue" {
+		t.Errorf("unexpected result: %+v", result.Content)
+	}
+
+	// the local server follows changes to the tools of the MCP proxy
+	remoteServer.AddTool(mcp.NewTool("slack__post_message"), echo)
+	remoteServer.DeleteTools("github__create_issue")
+	deadline := time.Now().Add(5 * time.Second)
+	for local.GetTool("slack__post_message") == nil || local.GetTool("github__create_issue") != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the tools to be re-synced after tools/list_changed, got %v", local.ListTools())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
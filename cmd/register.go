@@ -6,13 +6,29 @@ import (
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
 	"os"
+	"strings"
 )
 
 var (
 	registerCmdServerName  string
 	registerCmdServerURL   string
+	registerCmdSpec        string
+	registerCmdTransport   string
 	registerCmdServerDesc  string
 	registerCmdBearerToken string
+	registerCmdInitTimeout int
+	registerCmdCallTimeout int
+
+	registerCmdMaxConcurrency int
+
+	registerCmdHeaders            []string
+	registerCmdLabels             []string
+	registerCmdClientCertPath     string
+	registerCmdClientKeyPath      string
+	registerCmdCACertPath         string
+	registerCmdInsecureSkipVerify bool
+
+	registerCmdOAuthCallbackPort int
 
 	registerCmdServerConfigFilePath string
 )
@@ -23,7 +39,7 @@ var registerMCPServerCmd = &cobra.Command{
 	Long: "Register a MCP Server with the registry.\n" +
 		"The recommended way is to specify the json configuration file for your server.\n" +
 		"A config file is required if you want to register an stdio-based mcp server.\n" +
-		"The flags only allow you to register a streamable http server.\n" +
+		"The flags only allow you to register a streamable http, sse or openapi server.\n" +
 		"\nNOTE: A server's name is unique across mcpjungle and must not contain\nany whitespaces, special characters or multiple consecutive underscores '__'.",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		// Skip flag validation if config file is provided
@@ -34,6 +50,20 @@ var registerMCPServerCmd = &cobra.Command{
 		if registerCmdServerName == "" {
 			return fmt.Errorf("either supply a configuration file or set the required flag \"name\"")
 		}
+		t, err := types.ValidateTransport(registerCmdTransport)
+		if err != nil {
+			return err
+		}
+		if t == types.TransportStdio {
+			return fmt.Errorf("a configuration file is required to register a stdio server")
+		}
+		if t == types.TransportOpenAPI {
+			// the base url of an openapi server is optional, it defaults to the one declared in the spec
+			if registerCmdSpec == "" {
+				return fmt.Errorf("required flag \"spec\" not set")
+			}
+			return nil
+		}
 		if registerCmdServerURL == "" {
 			return fmt.Errorf("required flag \"url\" not set")
 		}
@@ -53,7 +83,21 @@ func init() {
 		&registerCmdServerURL,
 		"url",
 		"",
-		"URL of the streamable http MCP server (eg- http://localhost:8000/mcp)",
+		"URL of the streamable http MCP server (eg- http://localhost:8000/mcp) or SSE endpoint of the sse MCP server."+
+			" For an openapi server, this is the base URL of the API (default: the first server in the spec)",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdTransport,
+		"transport",
+		string(types.TransportStreamableHTTP),
+		"Transport used by the MCP server, either 'streamable_http', 'sse' or 'openapi'",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdSpec,
+		"spec",
+		"",
+		"URL or local path of the OpenAPI 3 spec of an openapi server."+
+			" Each operation of the API is exposed as a tool.",
 	)
 	registerMCPServerCmd.Flags().StringVar(
 		&registerCmdServerDesc,
@@ -68,6 +112,68 @@ func init() {
 		"If provided, MCPJungle will use this token to authenticate with the http MCP server for all requests."+
 			" This is useful if the MCP server requires static tokens (eg- your API token) for authentication.",
 	)
+	registerMCPServerCmd.Flags().IntVar(
+		&registerCmdInitTimeout,
+		"init-timeout",
+		0,
+		"Timeout (in seconds) for the initialization request to the MCP server (default 10)",
+	)
+	registerMCPServerCmd.Flags().IntVar(
+		&registerCmdCallTimeout,
+		"call-timeout",
+		0,
+		"Timeout (in seconds) for a tool call to the MCP server (default 300)",
+	)
+	registerMCPServerCmd.Flags().IntVar(
+		&registerCmdMaxConcurrency,
+		"max-concurrency",
+		0,
+		"Maximum number of tool calls in progress on the MCP server at once, further calls are queued (default unlimited)",
+	)
+	registerMCPServerCmd.Flags().StringArrayVar(
+		&registerCmdHeaders,
+		"header",
+		nil,
+		"Custom header to send in all requests to the http MCP server, in the form 'Name: value' (can be repeated)",
+	)
+	registerMCPServerCmd.Flags().StringArrayVarP(
+		&registerCmdLabels,
+		"label",
+		"l",
+		nil,
+		"Label to attach to the MCP server and its tools, in the form 'key=value' (can be repeated)",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdClientCertPath,
+		"client-cert",
+		"",
+		"Path to a PEM-encoded client certificate for mutual TLS with the http MCP server",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdClientKeyPath,
+		"client-key",
+		"",
+		"Path to the PEM-encoded private key of the client certificate",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdCACertPath,
+		"ca-cert",
+		"",
+		"Path to a PEM-encoded CA bundle used to verify the http MCP server's certificate",
+	)
+	registerMCPServerCmd.Flags().BoolVar(
+		&registerCmdInsecureSkipVerify,
+		"insecure-skip-verify",
+		false,
+		"Do not verify the http MCP server's certificate (only use this for development)",
+	)
+	registerMCPServerCmd.Flags().IntVar(
+		&registerCmdOAuthCallbackPort,
+		"oauth-callback-port",
+		0,
+		"Local port to receive the OAuth authorization response on, if the server uses the authorization_code grant"+
+			" (default: a random free port)",
+	)
 	registerMCPServerCmd.Flags().StringVarP(
 		&registerCmdServerConfigFilePath,
 		"conf",
@@ -96,6 +202,84 @@ func readMcpServerConfig(filePath string) (types.RegisterServerInput, error) {
 	return input, nil
 }
 
+// parseHeaderFlags parses headers supplied in the form 'Name: value'.
+func parseHeaderFlags(flags []string) (map[string]string, error) {
+	if len(flags) == 0 {
+		return nil, nil
+	}
+	headers := make(map[string]string, len(flags))
+	for _, h := range flags {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header '%s', expected the form 'Name: value'", h)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// parseLabelFlags parses labels supplied in the form 'key=value'.
+func parseLabelFlags(flags []string) (map[string]string, error) {
+	if len(flags) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(flags))
+	for _, l := range flags {
+		key, value, ok := strings.Cut(l, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid label '%s', expected the form 'key=value'", l)
+		}
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
+
+// readTLSFlags builds the TLS configuration from the certificate files supplied via flags.
+// It returns nil if no TLS flags were set.
+func readTLSFlags() (*types.UpstreamTLSConfig, error) {
+	if registerCmdClientCertPath == "" && registerCmdClientKeyPath == "" &&
+		registerCmdCACertPath == "" && !registerCmdInsecureSkipVerify {
+		return nil, nil
+	}
+	conf := &types.UpstreamTLSConfig{InsecureSkipVerify: registerCmdInsecureSkipVerify}
+	files := []struct {
+		path string
+		dst  *string
+	}{
+		{registerCmdClientCertPath, &conf.ClientCert},
+		{registerCmdClientKeyPath, &conf.ClientKey},
+		{registerCmdCACertPath, &conf.CACert},
+	}
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.path, err)
+		}
+		*f.dst = string(data)
+	}
+	return conf, nil
+}
+
+// readOpenAPISpecFile reads the OpenAPI spec of an openapi server from a local file, so that it can be sent
+// to the registry along with the rest of the configuration.
+// Specs supplied as http(s) URLs are left for the registry to download.
+func readOpenAPISpecFile(input *types.RegisterServerInput) error {
+	if input.Spec == "" || input.SpecContent != "" ||
+		strings.HasPrefix(input.Spec, "http://") || strings.HasPrefix(input.Spec, "https://") {
+		return nil
+	}
+	data, err := os.ReadFile(input.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to read OpenAPI spec %s: %w", input.Spec, err)
+	}
+	input.SpecContent = string(data)
+	input.Spec = ""
+	return nil
+}
+
 func runRegisterMCPServer(cmd *cobra.Command, args []string) error {
 	var input types.RegisterServerInput
 
@@ -103,11 +287,29 @@ func runRegisterMCPServer(cmd *cobra.Command, args []string) error {
 		// If no config file is provided, use the flags to create the input for server registration
 		input = types.RegisterServerInput{
 			Name:        registerCmdServerName,
-			Transport:   string(types.TransportStreamableHTTP),
+			Transport:   registerCmdTransport,
 			URL:         registerCmdServerURL,
+			Spec:        registerCmdSpec,
 			Description: registerCmdServerDesc,
 			BearerToken: registerCmdBearerToken,
 		}
+		var err error
+		if input.Headers, err = parseHeaderFlags(registerCmdHeaders); err != nil {
+			return err
+		}
+		if input.Labels, err = parseLabelFlags(registerCmdLabels); err != nil {
+			return err
+		}
+		if input.TLS, err = readTLSFlags(); err != nil {
+			return err
+		}
+		if registerCmdInitTimeout != 0 || registerCmdCallTimeout != 0 || registerCmdMaxConcurrency != 0 {
+			input.Policy = &types.ServerPolicy{
+				InitTimeoutSec: registerCmdInitTimeout,
+				CallTimeoutSec: registerCmdCallTimeout,
+				MaxConcurrency: registerCmdMaxConcurrency,
+			}
+		}
 	} else {
 		// If a config file is provided, read the configuration from the file
 		var err error
@@ -117,6 +319,20 @@ func runRegisterMCPServer(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if input.Transport == string(types.TransportOpenAPI) {
+		if err := readOpenAPISpecFile(&input); err != nil {
+			return err
+		}
+	}
+
+	if input.OAuth != nil && input.OAuth.GrantType == types.OAuthGrantAuthorizationCode {
+		// the server can only be accessed once the user has authorized mcpjungle
+		if err := runOAuthAuthorizationFlow(input.Name, input.OAuth, registerCmdOAuthCallbackPort); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
 	s, err := apiClient.RegisterServer(&input)
 	if err != nil {
 		return fmt.Errorf("failed to register server: %w", err)
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+// searchCmdDescriptionWidth is the maximum number of characters of a tool's description shown in search results.
+const searchCmdDescriptionWidth = 100
+
+var (
+	searchCmdServerName  string
+	searchCmdEnabledOnly bool
+	searchCmdLabels      string
+	searchCmdTag         string
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the tools in the registry",
+	Long: "Search the tools in the registry by keywords.\n" +
+		"Keywords are matched against the name, title and description of each tool," +
+		" the most relevant tools are shown first.",
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearchTools,
+}
+
+func init() {
+	searchCmd.Flags().StringVar(
+		&searchCmdServerName,
+		"server",
+		"",
+		"Only search the tools of this MCP server",
+	)
+	searchCmd.Flags().BoolVar(
+		&searchCmdEnabledOnly,
+		"enabled",
+		false,
+		"Only show enabled tools",
+	)
+	searchCmd.Flags().StringVarP(
+		&searchCmdLabels,
+		"labels",
+		"l",
+		"",
+		"Only search the tools whose labels match the label selector (eg- team=data)",
+	)
+	searchCmd.Flags().StringVar(
+		&searchCmdTag,
+		"tag",
+		"",
+		"Only search the tools that have this label, whatever its value",
+	)
+
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearchTools(cmd *cobra.Command, args []string) error {
+	filter := types.ToolFilter{
+		Query:  strings.Join(args, " "),
+		Server: searchCmdServerName,
+		Labels: searchCmdLabels,
+		Tag:    searchCmdTag,
+	}
+	if searchCmdEnabledOnly {
+		filter.Enabled = &searchCmdEnabledOnly
+	}
+	tools, err := apiClient.SearchTools(filter)
+	if err != nil {
+		return fmt.Errorf("failed to search tools: %w", err)
+	}
+
+	if len(tools) == 0 {
+		fmt.Println("No tools found")
+		return nil
+	}
+	for i, t := range tools {
+		server, _, _ := strings.Cut(t.Name, "__")
+		status := ""
+		if !t.Enabled {
+			status = "  [DISABLED]"
+		}
+		fmt.Printf("%d. %s (server: %s)%s\n", i+1, t.Name, server, status)
+		if d := summarizeDescription(t.Description); d != "" {
+			fmt.Println("   " + d)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("Run 'usage <tool name>' to see a tool's usage or 'invoke <tool name>' to call one")
+
+	return nil
+}
+
+// summarizeDescription returns the first line of a description, shortened to fit on one line.
+func summarizeDescription(description string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(description), "\n")
+	line = strings.TrimSpace(line)
+	if r := []rune(line); len(r) > searchCmdDescriptionWidth {
+		line = strings.TrimSpace(string(r[:searchCmdDescriptionWidth-3])) + "..."
+	}
+	return line
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"github.com/joho/godotenv"
 	"github.com/mark3labs/mcp-go/server"
@@ -11,10 +12,14 @@ import (
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp_client"
+	"github.com/mcpjungle/mcpjungle/internal/service/oauth_server"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
 	"github.com/spf13/cobra"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -24,11 +29,23 @@ const (
 	DBUrlEnvVar = "DATABASE_URL"
 
 	ServerModeEnvVar = "SERVER_MODE"
+
+	HealthCheckIntervalEnvVar = "HEALTH_CHECK_INTERVAL"
+	// HealthCheckIntervalDefault disables health checks, because every probe opens a new session with each
+	// MCP server, which spawns a new process for every stdio server.
+	HealthCheckIntervalDefault = time.Duration(0)
+
+	ToolSearchEnvVar = "TOOL_SEARCH"
+
+	TrustedProxiesEnvVar = "TRUSTED_PROXIES"
 )
 
 var (
-	startServerCmdBindPort    string
-	startServerCmdProdEnabled bool
+	startServerCmdBindPort            string
+	startServerCmdProdEnabled         bool
+	startServerCmdHealthCheckInterval string
+	startServerCmdToolSearch          bool
+	startServerCmdTrustedProxies      []string
 )
 
 var startServerCmd = &cobra.Command{
@@ -57,9 +74,98 @@ func init() {
 		),
 	)
 
+	startServerCmd.Flags().StringVar(
+		&startServerCmdHealthCheckInterval,
+		"health-check-interval",
+		"",
+		fmt.Sprintf(
+			"interval at which registered MCP servers are probed for health, eg- '30s', '5m'."+
+				" Tools of unreachable servers are hidden from the MCP proxy until they recover."+
+				" Health checks are disabled by default. Every probe starts a new process for each stdio server."+
+				" Overrides env var %s",
+			HealthCheckIntervalEnvVar,
+		),
+	)
+
+	startServerCmd.Flags().BoolVar(
+		&startServerCmdToolSearch,
+		"tool-search",
+		false,
+		fmt.Sprintf(
+			"Only expose the search_tools, describe_tool and call_tool meta-tools on the MCP proxy,"+
+				" so that clients discover tools on demand instead of listing all of them."+
+				" Useful for large registries. Alternatively, set the %s environment variable to 'true'",
+			ToolSearchEnvVar,
+		),
+	)
+
+	startServerCmd.Flags().StringSliceVar(
+		&startServerCmdTrustedProxies,
+		"trusted-proxies",
+		nil,
+		fmt.Sprintf(
+			"IP addresses or CIDR ranges of the reverse proxies in front of mcpjungle, eg- '10.0.0.0/8'."+
+				" Only requests from these proxies may set the client IP and the advertised URLs"+
+				" using the X-Forwarded-For, X-Forwarded-Proto and X-Forwarded-Host headers."+
+				" Overrides env var %s (comma-separated)",
+			TrustedProxiesEnvVar,
+		),
+	)
+
 	rootCmd.AddCommand(startServerCmd)
 }
 
+// getTrustedProxies determines the trusted reverse proxies from the command flag or the environment variable.
+func getTrustedProxies() []string {
+	if len(startServerCmdTrustedProxies) > 0 {
+		return startServerCmdTrustedProxies
+	}
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv(TrustedProxiesEnvVar), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+// getToolSearchEnabled determines whether the MCP proxy serves the tool search meta-tools
+// from the command flag or the environment variable.
+func getToolSearchEnabled() (bool, error) {
+	if startServerCmdToolSearch {
+		return true, nil
+	}
+	v := os.Getenv(ToolSearchEnvVar)
+	if v == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s environment variable: '%s'", ToolSearchEnvVar, v)
+	}
+	return enabled, nil
+}
+
+// getHealthCheckInterval determines the health check interval from the command flag, environment variable
+// or the default value, in that order of precedence.
+func getHealthCheckInterval() (time.Duration, error) {
+	v := startServerCmdHealthCheckInterval
+	if v == "" {
+		v = os.Getenv(HealthCheckIntervalEnvVar)
+	}
+	if v == "" {
+		return HealthCheckIntervalDefault, nil
+	}
+	if v == "0" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid health check interval '%s': %w", v, err)
+	}
+	return d, nil
+}
+
 func runStartServer(cmd *cobra.Command, args []string) error {
 	_ = godotenv.Load()
 
@@ -86,21 +192,37 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 	}
 
 	// create the MCP proxy server
+	proxyHooks := &server.Hooks{}
 	mcpProxyServer := server.NewMCPServer(
 		"MCPJungle Proxy MCP Server",
 		"0.0.1",
 		server.WithToolCapabilities(true),
+		server.WithHooks(proxyHooks),
 	)
 
-	mcpService, err := mcp.NewMCPService(dbConn, mcpProxyServer)
+	rateLimitService := ratelimit.NewRateLimitService(dbConn)
+
+	mcpService, err := mcp.NewMCPService(dbConn, mcpProxyServer, proxyHooks, rateLimitService)
 	if err != nil {
 		return fmt.Errorf("failed to create MCP service: %v", err)
 	}
 
+	healthCheckInterval, err := getHealthCheckInterval()
+	if err != nil {
+		return err
+	}
+	mcpService.StartHealthChecker(context.Background(), healthCheckInterval)
+
+	toolSearch, err := getToolSearchEnabled()
+	if err != nil {
+		return err
+	}
+
 	mcpClientService := mcp_client.NewMCPClientService(dbConn)
 
 	configService := config.NewServerConfigService(dbConn)
 	userService := user.NewUserService(dbConn)
+	oauthServerService := oauth_server.NewOAuthServerService(dbConn)
 
 	// create the API server
 	opts := &api.ServerOptions{
@@ -110,6 +232,12 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 		MCPClientService: mcpClientService,
 		ConfigService:    configService,
 		UserService:      userService,
+		RateLimitService: rateLimitService,
+
+		OAuthServerService: oauthServerService,
+
+		ToolSearch:     toolSearch,
+		TrustedProxies: getTrustedProxies(),
 	}
 	s, err := api.NewServer(opts)
 	if err != nil {
@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
 	"slices"
 	"strings"
@@ -25,10 +26,30 @@ func runGetToolUsage(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get tool '%s': %w", args[0], err)
 	}
 
-	fmt.Println(t.Name)
+	if t.Title != "" {
+		fmt.Printf("%s (%s)\n", t.Name, t.Title)
+	} else {
+		fmt.Println(t.Name)
+	}
 	fmt.Println(t.Description)
 
+	if hints := toolHints(t.Annotations); len(hints) > 0 {
+		fmt.Println("Hints: " + strings.Join(hints, ", "))
+	}
+
+	if len(t.OutputSchema) > 0 {
+		fmt.Println()
+		fmt.Println("Output Schema:")
+		j, err := json.MarshalIndent(t.OutputSchema, "", "  ")
+		if err != nil {
+			fmt.Println(t.OutputSchema)
+		} else {
+			fmt.Println(string(j))
+		}
+	}
+
 	if len(t.InputSchema.Properties) == 0 {
+		fmt.Println()
 		fmt.Println("This tool does not require any input parameters.")
 		return nil
 	}
@@ -60,3 +81,26 @@ func runGetToolUsage(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// toolHints returns the behavioural hints of a tool that are set, in human-readable form.
+func toolHints(a *types.ToolAnnotations) []string {
+	if a == nil {
+		return nil
+	}
+	var hints []string
+	add := func(hint *bool, ifTrue, ifFalse string) {
+		if hint == nil {
+			return
+		}
+		if *hint {
+			hints = append(hints, ifTrue)
+		} else {
+			hints = append(hints, ifFalse)
+		}
+	}
+	add(a.ReadOnlyHint, "read-only", "modifies its environment")
+	add(a.DestructiveHint, "destructive", "non-destructive")
+	add(a.IdempotentHint, "idempotent", "not idempotent")
+	add(a.OpenWorldHint, "interacts with external entities", "closed world")
+	return hints
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"net/http"
+)
+
+func listArgumentPresetsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presets, err := mcpService.ListArgumentPresets()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, presets)
+	}
+}
+
+// setArgumentPresetHandler creates the argument preset of a tool (for a client) or replaces the existing one.
+func setArgumentPresetHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.ArgumentPreset
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if input.Tool == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tool is required"})
+			return
+		}
+		if err := mcpService.SetArgumentPreset(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, input)
+	}
+}
+
+// deleteArgumentPresetHandler deletes the argument preset of a tool.
+// The optional client query parameter selects the preset of a specific MCP client.
+func deleteArgumentPresetHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := mcpService.DeleteArgumentPreset(c.Param("tool"), c.Query("client")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
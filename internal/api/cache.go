@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"net/http"
+)
+
+// getCacheStatsHandler returns the statistics of the tool result caches of all MCP servers.
+func getCacheStatsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, mcpService.GetCacheStats())
+	}
+}
+
+// purgeCacheHandler removes the cached results of the given tool or of all tools of the given mcp server.
+// If no entity is given, all cached results are removed.
+func purgeCacheHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		purged := mcpService.PurgeCache(c.Query("entity"))
+		c.JSON(http.StatusOK, gin.H{"purged": purged})
+	}
+}
@@ -7,14 +7,21 @@ import (
 	"net/http"
 )
 
+// listMcpClientsHandler lists the MCP clients.
+// Results can be sorted (?sort=) and split into pages (?limit=, ?cursor=).
 func listMcpClientsHandler(mcpClientService *mcp_client.McpClientService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clients, err := mcpClientService.ListClients()
+		opts, err := listOptionsFromQuery(c)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		clients, next, err := mcpClientService.ListClientsPage(opts)
+		if err != nil {
+			c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, clients)
+		respondWithPage(c, clients, next)
 	}
 }
 
@@ -29,6 +36,10 @@ func createMcpClientHandler(mcpClientService *mcp_client.McpClientService) gin.H
 			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
 			return
 		}
+		if _, err := req.GetAllowSelectors(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid allow selectors: " + err.Error()})
+			return
+		}
 		// TODO: if allow list in the request is null, convert it to an empty JSON array
 		client, err := mcpClientService.CreateClient(req)
 		if err != nil {
@@ -24,12 +24,18 @@ func registerServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 		}
 
 		var server *model.McpServer
-		if transport == types.TransportStreamableHTTP {
+		switch transport {
+		case types.TransportStreamableHTTP:
 			server, err = model.NewStreamableHTTPServer(
 				input.Name,
 				input.Description,
-				input.URL,
-				input.BearerToken,
+				model.StreamableHTTPConfig{
+					URL:         input.URL,
+					BearerToken: input.BearerToken,
+					Headers:     input.Headers,
+					TLS:         input.TLS,
+					OAuth:       input.OAuth,
+				},
 			)
 			if err != nil {
 				c.JSON(
@@ -38,7 +44,46 @@ func registerServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 				)
 				return
 			}
-		} else {
+		case types.TransportSSE:
+			server, err = model.NewSSEServer(
+				input.Name,
+				input.Description,
+				model.SSEConfig{
+					URL:         input.URL,
+					BearerToken: input.BearerToken,
+					Headers:     input.Headers,
+					TLS:         input.TLS,
+					OAuth:       input.OAuth,
+				},
+			)
+			if err != nil {
+				c.JSON(
+					http.StatusBadRequest,
+					gin.H{"error": fmt.Sprintf("Error creating sse server: %v", err)},
+				)
+				return
+			}
+		case types.TransportOpenAPI:
+			server, err = model.NewOpenAPIServer(
+				input.Name,
+				input.Description,
+				model.OpenAPIConfig{
+					SpecURL:     input.Spec,
+					Spec:        input.SpecContent,
+					BaseURL:     input.URL,
+					BearerToken: input.BearerToken,
+					Headers:     input.Headers,
+					TLS:         input.TLS,
+				},
+			)
+			if err != nil {
+				c.JSON(
+					http.StatusBadRequest,
+					gin.H{"error": fmt.Sprintf("Error creating openapi server: %v", err)},
+				)
+				return
+			}
+		default:
 			server, err = model.NewStdioServer(
 				input.Name,
 				input.Description,
@@ -55,11 +100,25 @@ func registerServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 			}
 		}
 
+		if err := server.SetPolicy(input.Policy); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid server policy: %v", err)})
+			return
+		}
+		if err := server.SetLabels(input.Labels); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid server labels: %v", err)})
+			return
+		}
+
 		if err := mcpService.RegisterMcpServer(c, server); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusCreated, server)
+		resp, err := newServerResponse(mcpService, server)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, resp)
 	}
 }
 
@@ -74,48 +133,118 @@ func deregisterServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	}
 }
 
+// listServersHandler lists the registered MCP servers, optionally filtered by a label selector (?labels=).
+// Results can be sorted (?sort=) and split into pages (?limit=, ?cursor=).
 func listServersHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		records, err := mcpService.ListMcpServers()
+		sel, err := types.ParseLabelSelector(c.Query("labels"))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		opts, err := listOptionsFromQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		records, next, err := mcpService.ListMcpServersPage(sel, opts)
+		if err != nil {
+			c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
 			return
 		}
 		servers := make([]*types.McpServer, len(records), len(records))
-		for i, record := range records {
-			servers[i] = &types.McpServer{
-				Name:        record.Name,
-				Transport:   string(record.Transport),
-				Description: record.Description,
-			}
-			if record.Transport == types.TransportStreamableHTTP {
-				conf, err := record.GetStreamableHTTPConfig()
-				if err != nil {
-					c.JSON(
-						http.StatusInternalServerError,
-						gin.H{
-							"error": fmt.Sprintf("Error getting streamable HTTP config for server %s: %v", record.Name, err),
-						},
-					)
-					return
-				}
-				servers[i].URL = conf.URL
-			} else {
-				conf, err := record.GetStdioConfig()
-				if err != nil {
-					c.JSON(
-						http.StatusInternalServerError,
-						gin.H{
-							"error": fmt.Sprintf("Error getting stdio config for server %s: %v", record.Name, err),
-						},
-					)
-					return
-				}
-				servers[i].Command = conf.Command
-				servers[i].Args = conf.Args
-				servers[i].Env = conf.Env
+		for i := range records {
+			if servers[i], err = newServerResponse(mcpService, &records[i]); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
 			}
 		}
-		c.JSON(http.StatusOK, servers)
+		respondWithPage(c, servers, next)
+	}
+}
+
+// newServerResponse converts a registered MCP server into its API representation.
+// All secrets in its configuration (tokens, header values, private keys and client secrets) are redacted.
+func newServerResponse(mcpService *mcp.MCPService, record *model.McpServer) (*types.McpServer, error) {
+	s := &types.McpServer{
+		Name:        record.Name,
+		Transport:   string(record.Transport),
+		Description: record.Description,
+		Status:      mcpService.GetServerStatus(record),
+	}
+	policy, err := record.GetPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting policy for server %s: %v", record.Name, err)
+	}
+	if len(record.Policy) > 0 {
+		s.Policy = policy
+	}
+	if s.Labels, err = record.GetLabels(); err != nil {
+		return nil, fmt.Errorf("Error getting labels for server %s: %v", record.Name, err)
+	}
+	switch record.Transport {
+	case types.TransportStreamableHTTP:
+		conf, err := record.GetStreamableHTTPConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting streamable HTTP config for server %s: %v", record.Name, err)
+		}
+		s.URL = conf.URL
+		s.Headers = types.RedactHeaders(conf.Headers)
+		if conf.TLS != nil {
+			s.TLS = conf.TLS.Redacted()
+		}
+		if conf.OAuth != nil {
+			s.OAuth = conf.OAuth.Redacted()
+		}
+	case types.TransportSSE:
+		conf, err := record.GetSSEConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting sse config for server %s: %v", record.Name, err)
+		}
+		s.URL = conf.URL
+		s.Headers = types.RedactHeaders(conf.Headers)
+		if conf.TLS != nil {
+			s.TLS = conf.TLS.Redacted()
+		}
+		if conf.OAuth != nil {
+			s.OAuth = conf.OAuth.Redacted()
+		}
+	case types.TransportOpenAPI:
+		conf, err := record.GetOpenAPIConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting openapi config for server %s: %v", record.Name, err)
+		}
+		s.URL = conf.BaseURL
+		s.SpecURL = conf.SpecURL
+		s.Headers = types.RedactHeaders(conf.Headers)
+		if conf.TLS != nil {
+			s.TLS = conf.TLS.Redacted()
+		}
+	default:
+		conf, err := record.GetStdioConfig()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting stdio config for server %s: %v", record.Name, err)
+		}
+		s.Command = conf.Command
+		s.Args = conf.Args
+		s.Env = conf.Env
+	}
+	return s, nil
+}
+
+// updateServerLabelsHandler adds, changes or removes labels of an MCP server and returns its new labels.
+func updateServerLabelsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.LabelsUpdate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		labels, err := mcpService.UpdateServerLabels(c.Param("name"), input.Labels)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, labels)
 	}
 }
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	mcpservice "github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegisterServerRedactsSecrets(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	proxy := server.NewMCPServer("test", "0.0.1", server.WithToolCapabilities(true))
+	mcpService, err := mcpservice.NewMCPService(db, proxy, &server.Hooks{}, ratelimit.NewRateLimitService(db))
+	if err != nil {
+		t.Fatalf("failed to create MCP service: %v", err)
+	}
+
+	// the upstream MCP server uses TLS, so that a client certificate & key can be configured for it
+	upstream := server.NewMCPServer("upstream", "0.0.1")
+	upstream.AddTool(mcp.NewTool("echo"), func(ctx context.Context, r mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	ts := httptest.NewTLSServer(server.NewStreamableHTTPServer(upstream))
+	defer ts.Close()
+	cert := ts.TLS.Certificates[0]
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatalf("failed to encode the private key: %v", err)
+	}
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}))
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}))
+	tlsConfig := &types.UpstreamTLSConfig{ClientCert: certPEM, ClientKey: keyPEM, CACert: certPEM}
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "upstream-token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer tokenServer.Close()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/servers", registerServerHandler(mcpService))
+
+	tests := []struct {
+		name    string
+		input   types.RegisterServerInput
+		secrets []string
+	}{
+		{
+			name: "bearer token",
+			input: types.RegisterServerInput{
+				Name:        "with_token",
+				Transport:   string(types.TransportStreamableHTTP),
+				URL:         ts.URL + "/mcp",
+				BearerToken: "bearer-secret",
+				Headers:     map[string]string{"X-Api-Key": "header-secret"},
+				TLS:         tlsConfig,
+			},
+			secrets: []string{"bearer-secret", "header-secret", "PRIVATE KEY"},
+		},
+		{
+			name: "oauth",
+			input: types.RegisterServerInput{
+				Name:      "with_oauth",
+				Transport: string(types.TransportStreamableHTTP),
+				URL:       ts.URL + "/mcp",
+				TLS:       tlsConfig,
+				OAuth: &types.UpstreamOAuthConfig{
+					GrantType:    types.OAuthGrantClientCredentials,
+					ClientID:     "mcpjungle",
+					ClientSecret: "oauth-secret",
+					TokenURL:     tokenServer.URL,
+				},
+			},
+			secrets: []string{"oauth-secret", "PRIVATE KEY"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.input)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/servers", bytes.NewReader(body)))
+			if w.Code != http.StatusCreated {
+				t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+			}
+			for _, secret := range tt.secrets {
+				if strings.Contains(w.Body.String(), secret) {
+					t.Errorf("expected %q to be redacted from the response: %s", secret, w.Body.String())
+				}
+			}
+			var s types.McpServer
+			if err := json.Unmarshal(w.Body.Bytes(), &s); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if s.Name != tt.input.Name || s.URL != tt.input.URL {
+				t.Errorf("unexpected server in response: %+v", s)
+			}
+		})
+	}
+}
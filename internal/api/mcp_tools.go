@@ -2,32 +2,68 @@ package api
 
 import (
 	"encoding/json"
-	"github.com/mcpjungle/mcpjungle/internal/model"
+	"errors"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 )
 
+// listToolsHandler lists the registered tools.
+// Tools can be filtered by server, by enabled state (?enabled=true|false), by a label selector (?labels=)
+// or by the presence of a label (?tag=), and searched by keywords (?q=), in which case they are ranked by relevance.
+// Results can be sorted (?sort=) and split into pages (?limit=, ?cursor=).
 func listToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		server := c.Query("server")
-		var (
-			tools []model.Tool
-			err   error
-		)
-		if server == "" {
-			// no server specified, list all tools
-			tools, err = mcpService.ListTools()
-		} else {
-			// server specified, list tools for that server
-			tools, err = mcpService.ListToolsByServer(server)
+		filter := types.ToolFilter{
+			Query:  c.Query("q"),
+			Server: c.Query("server"),
+			Labels: c.Query("labels"),
+			Tag:    c.Query("tag"),
 		}
+		if v := c.Query("enabled"); v != "" {
+			enabled, err := strconv.ParseBool(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid value for enabled: " + v})
+				return
+			}
+			filter.Enabled = &enabled
+		}
+		if _, err := types.ParseLabelSelector(filter.Labels); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if filter.Tag != "" {
+			if err := types.ValidateLabels(map[string]string{filter.Tag: ""}); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag: " + err.Error()})
+				return
+			}
+		}
+		opts, err := listOptionsFromQuery(c)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, tools)
+		if filter.Server != "" {
+			// make sure the server exists, rather than returning an empty list
+			if _, err := mcpService.GetMcpServer(filter.Server); err != nil {
+				c.JSON(
+					http.StatusInternalServerError,
+					gin.H{"error": fmt.Sprintf("failed to get MCP server %s from DB: %v", filter.Server, err)},
+				)
+				return
+			}
+		}
+		tools, next, err := mcpService.ListToolsPage(filter, opts)
+		if err != nil {
+			c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		respondWithPage(c, tools, next)
 	}
 }
 
@@ -57,9 +93,24 @@ func invokeToolHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 		// remove name from args since it was an input for the api, not for the tool
 		delete(args, "name")
 
-		resp, err := mcpService.InvokeTool(c, name, args)
+		resp, err := mcpService.InvokeTool(c.Request.Context(), name, args)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to invoke tool: " + err.Error()})
+			var rlErr *ratelimit.RateLimitError
+			if errors.As(err, &rlErr) {
+				c.Header("Retry-After", strconv.Itoa(rlErr.RetryAfterSeconds()))
+				c.JSON(http.StatusTooManyRequests, gin.H{"error": "failed to invoke tool: " + err.Error()})
+				return
+			}
+			var argsErr *mcp.ToolArgumentsError
+			if errors.As(err, &argsErr) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "validation_errors": argsErr.Errors})
+				return
+			}
+			status := http.StatusInternalServerError
+			if errors.Is(err, mcp.ErrCircuitOpen) || errors.Is(err, mcp.ErrServerBusy) {
+				status = http.StatusServiceUnavailable
+			}
+			c.JSON(status, gin.H{"error": "failed to invoke tool: " + err.Error()})
 			return
 		}
 
@@ -87,15 +138,25 @@ func getToolHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	}
 }
 
-// enableToolsHandler enables the given tool or all tools of the given mcp server
+// enableToolsHandler enables the given tool, all tools of the given mcp server
+// or all tools matching the given label selector
 func enableToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		entity := c.Query("entity")
-		if entity == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'entity' query parameter"})
+		selector := c.Query("labels")
+		if (entity == "") == (selector == "") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of 'entity' and 'labels' query parameters is required"})
 			return
 		}
-		enabledTools, err := mcpService.EnableTools(entity)
+		var (
+			enabledTools []string
+			err          error
+		)
+		if selector != "" {
+			enabledTools, err = mcpService.EnableToolsByLabels(selector)
+		} else {
+			enabledTools, err = mcpService.EnableTools(entity)
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to enable tool(s): " + err.Error()})
 			return
@@ -104,15 +165,25 @@ func enableToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	}
 }
 
-// disableToolsHandler disables the given tool or all tools of the given mcp server
+// disableToolsHandler disables the given tool, all tools of the given mcp server
+// or all tools matching the given label selector
 func disableToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		entity := c.Query("entity")
-		if entity == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'entity' query parameter"})
+		selector := c.Query("labels")
+		if (entity == "") == (selector == "") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of 'entity' and 'labels' query parameters is required"})
 			return
 		}
-		disabledTools, err := mcpService.DisableTools(entity)
+		var (
+			disabledTools []string
+			err           error
+		)
+		if selector != "" {
+			disabledTools, err = mcpService.DisableToolsByLabels(selector)
+		} else {
+			disabledTools, err = mcpService.DisableTools(entity)
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to disable tool(s): " + err.Error()})
 			return
@@ -120,3 +191,25 @@ func disableToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 		c.JSON(http.StatusOK, disabledTools)
 	}
 }
+
+// updateToolLabelsHandler adds, changes or removes labels of the given tool and returns its new labels.
+func updateToolLabelsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Query("name")
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' query parameter"})
+			return
+		}
+		var input types.LabelsUpdate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		labels, err := mcpService.UpdateToolLabels(name, input.Labels)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, labels)
+	}
+}
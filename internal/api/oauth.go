@@ -0,0 +1,47 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"net/http"
+)
+
+// startOAuthAuthorizationHandler starts the authorization of mcpjungle by a user to access an MCP server
+// and returns the URL the user must visit.
+func startOAuthAuthorizationHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.OAuthAuthorizeInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Server == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "server is required"})
+			return
+		}
+		resp, err := mcpService.StartOAuthAuthorization(input.Server, input.RedirectURI, input.OAuth)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// completeOAuthAuthorizationHandler completes an authorization with the code returned by the authorization server.
+func completeOAuthAuthorizationHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.OAuthCallbackInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		server, err := mcpService.CompleteOAuthAuthorization(c.Request.Context(), input.State, input.Code)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"server": server})
+	}
+}
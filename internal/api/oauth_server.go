@@ -0,0 +1,344 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp_client"
+	"github.com/mcpjungle/mcpjungle/internal/service/oauth_server"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	oauthProtectedResourcePath    = "/.well-known/oauth-protected-resource"
+	oauthAuthorizationServerPath  = "/.well-known/oauth-authorization-server"
+	oauthAuthorizeEndpointPath    = "/oauth/authorize"
+	oauthTokenEndpointPath        = "/oauth/token"
+	oauthRegistrationEndpointPath = "/oauth/register"
+)
+
+// consentPage asks the user to approve an MCP client application with a one-time approval code issued by an admin.
+// The approval code picks the MCP client identity that the application gets.
+var consentPage = template.Must(template.New("consent").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Authorize {{.ClientName}} - MCPJungle</title></head>
+<body style="font-family: sans-serif; max-width: 32em; margin: 4em auto;">
+<h2>Authorize {{.ClientName}}</h2>
+<p><b>{{.ClientName}}</b> wants to access the MCP servers in MCPJungle.</p>
+<p>To approve, ask an MCPJungle admin to run <code>mcpjungle create oauth-approval &lt;mcp-client&gt;</code>
+and enter the one-time approval code it prints.
+The application will act as that MCP client and can only access the servers in its allow list.</p>
+<p>If you approve, the authorization is sent to <b>{{.RedirectHost}}</b>.</p>
+{{if .Error}}<p style="color: #b00020;">{{.Error}}</p>{{end}}
+<form method="post" action="{{.Action}}">
+{{range $k, $v := .Params}}<input type="hidden" name="{{$k}}" value="{{$v}}">
+{{end}}<p><label>Approval code<br><input name="approval_code" type="password" autocomplete="off" required></label></p>
+<p><button name="decision" value="approve">Approve</button>
+<button name="decision" value="deny" formnovalidate>Deny</button></p>
+</form>
+</body>
+</html>
+`))
+
+// consentPageData is rendered by consentPage.
+type consentPageData struct {
+	ClientName string
+	// RedirectHost is the host that the authorization code is sent to, so that admins can tell where it goes
+	RedirectHost string
+	Action       string
+	Params       map[string]string
+	Error        string
+}
+
+// externalBaseURL returns the URL that clients use to reach mcpjungle.
+// The X-Forwarded-Proto and X-Forwarded-Host headers are only honored if the request comes from a trusted
+// reverse proxy, otherwise any caller could choose the URLs that mcpjungle advertises.
+func externalBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	host := c.Request.Host
+	if c.GetBool(trustedProxyKey) {
+		if p := strings.TrimSpace(strings.Split(c.GetHeader("X-Forwarded-Proto"), ",")[0]); p == "http" || p == "https" {
+			scheme = p
+		}
+		if h := c.GetHeader("X-Forwarded-Host"); h != "" {
+			host = strings.TrimSpace(strings.Split(h, ",")[0])
+		}
+	}
+	return scheme + "://" + host
+}
+
+// oauthProtectedResourceMetadataURL returns the URL that MCP clients are pointed to when they need a token.
+func oauthProtectedResourceMetadataURL(c *gin.Context) string {
+	return externalBaseURL(c) + oauthProtectedResourcePath
+}
+
+// oauthProtectedResourceHandler serves the metadata of the MCP proxy as an OAuth protected resource.
+// Clients may ask for the metadata of a specific endpoint, eg, /.well-known/oauth-protected-resource/mcp.
+func oauthProtectedResourceHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		base := externalBaseURL(c)
+		resource := strings.TrimSuffix(c.Param("resource"), "/")
+		if resource == "" {
+			resource = "/mcp"
+		}
+		c.JSON(http.StatusOK, types.OAuthProtectedResourceMetadata{
+			Resource:               base + resource,
+			AuthorizationServers:   []string{base},
+			BearerMethodsSupported: []string{"header"},
+		})
+	}
+}
+
+// oauthAuthorizationServerHandler serves the metadata of mcpjungle's authorization server.
+func oauthAuthorizationServerHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		base := externalBaseURL(c)
+		c.JSON(http.StatusOK, types.OAuthAuthorizationServerMetadata{
+			Issuer:                        base,
+			AuthorizationEndpoint:         base + oauthAuthorizeEndpointPath,
+			TokenEndpoint:                 base + oauthTokenEndpointPath,
+			RegistrationEndpoint:          base + oauthRegistrationEndpointPath,
+			ResponseTypesSupported:        []string{"code"},
+			GrantTypesSupported:           []string{oauth_server.GrantTypeAuthorizationCode, oauth_server.GrantTypeRefreshToken},
+			CodeChallengeMethodsSupported: []string{oauth_server.CodeChallengeMethodS256},
+			TokenEndpointAuthMethodsSupported: []string{
+				oauth_server.TokenEndpointAuthNone,
+				oauth_server.TokenEndpointAuthClientSecretBasic,
+				oauth_server.TokenEndpointAuthClientSecretPost,
+			},
+		})
+	}
+}
+
+// oauthErrorResponse writes an OAuth error, using the status code that RFC 6749 prescribes for it.
+func oauthErrorResponse(c *gin.Context, err error) {
+	var oauthErr *oauth_server.Error
+	if !errors.As(err, &oauthErr) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error", "error_description": err.Error()})
+		return
+	}
+	status := http.StatusBadRequest
+	if oauthErr.Code == "invalid_client" {
+		status = http.StatusUnauthorized
+	}
+	c.JSON(status, gin.H{"error": oauthErr.Code, "error_description": oauthErr.Description})
+}
+
+// registerOAuthClientHandler registers an OAuth client through dynamic client registration.
+func registerOAuthClientHandler(oauthServerService *oauth_server.OAuthServerService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req types.OAuthClientRegistrationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_client_metadata", "error_description": err.Error()})
+			return
+		}
+		resp, err := oauthServerService.RegisterClient(&req, c.ClientIP())
+		if errors.Is(err, oauth_server.ErrRegistrationLimitExceeded) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "temporarily_unavailable", "error_description": err.Error()})
+			return
+		}
+		if err != nil {
+			oauthErrorResponse(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, resp)
+	}
+}
+
+// parseAuthorizationRequest reads the parameters of an authorization request from the query or the consent form.
+func parseAuthorizationRequest(c *gin.Context) *oauth_server.AuthorizationRequest {
+	get := c.Query
+	if c.Request.Method == http.MethodPost {
+		get = c.PostForm
+	}
+	return &oauth_server.AuthorizationRequest{
+		ResponseType:        get("response_type"),
+		ClientID:            get("client_id"),
+		RedirectURI:         get("redirect_uri"),
+		State:               get("state"),
+		CodeChallenge:       get("code_challenge"),
+		CodeChallengeMethod: get("code_challenge_method"),
+	}
+}
+
+// redirectWithAuthorizationResponse sends the user's browser back to the client with the given parameters.
+func redirectWithAuthorizationResponse(c *gin.Context, req *oauth_server.AuthorizationRequest, params url.Values) {
+	u, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		c.String(http.StatusBadRequest, "invalid redirect_uri")
+		return
+	}
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	if req.State != "" {
+		q.Set("state", req.State)
+	}
+	u.RawQuery = q.Encode()
+	c.Redirect(http.StatusFound, u.String())
+}
+
+// redirectWithAuthorizationError sends an OAuth error back to the client through the user's browser.
+func redirectWithAuthorizationError(c *gin.Context, req *oauth_server.AuthorizationRequest, err error) {
+	params := url.Values{"error": {"server_error"}}
+	var oauthErr *oauth_server.Error
+	if errors.As(err, &oauthErr) {
+		params.Set("error", oauthErr.Code)
+		params.Set("error_description", oauthErr.Description)
+	}
+	redirectWithAuthorizationResponse(c, req, params)
+}
+
+// renderConsentPage shows the consent page for a validated authorization request, along with an error if the
+// status is not 200.
+func renderConsentPage(c *gin.Context, status int, req *oauth_server.AuthorizationRequest, clientName, errMsg string) {
+	if clientName == "" {
+		clientName = "An MCP client"
+	}
+	redirectHost := req.RedirectURI
+	if u, err := url.Parse(req.RedirectURI); err == nil && u.Host != "" {
+		redirectHost = u.Host
+	}
+	data := consentPageData{
+		ClientName:   clientName,
+		RedirectHost: redirectHost,
+		Action:       oauthAuthorizeEndpointPath,
+		Params: map[string]string{
+			"response_type":         req.ResponseType,
+			"client_id":             req.ClientID,
+			"redirect_uri":          req.RedirectURI,
+			"state":                 req.State,
+			"code_challenge":        req.CodeChallenge,
+			"code_challenge_method": req.CodeChallengeMethod,
+		},
+		Error: errMsg,
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	// the consent page must never be framed by another site, otherwise users could be tricked into approving
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "frame-ancestors 'none'")
+	c.Status(status)
+	_ = consentPage.Execute(c.Writer, data)
+}
+
+// oauthAuthorizeHandler serves the authorization endpoint.
+// A GET request shows the consent page. The page is posted back to approve or deny the authorization.
+// Approving requires a one-time approval code, so that no static token is ever entered in the browser.
+func oauthAuthorizeHandler(oauthServerService *oauth_server.OAuthServerService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := parseAuthorizationRequest(c)
+
+		// errors about the client or redirect uri must not be sent to the (unverified) redirect uri
+		client, err := oauthServerService.ValidateRedirect(req)
+		if err != nil {
+			c.String(http.StatusBadRequest, "invalid authorization request: %s", err.Error())
+			return
+		}
+		if err := oauthServerService.ValidateAuthorizationRequest(req); err != nil {
+			redirectWithAuthorizationError(c, req, err)
+			return
+		}
+
+		if c.Request.Method == http.MethodGet {
+			renderConsentPage(c, http.StatusOK, req, client.ClientName, "")
+			return
+		}
+
+		if c.PostForm("decision") != "approve" {
+			redirectWithAuthorizationError(
+				c, req, &oauth_server.Error{Code: "access_denied", Description: "the user denied the authorization"},
+			)
+			return
+		}
+		mcpClient, err := oauthServerService.RedeemApproval(strings.TrimSpace(c.PostForm("approval_code")), c.ClientIP())
+		if errors.Is(err, oauth_server.ErrTooManyFailedApprovals) {
+			renderConsentPage(
+				c, http.StatusTooManyRequests, req, client.ClientName,
+				"Too many invalid approval codes were entered. Try again later.",
+			)
+			return
+		}
+		if err != nil {
+			renderConsentPage(c, http.StatusUnauthorized, req, client.ClientName, "Invalid or expired approval code.")
+			return
+		}
+
+		code, err := oauthServerService.CreateAuthorizationCode(req, mcpClient)
+		if err != nil {
+			redirectWithAuthorizationError(c, req, err)
+			return
+		}
+		redirectWithAuthorizationResponse(c, req, url.Values{"code": {code}})
+	}
+}
+
+// createOAuthApprovalHandler issues a one-time approval code that lets a user approve an OAuth authorization
+// on the consent page. The authorization grants the identity of the MCP client named in the request.
+func createOAuthApprovalHandler(
+	oauthServerService *oauth_server.OAuthServerService, mcpClientService *mcp_client.McpClientService,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.OAuthApprovalInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body: " + err.Error()})
+			return
+		}
+		if input.McpClient == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mcp_client is required"})
+			return
+		}
+		mcpClient, err := mcpClientService.GetClientByName(input.McpClient)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "MCP client " + input.McpClient + " does not exist"})
+			return
+		}
+		a, err := oauthServerService.CreateApproval(mcpClient)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, a)
+	}
+}
+
+// oauthTokenHandler serves the token endpoint.
+// It supports the authorization_code grant (with PKCE) and the refresh_token grant.
+func oauthTokenHandler(oauthServerService *oauth_server.OAuthServerService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+
+		clientID, clientSecret, ok := c.Request.BasicAuth()
+		if !ok {
+			clientID, clientSecret = c.PostForm("client_id"), c.PostForm("client_secret")
+		}
+		client, err := oauthServerService.AuthenticateClient(clientID, clientSecret)
+		if err != nil {
+			oauthErrorResponse(c, err)
+			return
+		}
+
+		var resp *types.OAuthTokenResponse
+		switch grantType := c.PostForm("grant_type"); grantType {
+		case oauth_server.GrantTypeAuthorizationCode:
+			resp, err = oauthServerService.ExchangeAuthorizationCode(
+				client, c.PostForm("code"), c.PostForm("redirect_uri"), c.PostForm("code_verifier"),
+			)
+		case oauth_server.GrantTypeRefreshToken:
+			resp, err = oauthServerService.RefreshAccessToken(client, c.PostForm("refresh_token"))
+		default:
+			err = &oauth_server.Error{Code: "unsupported_grant_type", Description: "unsupported grant_type " + grantType}
+		}
+		if err != nil {
+			oauthErrorResponse(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExternalBaseURL(t *testing.T) {
+	trusted, err := parseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("failed to parse trusted proxies: %v", err)
+	}
+	if _, err := parseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Errorf("expected an error for a trusted proxy that is not an IP address or a CIDR range")
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(markTrustedProxy(trusted))
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, externalBaseURL(c)) })
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		want       string
+	}{
+		{"untrusted caller", "203.0.113.7:4000", "http://mcpjungle.internal:8080"},
+		{"trusted proxy", "10.1.2.3:4000", "https://mcp.example.com"},
+		{"trusted proxy address", "192.0.2.1:4000", "https://mcp.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://mcpjungle.internal:8080/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-Proto", "https")
+			req.Header.Set("X-Forwarded-Host", "mcp.example.com")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("expected base URL %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
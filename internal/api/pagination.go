@@ -0,0 +1,40 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"net/http"
+	"strconv"
+)
+
+// listOptionsFromQuery reads the sort field (?sort=), page size (?limit=) and cursor (?cursor=) of a list request.
+func listOptionsFromQuery(c *gin.Context) (types.ListOptions, error) {
+	opts := types.ListOptions{Sort: c.Query("sort"), Cursor: c.Query("cursor")}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			return opts, fmt.Errorf("invalid value for limit: %s", v)
+		}
+		opts.Limit = limit
+	}
+	return opts, nil
+}
+
+// respondWithPage sends a page of a list, along with the cursor of the next page if there is one.
+func respondWithPage(c *gin.Context, page any, next string) {
+	if next != "" {
+		c.Header(types.NextCursorHeader, next)
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// listErrorStatus returns the HTTP status for an error returned by a list query.
+func listErrorStatus(err error) int {
+	if errors.Is(err, db.ErrInvalidListOptions) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
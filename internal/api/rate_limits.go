@@ -0,0 +1,77 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"net/http"
+)
+
+func listRateLimitsHandler(rateLimitService *ratelimit.RateLimitService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		records, err := rateLimitService.ListRateLimits()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		limits := make([]*types.RateLimit, len(records))
+		for i, record := range records {
+			today, thisMonth, err := rateLimitService.GetUsage(&records[i])
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			limits[i] = &types.RateLimit{
+				Name:              record.Name,
+				Scope:             string(record.Scope),
+				Client:            record.Client,
+				Server:            record.Server,
+				Tool:              record.Tool,
+				RequestsPerMinute: record.RequestsPerMinute,
+				Burst:             record.Burst,
+				DailyQuota:        record.DailyQuota,
+				MonthlyQuota:      record.MonthlyQuota,
+				Usage:             &types.RateLimitUsage{Today: today, ThisMonth: thisMonth},
+			}
+		}
+		c.JSON(http.StatusOK, limits)
+	}
+}
+
+func createRateLimitHandler(rateLimitService *ratelimit.RateLimitService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.RateLimit
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		l := &model.RateLimit{
+			Name:              input.Name,
+			Scope:             model.RateLimitScope(input.Scope),
+			Client:            input.Client,
+			Server:            input.Server,
+			Tool:              input.Tool,
+			RequestsPerMinute: input.RequestsPerMinute,
+			Burst:             input.Burst,
+			DailyQuota:        input.DailyQuota,
+			MonthlyQuota:      input.MonthlyQuota,
+		}
+		if err := rateLimitService.CreateRateLimit(l); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, l)
+	}
+}
+
+func deleteRateLimitHandler(rateLimitService *ratelimit.RateLimitService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if err := rateLimitService.DeleteRateLimit(name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateRateLimitReturnsRecord(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/rate-limits", createRateLimitHandler(ratelimit.NewRateLimitService(db)))
+
+	body := `{"name": "cursor", "scope": "client", "client": "cursor", "requests_per_minute": 60, "usage": {"today": 99}}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rate-limits", strings.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// the response is the persisted record, not an echo of the request body
+	var got model.RateLimit
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.ID == 0 || got.CreatedAt.IsZero() {
+		t.Errorf("expected the ID and timestamps of the record, got %+v", got)
+	}
+	if got.Name != "cursor" || got.Scope != model.RateLimitScopeClient || got.RequestsPerMinute != 60 {
+		t.Errorf("unexpected rate limit: %+v", got)
+	}
+	if strings.Contains(w.Body.String(), "usage") {
+		t.Errorf("expected the usage in the request body not to be echoed: %s", w.Body.String())
+	}
+}
@@ -9,8 +9,11 @@ import (
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp_client"
+	"github.com/mcpjungle/mcpjungle/internal/service/oauth_server"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
 	"net/http"
+	"net/netip"
 	"strings"
 )
 
@@ -25,6 +28,16 @@ type ServerOptions struct {
 	MCPClientService *mcp_client.McpClientService
 	ConfigService    *config.ServerConfigService
 	UserService      *user.UserService
+	RateLimitService *ratelimit.RateLimitService
+
+	OAuthServerService *oauth_server.OAuthServerService
+
+	// ToolSearch makes the MCP proxy endpoints serve the tool search meta-tools instead of all tools.
+	ToolSearch bool
+
+	// TrustedProxies are the IP addresses or CIDR ranges of the reverse proxies in front of mcpjungle.
+	// The X-Forwarded-* headers are only honored on requests that come from one of them.
+	TrustedProxies []string
 }
 
 // Server represents the MCPJungle registry server that handles MCP proxy and API requests
@@ -145,10 +158,12 @@ func checkAuthForAPIAccess(configService *config.ServerConfigService, userServic
 
 // checkAuthForMcpProxyAccess is middleware for MCP proxy that checks for a valid MCP client token
 // if the server is in production mode.
+// The token is either the static access token of an MCP client or an access token issued to it using OAuth.
 // In development mode, mcp clients do not require auth to access the MCP proxy.
 func checkAuthForMcpProxyAccess(
 	configService *config.ServerConfigService,
 	mcpClientService *mcp_client.McpClientService,
+	oauthServerService *oauth_server.OAuthServerService,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		cfg, err := configService.GetConfig()
@@ -169,14 +184,22 @@ func checkAuthForMcpProxyAccess(
 			return
 		}
 
+		// point clients to the protected resource metadata so that they can obtain a token using OAuth
+		resourceMetadata := fmt.Sprintf(`resource_metadata="%s"`, oauthProtectedResourceMetadataURL(c))
+
 		authHeader := c.GetHeader("Authorization")
 		token := strings.TrimPrefix(authHeader, "Bearer ")
 		if token == "" {
+			c.Header("WWW-Authenticate", "Bearer "+resourceMetadata)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing MCP client access token"})
 			return
 		}
 		client, err := mcpClientService.GetClientByToken(token)
 		if err != nil {
+			client, err = oauthServerService.GetMcpClientByAccessToken(token)
+		}
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token", `+resourceMetadata)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid MCP client token"})
 			return
 		}
@@ -211,11 +234,58 @@ func requireServerMode(configService *config.ServerConfigService, m model.Server
 	}
 }
 
+// trustedProxyKey is set in the gin context of requests that come from a trusted reverse proxy.
+const trustedProxyKey = "trustedProxy"
+
+// parseTrustedProxies parses the IP addresses and CIDR ranges of trusted reverse proxies.
+func parseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, p := range proxies {
+		if addr, err := netip.ParseAddr(p); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy '%s': must be an IP address or a CIDR range", p)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// markTrustedProxy is middleware that marks requests coming from one of the trusted reverse proxies,
+// so that the X-Forwarded-* headers they set can be honored.
+func markTrustedProxy(trusted []netip.Prefix) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if addr, err := netip.ParseAddr(c.RemoteIP()); err == nil {
+			addr = addr.Unmap()
+			for _, p := range trusted {
+				if p.Contains(addr) {
+					c.Set(trustedProxyKey, true)
+					break
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
 // newRouter sets up the Gin router with the MCP proxy server and API endpoints.
 func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
 
+	trustedProxies, err := parseTrustedProxies(opts.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	// gin trusts all proxies by default, which lets any caller choose its client IP using X-Forwarded-For
+	if err := r.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	r.Use(markTrustedProxy(trustedProxies))
+
 	r.GET(
 		"/health",
 		func(c *gin.Context) {
@@ -227,10 +297,16 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 
 	requireInit := requireInitialized(opts.ConfigService)
 	checkUserAuth := checkAuthForAPIAccess(opts.ConfigService, opts.UserService)
-	checkMcpClientAuth := checkAuthForMcpProxyAccess(opts.ConfigService, opts.MCPClientService)
+	checkMcpClientAuth := checkAuthForMcpProxyAccess(opts.ConfigService, opts.MCPClientService, opts.OAuthServerService)
+
+	proxyServer := opts.MCPProxyServer
+	if opts.ToolSearch {
+		// clients find the tools through the meta-tools, which call them on the MCP proxy
+		proxyServer = opts.MCPService.ToolSearchServer()
+	}
 
 	// Set up the MCP proxy server on /mcp
-	streamableHttpServer := server.NewStreamableHTTPServer(opts.MCPProxyServer)
+	streamableHttpServer := server.NewStreamableHTTPServer(proxyServer)
 	r.Any(
 		"/mcp",
 		requireInit,
@@ -238,12 +314,50 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 		gin.WrapH(streamableHttpServer),
 	)
 
+	// Each tool group is served as a separate MCP server that only exposes the tools in the group
+	r.Any(
+		toolGroupPathPrefix+":name",
+		requireInit,
+		checkMcpClientAuth,
+		toolGroupProxyHandler(opts.MCPService),
+	)
+
+	// Also serve the MCP proxy server over the legacy HTTP+SSE transport for clients that don't support
+	// streamable http yet. Clients open the event stream on /sse and post their messages to /message.
+	sseServer := server.NewSSEServer(
+		proxyServer,
+		server.WithSSEEndpoint("/sse"),
+		server.WithMessageEndpoint("/message"),
+		server.WithUseFullURLForMessageEndpoint(false),
+		server.WithKeepAlive(true),
+	)
+	r.GET("/sse", requireInit, checkMcpClientAuth, gin.WrapH(sseServer.SSEHandler()))
+	r.POST("/message", requireInit, checkMcpClientAuth, gin.WrapH(sseServer.MessageHandler()))
+
+	// In production mode, mcpjungle is also an OAuth authorization server so that MCP clients
+	// can obtain access tokens for the proxy instead of being configured with static tokens.
+	requireProd := requireServerMode(opts.ConfigService, model.ModeProd)
+	r.GET(oauthProtectedResourcePath, requireInit, requireProd, oauthProtectedResourceHandler())
+	r.GET(oauthProtectedResourcePath+"/*resource", requireInit, requireProd, oauthProtectedResourceHandler())
+	r.GET(oauthAuthorizationServerPath, requireInit, requireProd, oauthAuthorizationServerHandler())
+	r.POST(
+		oauthRegistrationEndpointPath,
+		requireInit,
+		requireProd,
+		registerOAuthClientHandler(opts.OAuthServerService),
+	)
+	authorizeHandler := oauthAuthorizeHandler(opts.OAuthServerService)
+	r.GET(oauthAuthorizeEndpointPath, requireInit, requireProd, authorizeHandler)
+	r.POST(oauthAuthorizeEndpointPath, requireInit, requireProd, authorizeHandler)
+	r.POST(oauthTokenEndpointPath, requireInit, requireProd, oauthTokenHandler(opts.OAuthServerService))
+
 	// Setup API endpoints
 	apiV0 := r.Group(V0PathPrefix, requireInit, checkUserAuth)
 	{
 		apiV0.POST("/servers", registerServerHandler(opts.MCPService))
 		apiV0.DELETE("/servers/:name", deregisterServerHandler(opts.MCPService))
 		apiV0.GET("/servers", listServersHandler(opts.MCPService))
+		apiV0.PATCH("/servers/:name/labels", updateServerLabelsHandler(opts.MCPService))
 
 		apiV0.GET("/tools", listToolsHandler(opts.MCPService))
 		apiV0.POST("/tools/invoke", invokeToolHandler(opts.MCPService))
@@ -251,6 +365,35 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 		apiV0.POST("/tools/disable", disableToolsHandler(opts.MCPService))
 
 		apiV0.GET("/tool", getToolHandler(opts.MCPService))
+		apiV0.PATCH("/tool/labels", updateToolLabelsHandler(opts.MCPService))
+
+		apiV0.POST("/oauth/authorize", startOAuthAuthorizationHandler(opts.MCPService))
+		apiV0.POST("/oauth/callback", completeOAuthAuthorizationHandler(opts.MCPService))
+
+		apiV0.GET("/tool-overrides", listToolOverridesHandler(opts.MCPService))
+		apiV0.POST("/tool-overrides", setToolOverrideHandler(opts.MCPService))
+		apiV0.DELETE("/tool-overrides/:tool", deleteToolOverrideHandler(opts.MCPService))
+
+		apiV0.GET("/argument-presets", listArgumentPresetsHandler(opts.MCPService))
+		apiV0.POST("/argument-presets", setArgumentPresetHandler(opts.MCPService))
+		apiV0.DELETE("/argument-presets/:tool", deleteArgumentPresetHandler(opts.MCPService))
+
+		apiV0.GET("/composite-tools", listCompositeToolsHandler(opts.MCPService))
+		apiV0.POST("/composite-tools", createCompositeToolHandler(opts.MCPService))
+		apiV0.GET("/composite-tools/:name", getCompositeToolHandler(opts.MCPService))
+		apiV0.DELETE("/composite-tools/:name", deleteCompositeToolHandler(opts.MCPService))
+
+		apiV0.GET("/groups", listToolGroupsHandler(opts.MCPService))
+		apiV0.POST("/groups", createToolGroupHandler(opts.MCPService))
+		apiV0.GET("/groups/:name", getToolGroupHandler(opts.MCPService))
+		apiV0.DELETE("/groups/:name", deleteToolGroupHandler(opts.MCPService))
+
+		apiV0.GET("/cache", getCacheStatsHandler(opts.MCPService))
+		apiV0.DELETE("/cache", purgeCacheHandler(opts.MCPService))
+
+		apiV0.GET("/rate-limits", listRateLimitsHandler(opts.RateLimitService))
+		apiV0.POST("/rate-limits", createRateLimitHandler(opts.RateLimitService))
+		apiV0.DELETE("/rate-limits/:name", deleteRateLimitHandler(opts.RateLimitService))
 
 		apiV0.GET(
 			"/clients",
@@ -267,6 +410,11 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 			requireServerMode(opts.ConfigService, model.ModeProd),
 			deleteMcpClientHandler(opts.MCPClientService),
 		)
+		apiV0.POST(
+			"/oauth-approvals",
+			requireServerMode(opts.ConfigService, model.ModeProd),
+			createOAuthApprovalHandler(opts.OAuthServerService, opts.MCPClientService),
+		)
 	}
 
 	return r, nil
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"net/http"
+	"sync"
+)
+
+// toolGroupPathPrefix is the path under which the MCP endpoints of tool groups are served.
+const toolGroupPathPrefix = "/mcp/groups/"
+
+func toolGroupModelToType(g *model.ToolGroup) (*types.ToolGroup, error) {
+	tools, err := g.GetIncludedTools()
+	if err != nil {
+		return nil, err
+	}
+	servers, err := g.GetIncludedServers()
+	if err != nil {
+		return nil, err
+	}
+	return &types.ToolGroup{
+		Name:            g.Name,
+		Description:     g.Description,
+		IncludedTools:   tools,
+		IncludedServers: servers,
+		Selector:        g.Selector,
+		Endpoint:        toolGroupPathPrefix + g.Name,
+	}, nil
+}
+
+func listToolGroupsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		records, err := mcpService.ListToolGroups()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		groups := make([]*types.ToolGroup, len(records))
+		for i := range records {
+			if groups[i], err = toolGroupModelToType(&records[i]); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		c.JSON(http.StatusOK, groups)
+	}
+}
+
+func getToolGroupHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		record, err := mcpService.GetToolGroup(c.Param("name"))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, mcp.ErrToolGroupNotFound) {
+				status = http.StatusNotFound
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		g, err := toolGroupModelToType(record)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, g)
+	}
+}
+
+func createToolGroupHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.ToolGroup
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		record, err := mcpService.CreateToolGroup(
+			input.Name, input.Description, input.IncludedTools, input.IncludedServers, input.Selector,
+		)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		g, err := toolGroupModelToType(record)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, g)
+	}
+}
+
+func deleteToolGroupHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := mcpService.DeleteToolGroup(c.Param("name")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// toolGroupProxyHandler serves the MCP endpoint of each tool group over streamable http.
+// The streamable http server of a group keeps track of its sessions, so it is created once per group
+// and reused for all requests. It is replaced if the group is deleted and created again.
+func toolGroupProxyHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	type groupHandler struct {
+		proxy *server.MCPServer
+		http  *server.StreamableHTTPServer
+	}
+	var mu sync.Mutex
+	handlers := make(map[string]*groupHandler)
+
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		proxy, err := mcpService.GetToolGroupProxy(name)
+
+		mu.Lock()
+		if err != nil {
+			delete(handlers, name)
+			mu.Unlock()
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h, ok := handlers[name]
+		if !ok || h.proxy != proxy {
+			h = &groupHandler{proxy: proxy, http: server.NewStreamableHTTPServer(proxy)}
+			handlers[name] = h
+		}
+		mu.Unlock()
+
+		h.http.ServeHTTP(c.Writer, c.Request)
+	}
+}
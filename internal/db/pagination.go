@@ -0,0 +1,215 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"reflect"
+	"strings"
+)
+
+// MaxPageSize is the maximum number of results returned in a single page of a list query.
+const MaxPageSize = 1000
+
+// ErrInvalidListOptions is returned when the sort field or the cursor supplied to a list query is invalid.
+var ErrInvalidListOptions = errors.New("invalid list options")
+
+// SortColumn is a column, or an SQL expression, that the results of a list query are ordered by.
+type SortColumn struct {
+	Expr string
+	// Args are the values of the placeholders in Expr, if any
+	Args []any
+	Desc bool
+}
+
+// SortField is a field that the results of a list query can be sorted by.
+type SortField[T any] struct {
+	// Columns are the columns to order results by. They must end with a unique column (eg- the id),
+	// so that the order is stable and every result can be located by a cursor.
+	Columns []SortColumn
+	// Values returns the values of the columns for a result. It must accept the zero value of T,
+	// whose values determine the types that cursors are decoded into.
+	Values func(T) []any
+}
+
+// cursor marks the position in a list after which the next page starts.
+// It holds the values of the sort columns of the last result of the previous page.
+type cursor struct {
+	Sort   string            `json:"sort"`
+	Values []json.RawMessage `json:"values"`
+}
+
+// encodeCursor builds the opaque cursor that points past a result, from the values of its sort columns.
+func encodeCursor(sort string, values []any) (string, error) {
+	c := cursor{Sort: sort, Values: make([]json.RawMessage, len(values))}
+	for i, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode cursor: %w", err)
+		}
+		c.Values[i] = b
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor decodes the values of the sort columns held by a cursor.
+// They are decoded into values of the same types as the ones in like.
+// A cursor can only be used with the sort it was created for.
+func decodeCursor(s, sort string, like []any) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidListOptions)
+	}
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil || len(c.Values) != len(like) {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidListOptions)
+	}
+	if c.Sort != sort {
+		return nil, fmt.Errorf("%w: cursor was created for sort '%s', not '%s'", ErrInvalidListOptions, c.Sort, sort)
+	}
+	values := make([]any, len(like))
+	for i, v := range c.Values {
+		dst := reflect.New(reflect.TypeOf(like[i]))
+		if err := json.Unmarshal(v, dst.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidListOptions)
+		}
+		values[i] = dst.Elem().Interface()
+	}
+	return values, nil
+}
+
+// orderBy orders a query by the sort columns.
+func orderBy(q *gorm.DB, cols []SortColumn) *gorm.DB {
+	exprs := make([]string, len(cols))
+	var args []any
+	for i, c := range cols {
+		exprs[i] = c.Expr + " ASC"
+		if c.Desc {
+			exprs[i] = c.Expr + " DESC"
+		}
+		args = append(args, c.Args...)
+	}
+	return q.Order(clause.OrderBy{
+		Expression: clause.Expr{SQL: strings.Join(exprs, ", "), Vars: args, WithoutParentheses: true},
+	})
+}
+
+// after restricts a query to the rows that come after the given values of the sort columns.
+// This is keyset pagination, so pages stay cheap however far into the list they are:
+//
+//	(c1 > v1) OR (c1 = v1 AND c2 > v2) OR (c1 = v1 AND c2 = v2 AND c3 > v3) ...
+//
+// Row value comparisons would be shorter, but cannot mix ascending and descending columns.
+func after(q *gorm.DB, cols []SortColumn, values []any) *gorm.DB {
+	var (
+		ors  []string
+		args []any
+	)
+	for i, c := range cols {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, cols[j].Expr+" = ?")
+			args = append(append(args, cols[j].Args...), values[j])
+		}
+		op := " > ?"
+		if c.Desc {
+			op = " < ?"
+		}
+		ands = append(ands, c.Expr+op)
+		args = append(append(args, c.Args...), values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return q.Where("("+strings.Join(ors, " OR ")+")", args...)
+}
+
+// FindPage fetches a page of the results of a query, sorted by one of the fields.
+// The sort field may be prefixed with '-' for descending order. keep filters the results in Go, for conditions
+// that cannot be expressed in SQL, and can be nil. It returns the cursor of the next page, or an empty string
+// if this is the last page.
+func FindPage[T any](
+	q *gorm.DB,
+	fields map[string]SortField[T],
+	opts types.ListOptions,
+	keep func(T) bool,
+) ([]T, string, error) {
+	name, desc := strings.CutPrefix(opts.Sort, "-")
+	field, ok := fields[name]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: cannot sort by '%s'", ErrInvalidListOptions, name)
+	}
+	if opts.Limit < 0 {
+		return nil, "", fmt.Errorf("%w: limit must not be negative", ErrInvalidListOptions)
+	}
+	limit := min(opts.Limit, MaxPageSize)
+
+	cols := make([]SortColumn, len(field.Columns))
+	for i, c := range field.Columns {
+		cols[i] = c
+		cols[i].Desc = c.Desc != desc
+	}
+
+	var start []any
+	if opts.Cursor != "" {
+		var zero T
+		var err error
+		if start, err = decodeCursor(opts.Cursor, opts.Sort, field.Values(zero)); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// the query is run several times, so chained conditions must not leak into its next runs
+	q = orderBy(q.Session(&gorm.Session{}), cols).Session(&gorm.Session{})
+	page := make([]T, 0)
+
+	if limit == 0 {
+		var rows []T
+		b := q
+		if start != nil {
+			b = after(b, cols, start)
+		}
+		if err := b.Scan(&rows).Error; err != nil {
+			return nil, "", err
+		}
+		for _, r := range rows {
+			if keep == nil || keep(r) {
+				page = append(page, r)
+			}
+		}
+		return page, "", nil
+	}
+
+	// rows discarded by keep make a batch yield less than a page, so batches are fetched until one more
+	// result than the page size is found, which tells whether there is a next page.
+	for {
+		var batch []T
+		b := q
+		if start != nil {
+			b = after(b, cols, start)
+		}
+		if err := b.Limit(limit + 1).Scan(&batch).Error; err != nil {
+			return nil, "", err
+		}
+		for _, r := range batch {
+			if keep != nil && !keep(r) {
+				continue
+			}
+			if len(page) == limit {
+				next, err := encodeCursor(opts.Sort, field.Values(page[len(page)-1]))
+				return page, next, err
+			}
+			page = append(page, r)
+		}
+		if len(batch) <= limit {
+			return page, "", nil
+		}
+		start = field.Values(batch[len(batch)-1])
+	}
+}
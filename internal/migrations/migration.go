@@ -23,5 +23,32 @@ func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(&model.McpClient{}); err != nil {
 		return fmt.Errorf("auto‑migration failed for McpClient model: %v", err)
 	}
+	if err := db.AutoMigrate(&model.RateLimit{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for RateLimit model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.RateLimitUsage{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for RateLimitUsage model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.UpstreamOAuthToken{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for UpstreamOAuthToken model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.OAuthClient{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for OAuthClient model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.OAuthIssuedToken{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for OAuthIssuedToken model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ToolGroup{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ToolGroup model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ToolOverride{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ToolOverride model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ArgumentPreset{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ArgumentPreset model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.CompositeTool{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for CompositeTool model: %v", err)
+	}
 	return nil
 }
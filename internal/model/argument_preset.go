@@ -0,0 +1,22 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ArgumentPreset pins arguments of a tool to fixed values.
+// The MCP proxy hides the preset parameters from MCP clients and injects their values into every call.
+type ArgumentPreset struct {
+	gorm.Model
+
+	// ToolName is the canonical name of the tool.
+	ToolName string `json:"tool_name" gorm:"uniqueIndex:idx_argument_presets_tool_client;not null"`
+
+	// Client is the name of the MCP client the preset applies to.
+	// If empty, the preset applies to all clients.
+	Client string `json:"client" gorm:"uniqueIndex:idx_argument_presets_tool_client"`
+
+	// Arguments is a JSON object mapping parameter names to their values.
+	Arguments datatypes.JSON `json:"arguments" gorm:"type:jsonb; not null"`
+}
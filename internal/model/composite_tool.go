@@ -0,0 +1,27 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// CompositeTool is a tool defined in mcpjungle itself whose implementation is a sequence of calls
+// to the tools of upstream MCP servers.
+type CompositeTool struct {
+	gorm.Model
+
+	// Name is the name under which the MCP proxy exposes the tool.
+	// It does not belong to any MCP server, so it never contains the server-tool separator.
+	Name string `json:"name" gorm:"uniqueIndex;not null"`
+
+	Description string `json:"description"`
+
+	// InputSchema is the JSON schema of the tool's input, presented to MCP clients.
+	InputSchema datatypes.JSON `json:"input_schema" gorm:"type:jsonb"`
+
+	// Steps is a JSON array of the tool calls that implement the tool, in the order they are made.
+	Steps datatypes.JSON `json:"steps" gorm:"type:jsonb"`
+
+	// Output is the template of the tool's result. If empty, the result of the last step is returned.
+	Output string `json:"output"`
+}
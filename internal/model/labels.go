@@ -0,0 +1,30 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+)
+
+// decodeLabels returns the labels stored as a JSON object. It returns an empty map if there are none.
+func decodeLabels(v datatypes.JSON) (map[string]string, error) {
+	labels := make(map[string]string)
+	if len(v) == 0 || string(v) == "null" {
+		return labels, nil
+	}
+	if err := json.Unmarshal(v, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// encodeLabels validates labels and encodes them as a JSON object. It returns nil if there are none.
+func encodeLabels(labels map[string]string) (datatypes.JSON, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	if err := types.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
+	return json.Marshal(labels)
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"encoding/json"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -19,6 +20,10 @@ type McpClient struct {
 	// storing the list of server names as a JSON array is a convenient way for now.
 	// In the future, this will be removed in favor of a separate table for ACLs.
 	AllowList datatypes.JSON `json:"allow_list" gorm:"type:jsonb; not null"`
+
+	// AllowSelectors contains a list of label selectors. The client is also allowed to view and call
+	// the MCP servers whose labels match any of them.
+	AllowSelectors datatypes.JSON `json:"allow_selectors,omitempty" gorm:"type:jsonb"`
 }
 
 // CheckHasServerAccess returns true if this client has access to the specified MCP server.
@@ -38,3 +43,34 @@ func (c *McpClient) CheckHasServerAccess(serverName string) bool {
 	}
 	return false
 }
+
+// GetAllowSelectors returns the label selectors of the servers that this client is allowed to access.
+func (c *McpClient) GetAllowSelectors() ([]types.LabelSelector, error) {
+	list, err := decodeStringList(c.AllowSelectors)
+	if err != nil {
+		return nil, err
+	}
+	selectors := make([]types.LabelSelector, 0, len(list))
+	for _, s := range list {
+		sel, err := types.ParseLabelSelector(s)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+	}
+	return selectors, nil
+}
+
+// CheckHasLabelAccess returns true if any of the allow selectors of this client matches the labels of an MCP server.
+func (c *McpClient) CheckHasLabelAccess(serverLabels map[string]string) bool {
+	selectors, err := c.GetAllowSelectors()
+	if err != nil {
+		return false
+	}
+	for _, sel := range selectors {
+		if len(sel) > 0 && sel.Matches(serverLabels) {
+			return true
+		}
+	}
+	return false
+}
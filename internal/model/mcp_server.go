@@ -3,9 +3,11 @@ package model
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"net/url"
 )
 
 type StreamableHTTPConfig struct {
@@ -16,6 +18,61 @@ type StreamableHTTPConfig struct {
 	// BearerToken is an optional token used for authenticating requests to the MCP server.
 	// If present, it will be used to set the Authorization header in all requests to this MCP server.
 	BearerToken string `json:"bearer_token,omitempty"`
+
+	// Headers are custom headers sent in all requests to this MCP server, eg- API keys.
+	// An Authorization header is overridden by BearerToken if that is set.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// TLS optionally configures mutual TLS and verification of the server's certificate.
+	TLS *types.UpstreamTLSConfig `json:"tls,omitempty"`
+
+	// OAuth optionally makes mcpjungle authenticate with the MCP server as an OAuth client.
+	// It cannot be combined with BearerToken.
+	OAuth *types.UpstreamOAuthConfig `json:"oauth,omitempty"`
+}
+
+type SSEConfig struct {
+	// URL must be a valid http/https URL of the server's SSE endpoint.
+	URL string `json:"url"`
+
+	// BearerToken is an optional token used for authenticating requests to the MCP server.
+	// If present, it will be used to set the Authorization header in all requests to this MCP server.
+	BearerToken string `json:"bearer_token,omitempty"`
+
+	// Headers are custom headers sent in all requests to this MCP server, eg- API keys.
+	// An Authorization header is overridden by BearerToken if that is set.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// TLS optionally configures mutual TLS and verification of the server's certificate.
+	TLS *types.UpstreamTLSConfig `json:"tls,omitempty"`
+
+	// OAuth optionally makes mcpjungle authenticate with the MCP server as an OAuth client.
+	// It cannot be combined with BearerToken.
+	OAuth *types.UpstreamOAuthConfig `json:"oauth,omitempty"`
+}
+
+// OpenAPIConfig describes an HTTP API whose operations mcpjungle serves as tools.
+type OpenAPIConfig struct {
+	// SpecURL is the URL the spec was fetched from, if it was registered by URL.
+	SpecURL string `json:"spec_url,omitempty"`
+
+	// Spec is the OpenAPI spec document (JSON or YAML).
+	// It is a snapshot taken when the server is registered, so the tools don't change until it is registered again.
+	Spec string `json:"spec,omitempty"`
+
+	// BaseURL is the URL that the paths of the API are relative to.
+	// If empty, the URL of the first server in the spec is used.
+	BaseURL string `json:"base_url,omitempty"`
+
+	// BearerToken is an optional token used for authenticating requests to the API.
+	BearerToken string `json:"bearer_token,omitempty"`
+
+	// Headers are custom headers sent in all requests to the API, eg- API keys.
+	// An Authorization header is overridden by BearerToken if that is set.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// TLS optionally configures mutual TLS and verification of the API's certificate.
+	TLS *types.UpstreamTLSConfig `json:"tls,omitempty"`
 }
 
 type StdioConfig struct {
@@ -39,18 +96,25 @@ type McpServer struct {
 	Description string `json:"description"`
 
 	// Config describes the transport-specific configuration for the MCP server.
-	// It contains the JSON representation of either StreamableHTTPConfig or StdioConfig.
+	// It contains the JSON representation of either StreamableHTTPConfig, SSEConfig, StdioConfig or OpenAPIConfig.
 	Config datatypes.JSON `json:"config" gorm:"type:jsonb;not null"`
+
+	// Policy contains the JSON representation of types.ServerPolicy.
+	// It is null if the server uses the default policy.
+	Policy datatypes.JSON `json:"policy" gorm:"type:jsonb"`
+
+	// Labels is a JSON object of key/value labels used to organise servers, eg- {"team": "data"}.
+	// The tools of the server inherit its labels.
+	Labels datatypes.JSON `json:"labels" gorm:"type:jsonb"`
 }
 
 // NewStreamableHTTPServer creates a new MCP server with streamable HTTP transport configuration.
-func NewStreamableHTTPServer(name, description, url, bearerToken string) (*McpServer, error) {
-	if url == "" {
+func NewStreamableHTTPServer(name, description string, config StreamableHTTPConfig) (*McpServer, error) {
+	if config.URL == "" {
 		return nil, errors.New("url is required for streamable HTTP transport")
 	}
-	config := StreamableHTTPConfig{
-		URL:         url,
-		BearerToken: bearerToken,
+	if err := validateHTTPConfig(config.BearerToken, config.Headers, config.TLS, config.OAuth); err != nil {
+		return nil, err
 	}
 	configJSON, err := json.Marshal(config)
 	if err != nil {
@@ -64,6 +128,26 @@ func NewStreamableHTTPServer(name, description, url, bearerToken string) (*McpSe
 	}, nil
 }
 
+// NewSSEServer creates a new MCP server with SSE transport configuration.
+func NewSSEServer(name, description string, config SSEConfig) (*McpServer, error) {
+	if config.URL == "" {
+		return nil, errors.New("url is required for sse transport")
+	}
+	if err := validateHTTPConfig(config.BearerToken, config.Headers, config.TLS, config.OAuth); err != nil {
+		return nil, err
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return &McpServer{
+		Name:        name,
+		Description: description,
+		Transport:   types.TransportSSE,
+		Config:      configJSON,
+	}, nil
+}
+
 // NewStdioServer creates a new MCP server with stdio transport configuration.
 func NewStdioServer(name, description, command string, args []string, env map[string]string) (*McpServer, error) {
 	if command == "" {
@@ -87,6 +171,85 @@ func NewStdioServer(name, description, command string, args []string, env map[st
 	}, nil
 }
 
+// NewOpenAPIServer creates a new server that serves the operations of an OpenAPI spec as tools.
+// Either the spec or the URL to fetch it from must be supplied.
+func NewOpenAPIServer(name, description string, config OpenAPIConfig) (*McpServer, error) {
+	if config.Spec == "" && config.SpecURL == "" {
+		return nil, errors.New("an OpenAPI spec or the url of one is required for openapi transport")
+	}
+	if config.Spec == "" && !isHTTPURL(config.SpecURL) {
+		return nil, fmt.Errorf("invalid spec url %s: must be an http/https URL", config.SpecURL)
+	}
+	if config.BaseURL != "" && !isHTTPURL(config.BaseURL) {
+		return nil, fmt.Errorf("invalid base url %s: must be an http/https URL", config.BaseURL)
+	}
+	if err := validateHTTPConfig(config.BearerToken, config.Headers, config.TLS, nil); err != nil {
+		return nil, err
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return &McpServer{
+		Name:        name,
+		Description: description,
+		Transport:   types.TransportOpenAPI,
+		Config:      configJSON,
+	}, nil
+}
+
+func isHTTPURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateHTTPConfig validates the authentication, custom headers and TLS configuration of an HTTP-based MCP server.
+func validateHTTPConfig(
+	bearerToken string,
+	headers map[string]string,
+	tlsConfig *types.UpstreamTLSConfig,
+	oauthConfig *types.UpstreamOAuthConfig,
+) error {
+	if err := types.ValidateHeaders(headers); err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		if err := tlsConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid tls configuration: %w", err)
+		}
+	}
+	if oauthConfig != nil {
+		if bearerToken != "" {
+			return errors.New("a bearer token cannot be combined with oauth")
+		}
+		if err := oauthConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid oauth configuration: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetOAuthConfig returns the OAuth configuration of this server.
+// It returns nil if the server does not authenticate using OAuth.
+func (s *McpServer) GetOAuthConfig() (*types.UpstreamOAuthConfig, error) {
+	switch s.Transport {
+	case types.TransportStreamableHTTP:
+		conf, err := s.GetStreamableHTTPConfig()
+		if err != nil {
+			return nil, err
+		}
+		return conf.OAuth, nil
+	case types.TransportSSE:
+		conf, err := s.GetSSEConfig()
+		if err != nil {
+			return nil, err
+		}
+		return conf.OAuth, nil
+	default:
+		return nil, nil
+	}
+}
+
 // GetStreamableHTTPConfig returns the configuration if this is a streamable HTTP server
 func (s *McpServer) GetStreamableHTTPConfig() (*StreamableHTTPConfig, error) {
 	if s.Transport != types.TransportStreamableHTTP {
@@ -99,6 +262,43 @@ func (s *McpServer) GetStreamableHTTPConfig() (*StreamableHTTPConfig, error) {
 	return &config, nil
 }
 
+// GetSSEConfig returns the configuration if this is an SSE server
+func (s *McpServer) GetSSEConfig() (*SSEConfig, error) {
+	if s.Transport != types.TransportSSE {
+		return nil, errors.New("server is not an sse transport type")
+	}
+	var config SSEConfig
+	if err := json.Unmarshal(s.Config, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetOpenAPIConfig returns the configuration if this is an openapi server
+func (s *McpServer) GetOpenAPIConfig() (*OpenAPIConfig, error) {
+	if s.Transport != types.TransportOpenAPI {
+		return nil, errors.New("server is not an openapi transport type")
+	}
+	var config OpenAPIConfig
+	if err := json.Unmarshal(s.Config, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SetOpenAPIConfig replaces the configuration of an openapi server.
+func (s *McpServer) SetOpenAPIConfig(config *OpenAPIConfig) error {
+	if s.Transport != types.TransportOpenAPI {
+		return errors.New("server is not an openapi transport type")
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	s.Config = configJSON
+	return nil
+}
+
 // GetStdioConfig returns the configuration if this is a stdio server
 func (s *McpServer) GetStdioConfig() (*StdioConfig, error) {
 	if s.Transport != types.TransportStdio {
@@ -110,3 +310,49 @@ func (s *McpServer) GetStdioConfig() (*StdioConfig, error) {
 	}
 	return &config, nil
 }
+
+// SetPolicy validates and sets the policy of this server.
+// A nil policy resets the server to the default policy.
+func (s *McpServer) SetPolicy(p *types.ServerPolicy) error {
+	if p == nil {
+		s.Policy = nil
+		return nil
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	policyJSON, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	s.Policy = policyJSON
+	return nil
+}
+
+// GetLabels returns the labels of this server.
+func (s *McpServer) GetLabels() (map[string]string, error) {
+	return decodeLabels(s.Labels)
+}
+
+// SetLabels validates and sets the labels of this server.
+func (s *McpServer) SetLabels(labels map[string]string) error {
+	v, err := encodeLabels(labels)
+	if err != nil {
+		return err
+	}
+	s.Labels = v
+	return nil
+}
+
+// GetPolicy returns the policy of this server.
+// If no policy was configured, an empty policy is returned so that defaults apply.
+func (s *McpServer) GetPolicy() (*types.ServerPolicy, error) {
+	var policy types.ServerPolicy
+	if len(s.Policy) == 0 || string(s.Policy) == "null" {
+		return &policy, nil
+	}
+	if err := json.Unmarshal(s.Policy, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
@@ -19,12 +19,57 @@ type Tool struct {
 	// If a tool is disabled, it cannot be viewed or called from the MCP proxy.
 	Enabled bool `json:"enabled" gorm:"default:true"`
 
+	// Title is the human-readable name of the tool supplied by the MCP server.
+	Title string `json:"title,omitempty"`
+
 	Description string `json:"description"`
 
 	// InputSchema is a JSON schema that describes the input parameters for the tool.
+	// It is stored exactly as supplied by the MCP server.
 	InputSchema datatypes.JSON `json:"input_schema" gorm:"type:jsonb"`
 
+	// OutputSchema is an optional JSON schema that describes the structured output of the tool.
+	OutputSchema datatypes.JSON `json:"output_schema,omitempty" gorm:"type:jsonb"`
+
+	// Annotations contains the behavioural hints (read-only, idempotent, etc.) supplied by the MCP server.
+	Annotations datatypes.JSON `json:"annotations" gorm:"type:jsonb"`
+
+	// Meta contains the `_meta` object of the tool definition supplied by the MCP server.
+	Meta datatypes.JSON `json:"_meta,omitempty" gorm:"type:jsonb"`
+
+	// Labels is a JSON object of key/value labels set on this tool.
+	// They are added to the labels inherited from the server, and take precedence over them.
+	Labels datatypes.JSON `json:"labels,omitempty" gorm:"type:jsonb"`
+
 	// ServerID is the ID of the MCP server that provides this tool.
 	ServerID uint      `json:"-" gorm:"not null"`
 	Server   McpServer `json:"-" gorm:"foreignKey:ServerID;references:ID"`
 }
+
+// GetLabels returns the labels set on this tool, without the ones inherited from its server.
+func (t *Tool) GetLabels() (map[string]string, error) {
+	return decodeLabels(t.Labels)
+}
+
+// SetLabels validates and sets the labels of this tool.
+func (t *Tool) SetLabels(labels map[string]string) error {
+	v, err := encodeLabels(labels)
+	if err != nil {
+		return err
+	}
+	t.Labels = v
+	return nil
+}
+
+// EffectiveLabels returns the labels of a tool along with the ones it inherits from its server.
+// Labels of the tool take precedence.
+func EffectiveLabels(serverLabels, toolLabels map[string]string) map[string]string {
+	labels := make(map[string]string, len(serverLabels)+len(toolLabels))
+	for k, v := range serverLabels {
+		labels[k] = v
+	}
+	for k, v := range toolLabels {
+		labels[k] = v
+	}
+	return labels
+}
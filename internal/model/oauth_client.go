@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/json"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+// OAuthClient is an application registered with mcpjungle's authorization server,
+// usually by an MCP client through dynamic client registration.
+type OAuthClient struct {
+	gorm.Model
+
+	ClientID string `json:"client_id" gorm:"uniqueIndex;not null"`
+
+	// ClientSecret is empty for public clients, which authenticate the token requests using PKCE alone.
+	ClientSecret string `json:"-"`
+
+	ClientName string `json:"client_name"`
+
+	// RedirectURIs contains the JSON array of URIs that authorization responses may be sent to.
+	RedirectURIs datatypes.JSON `json:"redirect_uris" gorm:"type:jsonb; not null"`
+}
+
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+// HasRedirectURI returns true if the URI is one of the registered redirect URIs of this client.
+func (c *OAuthClient) HasRedirectURI(uri string) bool {
+	var uris []string
+	if err := json.Unmarshal(c.RedirectURIs, &uris); err != nil {
+		return false
+	}
+	for _, u := range uris {
+		if u == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// OAuthTokenKind distinguishes access tokens from refresh tokens issued by mcpjungle.
+type OAuthTokenKind string
+
+const (
+	OAuthTokenKindAccess  OAuthTokenKind = "access"
+	OAuthTokenKindRefresh OAuthTokenKind = "refresh"
+)
+
+// OAuthIssuedToken is a token issued by mcpjungle's authorization server.
+// The holder of an access token acts as the MCP client it was issued for, with the same allow list.
+// Only a hash of the token is stored.
+type OAuthIssuedToken struct {
+	gorm.Model
+
+	TokenHash string         `json:"-" gorm:"uniqueIndex;not null"`
+	Kind      OAuthTokenKind `json:"kind" gorm:"not null"`
+
+	// ClientID is the OAuth client that the token was issued to.
+	ClientID string `json:"client_id" gorm:"index;not null"`
+
+	// McpClientID is the MCP client whose identity the token grants.
+	McpClientID uint `json:"mcp_client_id" gorm:"index;not null"`
+
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (OAuthIssuedToken) TableName() string {
+	return "oauth_issued_tokens"
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// UpstreamOAuthToken is an OAuth token that mcpjungle obtained to access an upstream MCP server.
+// Tokens are keyed by server name because a user may authorize mcpjungle before the server is registered.
+type UpstreamOAuthToken struct {
+	gorm.Model
+
+	ServerName string `json:"server_name" gorm:"uniqueIndex;not null"`
+
+	AccessToken  string    `json:"-"`
+	RefreshToken string    `json:"-"`
+	TokenType    string    `json:"token_type"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+func (UpstreamOAuthToken) TableName() string {
+	return "upstream_oauth_tokens"
+}
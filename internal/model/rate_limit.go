@@ -0,0 +1,67 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// RateLimitScope describes which tool calls a rate limit applies to.
+type RateLimitScope string
+
+const (
+	// RateLimitScopeClient limits all tool calls made by an MCP client.
+	RateLimitScopeClient RateLimitScope = "client"
+	// RateLimitScopeClientServer limits the tool calls made by an MCP client to a specific MCP server.
+	RateLimitScopeClientServer RateLimitScope = "client_server"
+	// RateLimitScopeTool limits all calls to a specific tool, regardless of the caller.
+	RateLimitScopeTool RateLimitScope = "tool"
+)
+
+// RateLimit restricts how often tools can be called via mcpjungle.
+// A rate limit consists of a token bucket (RequestsPerMinute & Burst) and/or daily and monthly call quotas.
+// A value of 0 means that the corresponding restriction does not apply.
+type RateLimit struct {
+	gorm.Model
+
+	// Name uniquely identifies the rate limit so that it can be managed by the user.
+	Name string `json:"name" gorm:"uniqueIndex;not null"`
+
+	Scope RateLimitScope `json:"scope" gorm:"type:varchar(20);not null"`
+
+	// Client is the name of the MCP client this limit applies to.
+	// It is set for client and client_server scopes.
+	Client string `json:"client"`
+
+	// Server is the name of the MCP server this limit applies to.
+	// It is set for client_server scope.
+	Server string `json:"server"`
+
+	// Tool is the canonical name of the tool this limit applies to.
+	// It is set for tool scope.
+	Tool string `json:"tool"`
+
+	// RequestsPerMinute is the rate at which the token bucket is refilled.
+	RequestsPerMinute int `json:"requests_per_minute"`
+
+	// Burst is the capacity of the token bucket, ie, the maximum number of calls that can be made at once.
+	// If it is 0, the capacity is equal to RequestsPerMinute.
+	Burst int `json:"burst"`
+
+	// DailyQuota is the maximum number of calls allowed per calendar day (UTC).
+	DailyQuota int `json:"daily_quota"`
+
+	// MonthlyQuota is the maximum number of calls allowed per calendar month (UTC).
+	MonthlyQuota int `json:"monthly_quota"`
+}
+
+// RateLimitUsage counts the calls made under a rate limit during a quota period.
+// Usage counters are persisted so that quotas survive server restarts.
+type RateLimitUsage struct {
+	ID uint `gorm:"primarykey"`
+
+	RateLimitID uint `gorm:"uniqueIndex:idx_rate_limit_usage_period;not null"`
+
+	// Period identifies the quota period, eg- "2025-07-14" for a day or "2025-07" for a month.
+	Period string `gorm:"uniqueIndex:idx_rate_limit_usage_period;type:varchar(10);not null"`
+
+	Count int64 `gorm:"not null;default:0"`
+}
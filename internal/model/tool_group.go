@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ToolGroup is a curated set of tools across MCP servers, served by mcpjungle on its own MCP endpoint.
+// A tool belongs to the group if it is listed in IncludedTools, its server is listed in IncludedServers
+// or its labels match the Selector.
+type ToolGroup struct {
+	gorm.Model
+
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+
+	// IncludedTools is a JSON array of canonical tool names.
+	IncludedTools datatypes.JSON `json:"included_tools" gorm:"type:jsonb; not null"`
+
+	// IncludedServers is a JSON array of MCP server names, all of whose tools belong to the group.
+	IncludedServers datatypes.JSON `json:"included_servers" gorm:"type:jsonb; not null"`
+
+	// Selector is a label selector, all tools whose labels match it belong to the group.
+	// It is empty if the group does not select tools by label.
+	Selector string `json:"selector"`
+}
+
+// GetIncludedTools returns the canonical names of the tools listed in the group.
+func (g *ToolGroup) GetIncludedTools() ([]string, error) {
+	return decodeStringList(g.IncludedTools)
+}
+
+// GetIncludedServers returns the names of the MCP servers listed in the group.
+func (g *ToolGroup) GetIncludedServers() ([]string, error) {
+	return decodeStringList(g.IncludedServers)
+}
+
+func decodeStringList(v datatypes.JSON) ([]string, error) {
+	var list []string
+	if len(v) == 0 {
+		return list, nil
+	}
+	if err := json.Unmarshal(v, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ToolOverride customizes how the MCP proxy presents a tool to MCP clients.
+// It is stored separately from the Tool, whose fields are sourced from the MCP server, and is keyed by the
+// canonical name of the tool so that it survives the tools being re-fetched or the server being re-registered.
+type ToolOverride struct {
+	gorm.Model
+
+	// ToolName is the canonical name of the tool.
+	ToolName string `json:"tool_name" gorm:"uniqueIndex;not null"`
+
+	// Alias is an alternate name under which the proxy also exposes the tool.
+	Alias string `json:"alias"`
+
+	// Description replaces the description supplied by the MCP server, if set.
+	Description string `json:"description"`
+
+	// ParameterDescriptions is a JSON object mapping input parameter names to the descriptions
+	// that replace the ones in the tool's input schema.
+	ParameterDescriptions datatypes.JSON `json:"parameter_descriptions" gorm:"type:jsonb"`
+}
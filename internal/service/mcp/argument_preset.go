@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gorm.io/gorm/clause"
+	"log"
+	"maps"
+	"slices"
+)
+
+// initArgumentPresets loads all argument presets from the database.
+func (m *MCPService) initArgumentPresets() error {
+	presets, err := m.ListArgumentPresets()
+	if err != nil {
+		return err
+	}
+
+	m.presetsMu.Lock()
+	defer m.presetsMu.Unlock()
+	for _, p := range presets {
+		m.storePresetLocked(p)
+	}
+	return nil
+}
+
+// storePresetLocked caches a preset. The caller must hold presetsMu.
+func (m *MCPService) storePresetLocked(p *types.ArgumentPreset) {
+	if m.presets[p.Tool] == nil {
+		m.presets[p.Tool] = make(map[string]map[string]any)
+	}
+	m.presets[p.Tool][p.Client] = p.Arguments
+}
+
+// ListArgumentPresets returns all argument presets.
+func (m *MCPService) ListArgumentPresets() ([]*types.ArgumentPreset, error) {
+	var records []model.ArgumentPreset
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list argument presets: %w", err)
+	}
+	presets := make([]*types.ArgumentPreset, len(records))
+	for i, r := range records {
+		p := &types.ArgumentPreset{Tool: r.ToolName, Client: r.Client}
+		if err := json.Unmarshal(r.Arguments, &p.Arguments); err != nil {
+			return nil, fmt.Errorf("failed to decode preset arguments of tool %s: %w", r.ToolName, err)
+		}
+		presets[i] = p
+	}
+	return presets, nil
+}
+
+// SetArgumentPreset creates the argument preset of a tool (for a client) or replaces the existing one.
+// The preset arguments must be valid according to the tool's input schema.
+func (m *MCPService) SetArgumentPreset(p *types.ArgumentPreset) error {
+	if len(p.Arguments) == 0 {
+		return errors.New("a preset must set at least one argument")
+	}
+	tool, err := m.GetTool(p.Tool)
+	if err != nil {
+		return fmt.Errorf("tool %s does not exist: %w", p.Tool, err)
+	}
+	if err := validatePresetArguments(p.Tool, tool.InputSchema, p.Arguments); err != nil {
+		return err
+	}
+
+	args, err := json.Marshal(p.Arguments)
+	if err != nil {
+		return fmt.Errorf("failed to encode preset arguments: %w", err)
+	}
+	record := &model.ArgumentPreset{ToolName: p.Tool, Client: p.Client, Arguments: args}
+	err = m.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tool_name"}, {Name: "client"}},
+		DoUpdates: clause.AssignmentColumns([]string{"arguments", "updated_at"}),
+	}).Create(record).Error
+	if err != nil {
+		return fmt.Errorf("failed to save argument preset of tool %s: %w", p.Tool, err)
+	}
+
+	m.presetsMu.Lock()
+	m.storePresetLocked(p)
+	m.presetsMu.Unlock()
+	return nil
+}
+
+// DeleteArgumentPreset removes the argument preset of a tool for a client (or for all clients if client is empty).
+// It is an idempotent operation. Deleting a preset that does not exist does not return an error.
+func (m *MCPService) DeleteArgumentPreset(toolName, client string) error {
+	err := m.db.Unscoped().Where("tool_name = ? AND client = ?", toolName, client).
+		Delete(&model.ArgumentPreset{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete argument preset of tool %s: %w", toolName, err)
+	}
+
+	m.presetsMu.Lock()
+	defer m.presetsMu.Unlock()
+	delete(m.presets[toolName], client)
+	if len(m.presets[toolName]) == 0 {
+		delete(m.presets, toolName)
+	}
+	return nil
+}
+
+// presetArguments returns the preset arguments of a tool that apply to a client.
+// The client's own presets take precedence over the presets for all clients.
+func (m *MCPService) presetArguments(canonicalToolName, client string) map[string]any {
+	m.presetsMu.RLock()
+	defer m.presetsMu.RUnlock()
+
+	byClient := m.presets[canonicalToolName]
+	if len(byClient) == 0 {
+		return nil
+	}
+	args := make(map[string]any)
+	for k, v := range byClient[""] {
+		args[k] = v
+	}
+	if client != "" {
+		for k, v := range byClient[client] {
+			args[k] = v
+		}
+	}
+	return args
+}
+
+// applyArgumentPresets injects the preset arguments that apply to the client into a tool call.
+// Preset values replace any values supplied by the client.
+func (m *MCPService) applyArgumentPresets(request *mcp.CallToolRequest, canonicalToolName, client string) {
+	preset := m.presetArguments(canonicalToolName, client)
+	if len(preset) == 0 {
+		return
+	}
+	args := make(map[string]any)
+	for k, v := range request.GetArguments() {
+		args[k] = v
+	}
+	for k, v := range preset {
+		args[k] = v
+	}
+	request.Params.Arguments = args
+}
+
+// hidePresetParameters is a tool filter that removes the preset parameters from the input schemas
+// of the tools listed to a client, so that the LLM never sees (or tries to fill) them.
+func (m *MCPService) hidePresetParameters(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	client := ""
+	if c, ok := ctx.Value("client").(*model.McpClient); ok && c != nil {
+		client = c.Name
+	}
+	for i := range tools {
+		preset := m.presetArguments(m.resolveToolAlias(tools[i].Name), client)
+		if len(preset) == 0 {
+			continue
+		}
+		if err := removeSchemaParameters(&tools[i], preset); err != nil {
+			log.Printf("[WARN] failed to hide preset parameters of tool %s: %v", tools[i].Name, err)
+		}
+	}
+	return tools
+}
+
+// removeSchemaParameters removes the given parameters from the properties and required list of a tool's input schema.
+func removeSchemaParameters(tool *mcp.Tool, params map[string]any) error {
+	if len(tool.RawInputSchema) == 0 {
+		// the listed tools share their schemas with the tools registered in the proxy, so they are copied first
+		tool.InputSchema.Properties = maps.Clone(tool.InputSchema.Properties)
+		for p := range params {
+			delete(tool.InputSchema.Properties, p)
+		}
+		tool.InputSchema.Required = slices.DeleteFunc(slices.Clone(tool.InputSchema.Required), func(r string) bool {
+			_, ok := params[r]
+			return ok
+		})
+		return nil
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(tool.RawInputSchema, &schema); err != nil {
+		return err
+	}
+	if props, ok := schema["properties"].(map[string]any); ok {
+		for p := range params {
+			delete(props, p)
+		}
+	}
+	if required, ok := schema["required"].([]any); ok {
+		schema["required"] = slices.DeleteFunc(required, func(r any) bool {
+			s, _ := r.(string)
+			_, ok := params[s]
+			return ok
+		})
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	tool.RawInputSchema = raw
+	return nil
+}
+
+// validatePresetArguments checks that the preset arguments are parameters of the tool and that their values
+// are valid according to the tool's input schema. Parameters that are not preset are not required.
+func validatePresetArguments(name string, inputSchema []byte, args map[string]any) error {
+	if len(inputSchema) == 0 {
+		return fmt.Errorf("tool %s has no input parameters", name)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(inputSchema, &schema); err != nil {
+		return fmt.Errorf("failed to parse input schema of tool %s: %w", name, err)
+	}
+	props, _ := schema["properties"].(map[string]any)
+	for p := range args {
+		if _, ok := props[p]; !ok {
+			return fmt.Errorf("tool %s has no parameter named %s", name, p)
+		}
+	}
+
+	// the remaining parameters are supplied by the clients, so they are not required here
+	delete(schema, "required")
+	relaxed, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("failed to encode input schema of tool %s: %w", name, err)
+	}
+	compiled, err := compileInputSchema(name, relaxed)
+	if err != nil {
+		return fmt.Errorf("cannot validate preset arguments of tool %s: %w", name, err)
+	}
+
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("failed to serialize preset arguments: %w", err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("failed to parse preset arguments: %w", err)
+	}
+	if err := compiled.Validate(instance); err != nil {
+		var vErr *jsonschema.ValidationError
+		if errors.As(err, &vErr) {
+			return &ToolArgumentsError{Tool: name, Errors: argumentErrors(vErr)}
+		}
+		return fmt.Errorf("failed to validate preset arguments of tool %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"testing"
+)
+
+func TestArgumentPresets(t *testing.T) {
+	m := &MCPService{
+		presets: map[string]map[string]map[string]any{
+			"github__search_issues": {
+				"":       {"owner": "mcpjungle", "per_page": float64(10)},
+				"triage": {"owner": "acme"},
+			},
+		},
+		aliases: make(map[string]string),
+	}
+
+	tests := []struct {
+		name   string
+		client string
+		want   map[string]any
+	}{
+		{"all clients", "", map[string]any{"q": "bug", "owner": "mcpjungle", "per_page": float64(10)}},
+		{"client preset takes precedence", "triage", map[string]any{"q": "bug", "owner": "acme", "per_page": float64(10)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request mcp.CallToolRequest
+			request.Params.Arguments = map[string]any{"q": "bug", "owner": "someone-else"}
+			m.applyArgumentPresets(&request, "github__search_issues", tt.client)
+
+			got, _ := json.Marshal(request.GetArguments())
+			want, _ := json.Marshal(tt.want)
+			if string(got) != string(want) {
+				t.Errorf("expected arguments %s, got %s", want, got)
+			}
+		})
+	}
+
+	tools := []mcp.Tool{{
+		Name: "github__search_issues",
+		RawInputSchema: json.RawMessage(
+			`{"type":"object","properties":{"q":{"type":"string"},"owner":{"type":"string"},` +
+				`"per_page":{"type":"integer"}},"required":["q","owner"]}`,
+		),
+	}}
+	ctx := context.WithValue(context.Background(), "client", &model.McpClient{Name: "triage"})
+	listed := m.hidePresetParameters(ctx, tools)
+
+	var schema struct {
+		Properties map[string]any `json:"properties"`
+		Required   []string       `json:"required"`
+	}
+	if err := json.Unmarshal(listed[0].RawInputSchema, &schema); err != nil {
+		t.Fatalf("invalid input schema: %v", err)
+	}
+	if len(schema.Properties) != 1 || schema.Properties["q"] == nil {
+		t.Errorf("expected only the q parameter to be listed, got %v", schema.Properties)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "q" {
+		t.Errorf("expected only q to be required, got %v", schema.Required)
+	}
+}
+
+func TestValidatePresetArguments(t *testing.T) {
+	schema := []byte(
+		`{"type":"object","properties":{"owner":{"type":"string"},"per_page":{"type":"integer"}},` +
+			`"required":["q","owner"]}`,
+	)
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantErr bool
+	}{
+		{"valid", map[string]any{"owner": "mcpjungle"}, false},
+		{"unknown parameter", map[string]any{"org": "mcpjungle"}, true},
+		{"wrong type", map[string]any{"per_page": "ten"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePresetArguments("github__search_issues", schema, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package mcp
+
+import (
+	"container/list"
+	"encoding/json"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultCacheMaxEntries is the number of results cached per MCP server if the policy does not specify a limit.
+const defaultCacheMaxEntries = 1000
+
+// maxCachedResultSize is the size (in bytes) of the largest tool result that is cached.
+// Larger results are always fetched from the MCP server so that a few of them cannot exhaust memory.
+const maxCachedResultSize = 1 << 20
+
+// cacheEntry is a cached tool result.
+// The result is kept serialized, so that callers can neither modify the cached result nor each other's copies.
+type cacheEntry struct {
+	key       string
+	tool      string
+	result    []byte
+	expiresAt time.Time
+}
+
+// resultCache is an LRU cache of the tool results of a single MCP server with a TTL per entry.
+type resultCache struct {
+	mu sync.Mutex
+
+	ttl        time.Duration
+	maxEntries int
+
+	// lru holds the entries ordered from most to least recently used
+	lru     *list.List
+	entries map[string]*list.Element
+
+	hits   int64
+	misses int64
+
+	// now returns the current time, it can be replaced in tests
+	now func() time.Time
+}
+
+func newResultCache(p *types.CachePolicy) *resultCache {
+	c := &resultCache{
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+	c.configure(p)
+	return c
+}
+
+// configure applies the given policy to the cache without discarding the cached results.
+func (c *resultCache) configure(p *types.CachePolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl = time.Duration(p.TTLSec) * time.Second
+	c.maxEntries = defaultCacheMaxEntries
+	if p.MaxEntries > 0 {
+		c.maxEntries = p.MaxEntries
+	}
+	c.evict()
+}
+
+// get returns a copy of the cached result for the given key and records a hit or a miss.
+func (c *resultCache) get(key string) (*mcp.CallToolResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if ok && c.now().Before(el.Value.(*cacheEntry).expiresAt) {
+		var result mcp.CallToolResult
+		if err := json.Unmarshal(el.Value.(*cacheEntry).result, &result); err == nil {
+			c.lru.MoveToFront(el)
+			c.hits++
+			return &result, true
+		}
+	}
+	if ok {
+		// the entry has expired or cannot be decoded
+		c.remove(el)
+	}
+	c.misses++
+	return nil, false
+}
+
+// put caches a copy of the result of a call to the given tool.
+func (c *resultCache) put(key, tool string, result *mcp.CallToolResult) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{
+		key:       key,
+		tool:      tool,
+		result:    raw,
+		expiresAt: c.now().Add(c.ttl),
+	})
+	c.evict()
+}
+
+// purge removes the cached results of the given tool, or all cached results if tool is empty.
+// It returns the number of removed results.
+func (c *resultCache) purge(tool string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for el := c.lru.Front(); el != nil; {
+		next := el.Next()
+		if tool == "" || el.Value.(*cacheEntry).tool == tool {
+			c.remove(el)
+			n++
+		}
+		el = next
+	}
+	return n
+}
+
+// stats returns the number of cached results, hits and misses.
+func (c *resultCache) stats() (int, int64, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len(), c.hits, c.misses
+}
+
+// evict removes the least recently used entries until the cache is within its size limit.
+// The caller must hold c.mu.
+func (c *resultCache) evict() {
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove removes an entry from the cache. The caller must hold c.mu.
+func (c *resultCache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
+
+// cacheKey returns the key of a call to the given tool with the given arguments.
+// The key is the canonical tool name followed by the arguments serialized as JSON.
+// json.Marshal sorts map keys, so calls with the same arguments in a different order share a key.
+// It returns false if the arguments cannot be serialized.
+func cacheKey(canonicalName string, args any) (string, bool) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return canonicalName + "\x00" + string(raw), true
+}
+
+// isCacheableResult returns true if a tool result may be cached.
+// Error results are never cached because they are often transient.
+func isCacheableResult(result *mcp.CallToolResult) bool {
+	if result == nil || result.IsError {
+		return false
+	}
+	raw, err := json.Marshal(result)
+	return err == nil && len(raw) <= maxCachedResultSize
+}
+
+// isToolCacheable returns true if the results of the given tool may be cached as per the cache policy.
+// A tool is cacheable if it is listed in the policy or, if the policy lists no tools, if the MCP server
+// annotated it as read-only.
+func (m *MCPService) isToolCacheable(serverID uint, toolName string, p *types.CachePolicy) bool {
+	if len(p.Tools) > 0 {
+		return slices.Contains(p.Tools, toolName)
+	}
+	return isReadOnlyTool(m.toolAnnotations(serverID, toolName))
+}
+
+// isReadOnlyTool returns true if a tool is annotated as read-only.
+// An idempotent tool may still modify its environment (eg- set or delete), so its results are not cacheable:
+// answering a repeated call from the cache would skip the change the caller asked for.
+func isReadOnlyTool(annotations *mcp.ToolAnnotation) bool {
+	return annotations != nil && annotations.ReadOnlyHint != nil && *annotations.ReadOnlyHint
+}
+
+// getResultCache returns the result cache of the given MCP server as per its policy.
+// It returns nil if the server has no cache configured.
+func (m *MCPService) getResultCache(serverName string, p *types.CachePolicy) *resultCache {
+	m.cachesMu.Lock()
+	defer m.cachesMu.Unlock()
+
+	if p == nil {
+		delete(m.caches, serverName)
+		return nil
+	}
+	c, ok := m.caches[serverName]
+	if !ok {
+		c = newResultCache(p)
+		m.caches[serverName] = c
+		return c
+	}
+	// the policy might have changed since the cache was created
+	c.configure(p)
+	return c
+}
+
+// GetCacheStats returns the statistics of the result caches of all MCP servers, sorted by server name.
+func (m *MCPService) GetCacheStats() []types.CacheStats {
+	m.cachesMu.Lock()
+	defer m.cachesMu.Unlock()
+
+	stats := make([]types.CacheStats, 0, len(m.caches))
+	for name, c := range m.caches {
+		entries, hits, misses := c.stats()
+		stats = append(stats, types.CacheStats{Server: name, Entries: entries, Hits: hits, Misses: misses})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Server < stats[j].Server })
+	return stats
+}
+
+// PurgeCache removes cached tool results and returns the number of removed results.
+// entity can be an MCP server name or a canonical tool name, if it is empty, all cached results are removed.
+func (m *MCPService) PurgeCache(entity string) int {
+	m.cachesMu.Lock()
+	defer m.cachesMu.Unlock()
+
+	if entity == "" {
+		n := 0
+		for _, c := range m.caches {
+			n += c.purge("")
+		}
+		return n
+	}
+	serverName, toolName, _ := splitServerToolName(entity)
+	c, ok := m.caches[serverName]
+	if !ok {
+		return 0
+	}
+	return c.purge(toolName)
+}
+
+// forgetResultCache discards the result cache of an MCP server, eg- when it is deregistered.
+func (m *MCPService) forgetResultCache(serverName string) {
+	m.cachesMu.Lock()
+	defer m.cachesMu.Unlock()
+	delete(m.caches, serverName)
+}
@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestResultCache(t *testing.T) {
+	now := time.Now()
+	c := newResultCache(&types.CachePolicy{TTLSec: 60, MaxEntries: 2})
+	c.now = func() time.Time { return now }
+
+	c.put("a", "tool_a", mcp.NewToolResultText("a"))
+	c.put("b", "tool_b", mcp.NewToolResultText("b"))
+
+	if _, ok := c.get("a"); !ok {
+		t.Fatalf("expected a cache hit for key a")
+	}
+
+	// b is now the least recently used entry, so it must be evicted
+	c.put("c", "tool_c", mcp.NewToolResultText("c"))
+	if _, ok := c.get("b"); ok {
+		t.Errorf("expected key b to be evicted")
+	}
+	if _, ok := c.get("c"); !ok {
+		t.Errorf("expected a cache hit for key c")
+	}
+
+	now = now.Add(61 * time.Second)
+	if _, ok := c.get("a"); ok {
+		t.Errorf("expected key a to have expired")
+	}
+
+	entries, hits, misses := c.stats()
+	if entries != 1 || hits != 2 || misses != 2 {
+		t.Errorf("expected 1 entry, 2 hits and 2 misses, got %d entries, %d hits and %d misses", entries, hits, misses)
+	}
+
+	if n := c.purge("tool_c"); n != 1 {
+		t.Errorf("expected 1 purged entry, got %d", n)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	k1, ok1 := cacheKey("docs__search", map[string]any{"query": "gin", "limit": 5})
+	k2, ok2 := cacheKey("docs__search", map[string]any{"limit": 5, "query": "gin"})
+	if !ok1 || !ok2 || k1 != k2 {
+		t.Errorf("expected calls with the same arguments to share a cache key, got %q and %q", k1, k2)
+	}
+
+	k3, _ := cacheKey("docs__fetch", map[string]any{"query": "gin", "limit": 5})
+	if k1 == k3 {
+		t.Errorf("expected calls to different tools to have different cache keys")
+	}
+
+	k4, _ := cacheKey("docs__list", nil)
+	k5, _ := cacheKey("docs__list", map[string]any{})
+	if k4 != k5 {
+		t.Errorf("expected a call without arguments to match a call with empty arguments")
+	}
+}
+
+func TestResultCacheReturnsCopies(t *testing.T) {
+	c := newResultCache(&types.CachePolicy{TTLSec: 60})
+	result := mcp.NewToolResultText("original")
+	c.put("a", "tool_a", result)
+
+	// neither the caller that stored the result nor the ones reading it can change the cached result
+	result.Content[0] = mcp.NewTextContent("changed by the caller")
+	first, _ := c.get("a")
+	first.Content[0] = mcp.NewTextContent("changed by a reader")
+
+	second, ok := c.get("a")
+	if !ok {
+		t.Fatalf("expected a cache hit for key a")
+	}
+	if second == first {
+		t.Errorf("expected each hit to return its own copy of the result")
+	}
+	if text, ok := second.Content[0].(mcp.TextContent); !ok || text.Text != "original" {
+		t.Errorf("expected the cached result to be unchanged, got %#v", second.Content[0])
+	}
+}
+
+func TestIsReadOnlyTool(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations *mcp.ToolAnnotation
+		want        bool
+	}{
+		{name: "no annotations", annotations: nil, want: false},
+		{name: "read-only", annotations: &mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)}, want: true},
+		{
+			name: "idempotent but not read-only",
+			annotations: &mcp.ToolAnnotation{
+				ReadOnlyHint:   mcp.ToBoolPtr(false),
+				IdempotentHint: mcp.ToBoolPtr(true),
+			},
+			want: false,
+		},
+		{name: "idempotent only", annotations: &mcp.ToolAnnotation{IdempotentHint: mcp.ToBoolPtr(true)}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isReadOnlyTool(tt.annotations); got != tt.want {
+				t.Errorf("isReadOnlyTool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
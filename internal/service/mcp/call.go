@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"log"
+	"slices"
+	"time"
+)
+
+// callUpstreamTool calls a tool on an upstream MCP server and returns its result.
+// It is the single path through which both the MCP proxy and the HTTP API call tools, so it enforces
+// the server's policy: the result cache, the concurrency limit, the call timeout, retries of idempotent tools
+// and the circuit breaker. A call holds its concurrency slot across all of its retries.
+// The tool name in the request must NOT contain the server name prefix.
+func (m *MCPService) callUpstreamTool(
+	ctx context.Context, s *model.McpServer, request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	policy, err := s.GetPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy of MCP server %s: %w", s.Name, err)
+	}
+
+	// serve the result from the cache if possible, this bypasses all other policies
+	var cache *resultCache
+	var key string
+	if policy.Cache != nil && m.isToolCacheable(s.ID, request.Params.Name, policy.Cache) {
+		var ok bool
+		if key, ok = cacheKey(mergeServerToolNames(s.Name, request.Params.Name), request.Params.Arguments); ok {
+			cache = m.getResultCache(s.Name, policy.Cache)
+			if result, hit := cache.get(key); hit {
+				return result, nil
+			}
+		}
+	}
+
+	if limiter := m.getConcurrencyLimiter(s.Name, policy); limiter != nil {
+		release, err := limiter.acquire(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot call tool on MCP server %s: %w", s.Name, err)
+		}
+		defer release()
+	}
+
+	cb := m.getCircuitBreaker(s.Name, policy.CircuitBreaker)
+	if cb != nil {
+		if err := cb.allow(); err != nil {
+			return nil, fmt.Errorf("cannot call tool on MCP server %s: %w", s.Name, err)
+		}
+	}
+
+	maxAttempts := 1
+	if policy.Retry != nil && policy.Retry.MaxRetries > 0 && m.isToolRetryable(s.ID, request.Params.Name, policy.Retry) {
+		maxAttempts += policy.Retry.MaxRetries
+	}
+
+	var result *mcp.CallToolResult
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			backoff := retryBackoff(policy.Retry, attempt-1)
+			log.Printf(
+				"[WARN] call to tool %s on MCP server %s failed, retrying in %s (attempt %d/%d): %v",
+				request.Params.Name, s.Name, backoff, attempt, maxAttempts, err,
+			)
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+			case <-time.After(backoff):
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		result, err = m.callUpstreamToolOnce(ctx, s, policy, request)
+		if !isServerFailure(ctx, err) {
+			// don't retry if the call succeeded, the server rejected it or the caller has given up
+			break
+		}
+	}
+
+	if cb != nil {
+		if ctx.Err() != nil {
+			// the caller gave up, which says nothing about the health of the server
+			cb.release()
+		} else {
+			cb.recordResult(!isServerFailure(ctx, err))
+		}
+	}
+	if err == nil && cache != nil && isCacheableResult(result) {
+		cache.put(key, request.Params.Name, result)
+	}
+	return result, err
+}
+
+// callUpstreamToolOnce opens a new session with the MCP server and calls the tool within the call timeout.
+// Progress notifications sent by the server are relayed to the caller, and so are its sampling & elicitation
+// requests if the caller supports them. If the caller gives up or the call times out, the server is notified
+// that the call has been cancelled.
+func (m *MCPService) callUpstreamToolOnce(
+	ctx context.Context, s *model.McpServer, policy *types.ServerPolicy, request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	timeout := callTimeout(policy)
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	mcpClient, err := m.newMcpServerSession(callCtx, s, m.downstreamClientOptions(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	defer mcpClient.Close()
+
+	m.relayProgressNotifications(ctx, mcpClient, request)
+
+	// the request is sent through the transport directly so that its ID is known in case it must be cancelled
+	id := mcp.NewRequestId("mcpjungle-tools-call")
+	resp, err := mcpClient.GetTransport().SendRequest(callCtx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      id,
+		Method:  string(mcp.MethodToolsCall),
+		Params:  request.Params,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			cancelUpstreamRequest(mcpClient, id, "the client cancelled the call")
+			return nil, fmt.Errorf(
+				"call to tool %s on MCP server %s was cancelled: %w", request.Params.Name, s.Name, ctx.Err(),
+			)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			cancelUpstreamRequest(mcpClient, id, "the call timed out")
+			return nil, fmt.Errorf(
+				"call to tool %s on MCP server %s timed out after %s", request.Params.Name, s.Name, timeout,
+			)
+		}
+		return nil, fmt.Errorf("failed to call tool %s on MCP server %s: %w", request.Params.Name, s.Name, err)
+	}
+	if resp.Error != nil {
+		return nil, &upstreamRPCError{err: fmt.Errorf(
+			"failed to call tool %s on MCP server %s: %w", request.Params.Name, s.Name, resp.Error.AsError(),
+		)}
+	}
+	return mcp.ParseCallToolResult(&resp.Result)
+}
+
+// upstreamRPCError is a JSON-RPC error response of an MCP server to a tool call (eg- invalid params).
+// The server has handled the call, so it is neither retried nor counted as a failure of the server.
+type upstreamRPCError struct {
+	err error
+}
+
+func (e *upstreamRPCError) Error() string { return e.err.Error() }
+func (e *upstreamRPCError) Unwrap() error { return e.err }
+
+// isServerFailure returns true if a call failed because the MCP server could not be reached or did not answer
+// in time. Only such failures are retried and counted by the circuit breaker.
+// Errors returned by the server, missing authorizations and calls given up by the caller are not.
+func isServerFailure(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var rpcErr *upstreamRPCError
+	return !errors.As(err, &rpcErr) && !errors.Is(err, ErrOAuthAuthorizationRequired)
+}
+
+// retryBackoff returns the delay before the given retry (starting at 1) of a call.
+// The delay doubles with every retry.
+func retryBackoff(p *types.RetryPolicy, retry int) time.Duration {
+	return time.Duration(p.BackoffMs) * time.Millisecond << (retry - 1)
+}
+
+// isToolRetryable returns true if calls to the given tool may be retried safely.
+// A tool is retryable if it is listed in the retry policy or if the MCP server annotated it
+// as idempotent or read-only.
+func (m *MCPService) isToolRetryable(serverID uint, toolName string, p *types.RetryPolicy) bool {
+	if slices.Contains(p.Tools, toolName) {
+		return true
+	}
+	annotations := m.toolAnnotations(serverID, toolName)
+	if annotations == nil {
+		return false
+	}
+	isTrue := func(b *bool) bool { return b != nil && *b }
+	return isTrue(annotations.IdempotentHint) || isTrue(annotations.ReadOnlyHint)
+}
+
+// toolAnnotations returns the annotations of a tool as published by its MCP server.
+// It returns nil if the tool does not exist or has no annotations.
+func (m *MCPService) toolAnnotations(serverID uint, toolName string) *mcp.ToolAnnotation {
+	var tool model.Tool
+	if err := m.db.Where("server_id = ? AND name = ?", serverID, toolName).First(&tool).Error; err != nil {
+		return nil
+	}
+	if len(tool.Annotations) == 0 {
+		return nil
+	}
+	var annotations mcp.ToolAnnotation
+	if err := json.Unmarshal(tool.Annotations, &annotations); err != nil {
+		return nil
+	}
+	return &annotations
+}
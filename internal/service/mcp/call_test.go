@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	p := &types.RetryPolicy{MaxRetries: 3, BackoffMs: 100}
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := retryBackoff(p, tt.retry); got != tt.want {
+			t.Errorf("retryBackoff(%d) = %s, want %s", tt.retry, got, tt.want)
+		}
+	}
+}
+
+// flakyUpstream is an MCP server whose first sessions fail to initialize, as if it could not be reached.
+type flakyUpstream struct {
+	url string
+	// failures is the number of sessions that still have to fail
+	failures atomic.Int32
+	// badCalls counts the calls to the tool that returns a JSON-RPC error
+	badCalls atomic.Int32
+}
+
+func newFlakyUpstream(t *testing.T) *flakyUpstream {
+	u := &flakyUpstream{}
+	s := server.NewMCPServer("upstream", "0.0.1")
+	s.AddTool(mcp.NewTool("echo"), func(ctx context.Context, r mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	s.AddTool(mcp.NewTool("bad"), func(ctx context.Context, r mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		u.badCalls.Add(1)
+		return nil, errors.New("invalid arguments")
+	})
+	handler := server.NewStreamableHTTPServer(s)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if bytes.Contains(body, []byte(`"initialize"`)) && u.failures.Add(-1) >= 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	u.url = ts.URL
+	return u
+}
+
+func TestCallUpstreamToolRetries(t *testing.T) {
+	upstream := newFlakyUpstream(t)
+	config, _ := json.Marshal(model.StreamableHTTPConfig{URL: upstream.url})
+	policy, _ := json.Marshal(types.ServerPolicy{
+		Retry:          &types.RetryPolicy{MaxRetries: 2, BackoffMs: 1, Tools: []string{"echo", "bad"}},
+		CircuitBreaker: &types.CircuitBreakerPolicy{FailureThreshold: 1, CooldownSec: 60},
+	})
+	s := &model.McpServer{Name: "upstream", Transport: types.TransportStreamableHTTP, Config: config, Policy: policy}
+
+	newService := func() *MCPService {
+		return &MCPService{
+			breakers:       make(map[string]*circuitBreaker),
+			limiters:       make(map[string]*concurrencyLimiter),
+			caches:         make(map[string]*resultCache),
+			upstreamTokens: make(map[string]*upstreamOAuthToken),
+		}
+	}
+	call := func(m *MCPService, ctx context.Context, tool string) error {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool
+		_, err := m.callUpstreamTool(ctx, s, request)
+		return err
+	}
+
+	t.Run("unreachable server is retried", func(t *testing.T) {
+		m := newService()
+		upstream.failures.Store(2)
+		if err := call(m, context.Background(), "echo"); err != nil {
+			t.Fatalf("expected the call to succeed after 2 retries, got %v", err)
+		}
+		if got := m.circuitBreakerState("upstream"); got != types.CircuitClosed {
+			t.Errorf("expected the circuit to be closed, got %s", got)
+		}
+	})
+
+	t.Run("retries are exhausted", func(t *testing.T) {
+		m := newService()
+		upstream.failures.Store(3)
+		if err := call(m, context.Background(), "echo"); err == nil {
+			t.Fatal("expected an error once all retries have failed")
+		}
+		if upstream.failures.Load() > 0 {
+			t.Errorf("expected 3 attempts, %d attempts were not made", upstream.failures.Load())
+		}
+		if got := m.circuitBreakerState("upstream"); got != types.CircuitOpen {
+			t.Errorf("expected the circuit to open, got %s", got)
+		}
+	})
+
+	t.Run("json-rpc errors are not retried", func(t *testing.T) {
+		m := newService()
+		upstream.failures.Store(0)
+		upstream.badCalls.Store(0)
+		if err := call(m, context.Background(), "bad"); err == nil {
+			t.Fatal("expected the error of the server")
+		}
+		if got := upstream.badCalls.Load(); got != 1 {
+			t.Errorf("expected the tool to be called once, got %d", got)
+		}
+		if got := m.circuitBreakerState("upstream"); got != types.CircuitClosed {
+			t.Errorf("expected the circuit to stay closed, got %s", got)
+		}
+	})
+
+	t.Run("cancelled calls are not counted", func(t *testing.T) {
+		m := newService()
+		upstream.failures.Store(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := call(m, ctx, "echo"); err == nil {
+			t.Fatal("expected an error for a cancelled call")
+		}
+		if got := m.circuitBreakerState("upstream"); got != types.CircuitClosed {
+			t.Errorf("expected the circuit to stay closed, got %s", got)
+		}
+	})
+}
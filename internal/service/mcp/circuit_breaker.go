@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"sync"
+	"time"
+)
+
+// defaultCircuitBreakerCooldown is the time a circuit stays open if the policy does not specify a cooldown.
+const defaultCircuitBreakerCooldown = 30 * time.Second
+
+// ErrCircuitOpen is returned when a tool call is rejected because the circuit breaker
+// of the MCP server is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitBreaker fails calls to an MCP server fast after it has failed repeatedly.
+// Once the cooldown has elapsed, a single trial call is let through (half-open state).
+// If the trial call succeeds, the circuit closes again, otherwise it re-opens for another cooldown.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	failureThreshold int
+	cooldown         time.Duration
+
+	state               types.CircuitBreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	trialInFlight       bool
+
+	// now returns the current time, it can be replaced in tests
+	now func() time.Time
+}
+
+func newCircuitBreaker(p *types.CircuitBreakerPolicy) *circuitBreaker {
+	cb := &circuitBreaker{
+		state: types.CircuitClosed,
+		now:   time.Now,
+	}
+	cb.configure(p)
+	return cb
+}
+
+// configure applies the given policy to the circuit breaker without resetting its state.
+func (cb *circuitBreaker) configure(p *types.CircuitBreakerPolicy) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failureThreshold = p.FailureThreshold
+	cb.cooldown = defaultCircuitBreakerCooldown
+	if p.CooldownSec > 0 {
+		cb.cooldown = time.Duration(p.CooldownSec) * time.Second
+	}
+}
+
+// allow returns nil if a call may be forwarded to the MCP server.
+// Otherwise, it returns an error wrapping ErrCircuitOpen.
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case types.CircuitOpen:
+		remaining := cb.cooldown - cb.now().Sub(cb.openedAt)
+		if remaining > 0 {
+			return fmt.Errorf("%w, retry after %s", ErrCircuitOpen, remaining.Round(time.Second))
+		}
+		// cooldown has elapsed, let a trial call through
+		cb.state = types.CircuitHalfOpen
+		cb.trialInFlight = true
+		return nil
+	case types.CircuitHalfOpen:
+		if cb.trialInFlight {
+			return fmt.Errorf("%w, a trial call is in progress", ErrCircuitOpen)
+		}
+		cb.trialInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// recordResult updates the circuit breaker with the outcome of a call that was allowed through.
+func (cb *circuitBreaker) recordResult(success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.trialInFlight = false
+	if success {
+		cb.state = types.CircuitClosed
+		cb.consecutiveFailures = 0
+		return
+	}
+
+	cb.consecutiveFailures++
+	if cb.state == types.CircuitHalfOpen || cb.consecutiveFailures >= cb.failureThreshold {
+		cb.state = types.CircuitOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// release lets go of a call that was allowed through without recording its outcome,
+// eg- because the caller gave up on it. If it was the trial call, another one may be let through.
+func (cb *circuitBreaker) release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.trialInFlight = false
+}
+
+// currentState returns the state of the circuit breaker.
+func (cb *circuitBreaker) currentState() types.CircuitBreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// getCircuitBreaker returns the circuit breaker for the given MCP server as per its policy.
+// It returns nil if the server has no circuit breaker configured.
+func (m *MCPService) getCircuitBreaker(serverName string, p *types.CircuitBreakerPolicy) *circuitBreaker {
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+
+	if p == nil {
+		delete(m.breakers, serverName)
+		return nil
+	}
+	cb, ok := m.breakers[serverName]
+	if !ok {
+		cb = newCircuitBreaker(p)
+		m.breakers[serverName] = cb
+		return cb
+	}
+	// the policy might have changed since the breaker was created
+	cb.configure(p)
+	return cb
+}
+
+// circuitBreakerState returns the state of the circuit breaker of the given MCP server.
+// It returns an empty state if the server does not have a circuit breaker.
+func (m *MCPService) circuitBreakerState(serverName string) types.CircuitBreakerState {
+	m.breakersMu.Lock()
+	cb, ok := m.breakers[serverName]
+	m.breakersMu.Unlock()
+
+	if !ok {
+		return ""
+	}
+	return cb.currentState()
+}
+
+// forgetCircuitBreaker discards the circuit breaker of an MCP server, eg- when it is deregistered.
+func (m *MCPService) forgetCircuitBreaker(serverName string) {
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+	delete(m.breakers, serverName)
+}
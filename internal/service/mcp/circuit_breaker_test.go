@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"errors"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	cb := newCircuitBreaker(&types.CircuitBreakerPolicy{FailureThreshold: 2, CooldownSec: 10})
+	cb.now = func() time.Time { return now }
+
+	// the circuit stays closed until the failure threshold is reached
+	if err := cb.allow(); err != nil {
+		t.Fatalf("allow() on closed circuit returned error: %v", err)
+	}
+	cb.recordResult(false)
+	if got := cb.currentState(); got != types.CircuitClosed {
+		t.Fatalf("state after 1 failure = %s, want %s", got, types.CircuitClosed)
+	}
+
+	// a success resets the failure count
+	cb.recordResult(true)
+	cb.recordResult(false)
+	if got := cb.currentState(); got != types.CircuitClosed {
+		t.Fatalf("state after success and 1 failure = %s, want %s", got, types.CircuitClosed)
+	}
+
+	cb.recordResult(false)
+	if got := cb.currentState(); got != types.CircuitOpen {
+		t.Fatalf("state after 2 consecutive failures = %s, want %s", got, types.CircuitOpen)
+	}
+	if err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() on open circuit = %v, want ErrCircuitOpen", err)
+	}
+
+	// after the cooldown, exactly one trial call is allowed
+	now = now.Add(11 * time.Second)
+	if err := cb.allow(); err != nil {
+		t.Fatalf("allow() after cooldown returned error: %v", err)
+	}
+	if got := cb.currentState(); got != types.CircuitHalfOpen {
+		t.Fatalf("state after cooldown = %s, want %s", got, types.CircuitHalfOpen)
+	}
+	if err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second allow() during trial call = %v, want ErrCircuitOpen", err)
+	}
+
+	// a failed trial call re-opens the circuit immediately
+	cb.recordResult(false)
+	if got := cb.currentState(); got != types.CircuitOpen {
+		t.Fatalf("state after failed trial = %s, want %s", got, types.CircuitOpen)
+	}
+
+	// a successful trial call closes the circuit
+	now = now.Add(11 * time.Second)
+	if err := cb.allow(); err != nil {
+		t.Fatalf("allow() after second cooldown returned error: %v", err)
+	}
+	cb.recordResult(true)
+	if got := cb.currentState(); got != types.CircuitClosed {
+		t.Fatalf("state after successful trial = %s, want %s", got, types.CircuitClosed)
+	}
+}
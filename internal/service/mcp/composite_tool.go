@@ -0,0 +1,405 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// validStepID restricts step IDs to characters that can be used in template references, eg- {{.steps.lookup}}.
+var validStepID = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// singleReference matches a template that consists of a single reference, eg- {{ .steps.lookup.id }}.
+var singleReference = regexp.MustCompile(`^\{\{\s*\.([a-zA-Z0-9_]+(?:\.[a-zA-Z0-9_]+)*)\s*\}\}$`)
+
+// ErrCompositeToolNotFound is returned when a composite tool does not exist.
+var ErrCompositeToolNotFound = errors.New("composite tool not found")
+
+// defaultCompositeInputSchema is the input schema of composite tools that don't take any input.
+var defaultCompositeInputSchema = json.RawMessage(`{"type":"object","properties":{}}`)
+
+// initCompositeTools adds all composite tools stored in the database to the MCP proxy.
+func (m *MCPService) initCompositeTools() error {
+	tools, err := m.ListCompositeTools()
+	if err != nil {
+		return err
+	}
+	for _, t := range tools {
+		m.addCompositeProxyTool(t)
+	}
+	return nil
+}
+
+func (m *MCPService) addCompositeProxyTool(t *types.CompositeTool) {
+	m.addProxyTools(server.ServerTool{
+		Tool: mcp.Tool{
+			Name:           t.Name,
+			Description:    t.Description,
+			RawInputSchema: t.InputSchema,
+		},
+		Handler: m.compositeToolCallHandler,
+	})
+}
+
+func compositeToolModelToType(r *model.CompositeTool) (*types.CompositeTool, error) {
+	t := &types.CompositeTool{
+		Name:        r.Name,
+		Description: r.Description,
+		InputSchema: json.RawMessage(r.InputSchema),
+		Output:      r.Output,
+	}
+	if err := json.Unmarshal(r.Steps, &t.Steps); err != nil {
+		return nil, fmt.Errorf("failed to decode steps of composite tool %s: %w", r.Name, err)
+	}
+	return t, nil
+}
+
+// ListCompositeTools returns all composite tools.
+func (m *MCPService) ListCompositeTools() ([]*types.CompositeTool, error) {
+	var records []model.CompositeTool
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list composite tools: %w", err)
+	}
+	tools := make([]*types.CompositeTool, len(records))
+	for i := range records {
+		t, err := compositeToolModelToType(&records[i])
+		if err != nil {
+			return nil, err
+		}
+		tools[i] = t
+	}
+	return tools, nil
+}
+
+// GetCompositeTool returns the composite tool with the given name.
+func (m *MCPService) GetCompositeTool(name string) (*types.CompositeTool, error) {
+	var r model.CompositeTool
+	if err := m.db.Where("name = ?", name).First(&r).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCompositeToolNotFound
+		}
+		return nil, fmt.Errorf("failed to get composite tool %s: %w", name, err)
+	}
+	return compositeToolModelToType(&r)
+}
+
+// CreateCompositeTool validates and stores a composite tool, and exposes it on the MCP proxy.
+// The tools called by its steps must exist. Aliases used in the steps are replaced by canonical names.
+func (m *MCPService) CreateCompositeTool(t *types.CompositeTool) error {
+	if err := m.validateCompositeTool(t); err != nil {
+		return err
+	}
+
+	steps, err := json.Marshal(t.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to encode steps: %w", err)
+	}
+	record := &model.CompositeTool{
+		Name:        t.Name,
+		Description: t.Description,
+		InputSchema: []byte(t.InputSchema),
+		Steps:       steps,
+		Output:      t.Output,
+	}
+	if err := m.db.Create(record).Error; err != nil {
+		return fmt.Errorf("failed to create composite tool %s: %w", t.Name, err)
+	}
+
+	m.addCompositeProxyTool(t)
+	return nil
+}
+
+// DeleteCompositeTool deletes a composite tool and removes it from the MCP proxy.
+// It is an idempotent operation. Deleting a composite tool that does not exist does not return an error.
+func (m *MCPService) DeleteCompositeTool(name string) error {
+	res := m.db.Unscoped().Where("name = ?", name).Delete(&model.CompositeTool{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete composite tool %s: %w", name, res.Error)
+	}
+	if res.RowsAffected > 0 {
+		m.deleteProxyTools(name)
+		m.forgetInputSchemas(name)
+	}
+	return nil
+}
+
+// validateCompositeTool checks the definition of a new composite tool and fills in its defaults.
+func (m *MCPService) validateCompositeTool(t *types.CompositeTool) error {
+	if !validToolAlias.MatchString(t.Name) || strings.Contains(t.Name, serverToolNameSep) {
+		return fmt.Errorf(
+			"invalid name: '%s' must follow the regular expression %s and must not contain %s",
+			t.Name, validToolAlias, serverToolNameSep,
+		)
+	}
+	if _, err := m.GetCompositeTool(t.Name); err == nil {
+		return fmt.Errorf("composite tool %s already exists", t.Name)
+	}
+	if canonical := m.resolveToolAlias(t.Name); canonical != t.Name {
+		return fmt.Errorf("name %s is already used as an alias of tool %s", t.Name, canonical)
+	}
+
+	if len(t.InputSchema) == 0 {
+		t.InputSchema = defaultCompositeInputSchema
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(t.InputSchema, &schema); err != nil {
+		return fmt.Errorf("invalid input schema: %w", err)
+	}
+	if schema["type"] != "object" {
+		return errors.New("invalid input schema: the type of the input must be object")
+	}
+	if _, err := compileInputSchema(t.Name, t.InputSchema); err != nil {
+		return fmt.Errorf("invalid input schema: %w", err)
+	}
+
+	if len(t.Steps) == 0 {
+		return errors.New("a composite tool must have at least one step")
+	}
+	seen := make(map[string]bool, len(t.Steps))
+	for i := range t.Steps {
+		s := &t.Steps[i]
+		if !validStepID.MatchString(s.ID) {
+			return fmt.Errorf("invalid step id: '%s' must follow the regular expression %s", s.ID, validStepID)
+		}
+		if seen[s.ID] {
+			return fmt.Errorf("step id %s is used more than once", s.ID)
+		}
+		seen[s.ID] = true
+
+		s.Tool = m.resolveToolAlias(s.Tool)
+		if _, err := m.GetTool(s.Tool); err != nil {
+			return fmt.Errorf("tool %s of step %s does not exist: %w", s.Tool, s.ID, err)
+		}
+		if err := parseTemplates(s.Arguments); err != nil {
+			return fmt.Errorf("invalid arguments of step %s: %w", s.ID, err)
+		}
+	}
+	if _, err := template.New("output").Parse(t.Output); err != nil {
+		return fmt.Errorf("invalid output: %w", err)
+	}
+	return nil
+}
+
+// compositeToolCallHandler handles calls to composite tools made through the MCP proxy.
+// Each step is handled exactly like a call of the client to the step's tool, so access control,
+// argument presets, validation and rate limits apply to every step.
+func (m *MCPService) compositeToolCallHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	t, err := m.GetCompositeTool(request.Params.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	input := request.GetArguments()
+	if input == nil {
+		input = map[string]any{}
+	}
+	if err := m.validateCompositeInput(t, input); err != nil {
+		if result, ok := toolArgumentsErrorResult(err); ok {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	// cancelling the composite call cancels the step in progress
+	ctx, done := m.trackProxyCall(ctx, request)
+	defer done()
+
+	return runCompositeTool(ctx, t, input, m.mcpProxyToolCallHandler)
+}
+
+// invokeCompositeTool calls a composite tool on behalf of the API.
+// Each step is handled exactly like a tool invoked via the API.
+func (m *MCPService) invokeCompositeTool(ctx context.Context, name string, input map[string]any) (*mcp.CallToolResult, error) {
+	t, err := m.GetCompositeTool(name)
+	if err != nil {
+		if errors.Is(err, ErrCompositeToolNotFound) {
+			return nil, fmt.Errorf(
+				"invalid input: tool name does not contain a %s separator and is not a composite tool", serverToolNameSep,
+			)
+		}
+		return nil, err
+	}
+	if input == nil {
+		input = map[string]any{}
+	}
+	if err := m.validateCompositeInput(t, input); err != nil {
+		return nil, err
+	}
+	return runCompositeTool(ctx, t, input, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return m.invokeUpstreamTool(ctx, request.Params.Name, request.GetArguments())
+	})
+}
+
+// runCompositeTool calls the steps of a composite tool in order using callStep and returns the composite result.
+func runCompositeTool(
+	ctx context.Context, t *types.CompositeTool, input map[string]any, callStep server.ToolHandlerFunc,
+) (*mcp.CallToolResult, error) {
+	steps := make(map[string]any, len(t.Steps))
+	data := map[string]any{"input": input, "steps": steps}
+
+	var result *mcp.CallToolResult
+	for _, s := range t.Steps {
+		args, err := renderTemplates(s.Arguments, data)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to build the arguments of step %s: %v", s.ID, err)), nil
+		}
+
+		var stepRequest mcp.CallToolRequest
+		stepRequest.Params.Name = s.Tool
+		stepRequest.Params.Arguments = args
+		result, err = callStep(ctx, stepRequest)
+		if err != nil {
+			return nil, fmt.Errorf("step %s (%s) of composite tool %s failed: %w", s.ID, s.Tool, t.Name, err)
+		}
+		if result.IsError {
+			return mcp.NewToolResultError(
+				fmt.Sprintf("step %s (%s) failed: %s", s.ID, s.Tool, resultText(result)),
+			), nil
+		}
+		steps[s.ID] = stepResultValue(result)
+	}
+
+	if t.Output == "" {
+		return result, nil
+	}
+	out, err := renderTemplate(t.Output, data)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to build the output: %v", err)), nil
+	}
+	return mcp.NewToolResultText(out), nil
+}
+
+// validateCompositeInput validates the input of a composite tool call against the tool's input schema.
+func (m *MCPService) validateCompositeInput(t *types.CompositeTool, input map[string]any) error {
+	schema, err := m.inputSchema(t.Name, t.InputSchema)
+	if err != nil {
+		return fmt.Errorf("failed to compile input schema of composite tool %s: %w", t.Name, err)
+	}
+	return validateArguments(t.Name, schema, input)
+}
+
+// resultText returns the text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, c := range result.Content {
+		if tc, ok := mcp.AsTextContent(c); ok {
+			texts = append(texts, tc.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// stepResultValue returns the value of a step's result that later steps can refer to.
+// It is the structured content of the result if there is one, otherwise its text content
+// parsed as JSON if possible, or the text itself.
+func stepResultValue(result *mcp.CallToolResult) any {
+	var v any
+	if result.StructuredContent != nil {
+		// round-trip through JSON so that templates can navigate it as maps and slices
+		raw, err := json.Marshal(result.StructuredContent)
+		if err == nil && json.Unmarshal(raw, &v) == nil {
+			return v
+		}
+	}
+	text := resultText(result)
+	if err := json.Unmarshal([]byte(text), &v); err == nil {
+		return v
+	}
+	return text
+}
+
+// parseTemplates checks the syntax of the templates in the arguments of a step.
+func parseTemplates(v any) error {
+	switch v := v.(type) {
+	case string:
+		_, err := template.New("argument").Parse(v)
+		return err
+	case map[string]any:
+		for _, e := range v {
+			if err := parseTemplates(e); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, e := range v {
+			if err := parseTemplates(e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renderTemplates renders the templates in the arguments of a step.
+// Strings that consist of a single reference are replaced by the referenced value, so that it keeps its type.
+func renderTemplates(args map[string]any, data map[string]any) (map[string]any, error) {
+	rendered := make(map[string]any, len(args))
+	for k, v := range args {
+		r, err := renderTemplateValue(v, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		rendered[k] = r
+	}
+	return rendered, nil
+}
+
+func renderTemplateValue(v any, data map[string]any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		if m := singleReference.FindStringSubmatch(v); m != nil {
+			return resolveReference(data, strings.Split(m[1], "."))
+		}
+		return renderTemplate(v, data)
+	case map[string]any:
+		return renderTemplates(v, data)
+	case []any:
+		rendered := make([]any, len(v))
+		for i, e := range v {
+			r, err := renderTemplateValue(e, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered[i] = r
+		}
+		return rendered, nil
+	default:
+		return v, nil
+	}
+}
+
+func renderTemplate(text string, data map[string]any) (string, error) {
+	tmpl, err := template.New("template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// resolveReference returns the value found by following a path of keys from data.
+func resolveReference(data map[string]any, path []string) (any, error) {
+	var v any = data
+	for i, key := range path {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf(".%s is not an object", strings.Join(path[:i], "."))
+		}
+		if v, ok = obj[key]; !ok {
+			return nil, fmt.Errorf(".%s does not exist", strings.Join(path[:i+1], "."))
+		}
+	}
+	return v, nil
+}
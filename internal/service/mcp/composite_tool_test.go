@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRenderTemplates(t *testing.T) {
+	data := map[string]any{
+		"input": map[string]any{"repo": "mcpjungle/MCPJungle", "limit": float64(5)},
+		"steps": map[string]any{
+			"lookup": map[string]any{"id": float64(42), "labels": []any{"bug"}},
+			"echo":   "hello",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "single references keep their type",
+			args: map[string]any{"id": "{{.steps.lookup.id}}", "labels": "{{ .steps.lookup.labels }}"},
+			want: `{"id":42,"labels":["bug"]}`,
+		},
+		{
+			name: "text templates render strings",
+			args: map[string]any{"q": "repo:{{.input.repo}} limit:{{.input.limit}}", "greeting": "{{.steps.echo}}!"},
+			want: `{"greeting":"hello!","q":"repo:mcpjungle/MCPJungle limit:5"}`,
+		},
+		{
+			name: "nested values and literals",
+			args: map[string]any{"filter": map[string]any{"ids": []any{"{{.steps.lookup.id}}", float64(7)}}, "open": true},
+			want: `{"filter":{"ids":[42,7]},"open":true}`,
+		},
+		{name: "missing reference", args: map[string]any{"id": "{{.steps.missing.id}}"}, wantErr: true},
+		{name: "missing key in template", args: map[string]any{"q": "x {{.input.nope}}"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderTemplates(tt.args, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			raw, _ := json.Marshal(got)
+			if string(raw) != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, raw)
+			}
+		})
+	}
+}
+
+func TestStepResultValue(t *testing.T) {
+	structured := mcp.NewToolResultStructured(map[string]any{"id": 1}, `{"id":1}`)
+	tests := []struct {
+		name   string
+		result *mcp.CallToolResult
+		want   string
+	}{
+		{"structured content", structured, `{"id":1}`},
+		{"JSON text", mcp.NewToolResultText(`[1, 2]`), `[1,2]`},
+		{"plain text", mcp.NewToolResultText("done"), `"done"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, _ := json.Marshal(stepResultValue(tt.result))
+			if string(raw) != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, raw)
+			}
+		})
+	}
+}
+
+func TestInvokeCompositeTool(t *testing.T) {
+	db := newTestDB(t)
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	proxy := server.NewMCPServer("test", "0.0.1", server.WithToolCapabilities(true))
+	m, err := NewMCPService(db, proxy, &server.Hooks{}, ratelimit.NewRateLimitService(db))
+	if err != nil {
+		t.Fatalf("failed to create MCP service: %v", err)
+	}
+
+	upstream := server.NewMCPServer("upstream", "0.0.1")
+	upstream.AddTool(
+		mcp.NewTool("echo", mcp.WithString("text", mcp.Required())),
+		func(ctx context.Context, r mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(r.GetString("text", "")), nil
+		},
+	)
+	ts := httptest.NewServer(server.NewStreamableHTTPServer(upstream))
+	defer ts.Close()
+	s, err := model.NewStreamableHTTPServer("upstream", "", model.StreamableHTTPConfig{URL: ts.URL + "/mcp"})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("failed to register server: %v", err)
+	}
+	err = m.CreateCompositeTool(&types.CompositeTool{
+		Name:        "shout",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"}},"required":["text"]}`),
+		Steps: []types.CompositeToolStep{
+			{ID: "first", Tool: "upstream__echo", Arguments: map[string]any{"text": "{{.input.text}}"}},
+			{ID: "second", Tool: "upstream__echo", Arguments: map[string]any{"text": "{{.steps.first}}!"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create composite tool: %v", err)
+	}
+
+	// composite tools can be invoked via the API just like the tools of MCP servers
+	result, err := m.InvokeTool(context.Background(), "shout", map[string]any{"text": "hi"})
+	if err != nil {
+		t.Fatalf("failed to invoke composite tool: %v", err)
+	}
+	if result.IsError || len(result.Content) != 1 || result.Content[0]["text"] != "hi!" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	// the input of the composite tool is validated
+	_, err = m.InvokeTool(context.Background(), "shout", nil)
+	var argsErr *ToolArgumentsError
+	if !errors.As(err, &argsErr) {
+		t.Errorf("expected a validation error for missing input, got %v", err)
+	}
+
+	if _, err := m.InvokeTool(context.Background(), "unknown", nil); err == nil {
+		t.Errorf("expected an error for an unknown tool")
+	}
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"sync"
+	"time"
+)
+
+// ErrServerBusy is returned when a tool call is rejected because the MCP server is already handling
+// the maximum number of concurrent calls and the call could not be queued.
+var ErrServerBusy = errors.New("MCP server is busy")
+
+// concurrencyLimiter bounds the number of tool calls in progress on an MCP server.
+// Calls beyond the limit wait in a FIFO queue until a slot frees up, the queue timeout elapses
+// or the caller gives up.
+type concurrencyLimiter struct {
+	// slots is a semaphore, a call holds a slot by sending into it
+	slots chan struct{}
+
+	mu           sync.Mutex
+	maxQueueSize int
+	queueTimeout time.Duration
+	queued       int
+}
+
+func newConcurrencyLimiter(p *types.ServerPolicy) *concurrencyLimiter {
+	l := &concurrencyLimiter{slots: make(chan struct{}, p.MaxConcurrency)}
+	l.configure(p)
+	return l
+}
+
+// configure applies the queue settings of the policy to the limiter.
+// The concurrency limit itself cannot be changed, a new limiter must be created instead.
+func (l *concurrencyLimiter) configure(p *types.ServerPolicy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.maxQueueSize = p.MaxQueueSize
+	l.queueTimeout = time.Duration(p.QueueTimeoutSec) * time.Second
+}
+
+// acquire waits for a free slot and returns a function that must be called to release it.
+// It returns an error wrapping ErrServerBusy if the queue is full or the queue timeout elapsed.
+func (l *concurrencyLimiter) acquire(ctx context.Context) (func(), error) {
+	release := func() { <-l.slots }
+
+	// fast path: a slot is free right away
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	l.mu.Lock()
+	if l.maxQueueSize > 0 && l.queued >= l.maxQueueSize {
+		l.mu.Unlock()
+		return nil, fmt.Errorf("%w, its queue is full (%d calls waiting)", ErrServerBusy, l.maxQueueSize)
+	}
+	l.queued++
+	queueTimeout := l.queueTimeout
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		l.queued--
+		l.mu.Unlock()
+	}()
+
+	var timeout <-chan time.Time
+	if queueTimeout > 0 {
+		t := time.NewTimer(queueTimeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-timeout:
+		return nil, fmt.Errorf("%w, timed out after waiting %s in its queue", ErrServerBusy, queueTimeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// stats returns the number of calls in progress and the number of calls waiting in the queue.
+func (l *concurrencyLimiter) stats() (int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.slots), l.queued
+}
+
+// getConcurrencyLimiter returns the concurrency limiter for the given MCP server as per its policy.
+// It returns nil if the server has no concurrency limit configured.
+func (m *MCPService) getConcurrencyLimiter(serverName string, p *types.ServerPolicy) *concurrencyLimiter {
+	m.limitersMu.Lock()
+	defer m.limitersMu.Unlock()
+
+	if p.MaxConcurrency == 0 {
+		delete(m.limiters, serverName)
+		return nil
+	}
+	l, ok := m.limiters[serverName]
+	if !ok || cap(l.slots) != p.MaxConcurrency {
+		// the concurrency limit has changed since the limiter was created.
+		// Calls in progress release their slots in the old limiter, so the new limit is not
+		// strictly enforced until they finish.
+		l = newConcurrencyLimiter(p)
+		m.limiters[serverName] = l
+		return l
+	}
+	l.configure(p)
+	return l
+}
+
+// concurrencyStats returns the number of calls in progress and waiting for the given MCP server.
+// It returns zeros if the server does not have a concurrency limit configured.
+func (m *MCPService) concurrencyStats(serverName string) (int, int) {
+	m.limitersMu.Lock()
+	l, ok := m.limiters[serverName]
+	m.limitersMu.Unlock()
+
+	if !ok {
+		return 0, 0
+	}
+	return l.stats()
+}
+
+// forgetConcurrencyLimiter discards the concurrency limiter of an MCP server, eg- when it is deregistered.
+func (m *MCPService) forgetConcurrencyLimiter(serverName string) {
+	m.limitersMu.Lock()
+	defer m.limitersMu.Unlock()
+	delete(m.limiters, serverName)
+}
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	t.Run("rejects calls when the queue is full", func(t *testing.T) {
+		l := newConcurrencyLimiter(&types.ServerPolicy{MaxConcurrency: 1, MaxQueueSize: 1})
+
+		release, err := l.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("expected first call to acquire a slot, got %v", err)
+		}
+
+		acquired := make(chan error)
+		go func() {
+			r, err := l.acquire(context.Background())
+			if err == nil {
+				r()
+			}
+			acquired <- err
+		}()
+
+		// wait for the second call to enter the queue
+		for {
+			if _, queued := l.stats(); queued == 1 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		if _, err := l.acquire(context.Background()); !errors.Is(err, ErrServerBusy) {
+			t.Fatalf("expected ErrServerBusy for a call beyond the queue size, got %v", err)
+		}
+
+		release()
+		if err := <-acquired; err != nil {
+			t.Fatalf("expected queued call to acquire a slot after release, got %v", err)
+		}
+		if active, queued := l.stats(); active != 0 || queued != 0 {
+			t.Errorf("expected no active or queued calls, got %d active and %d queued", active, queued)
+		}
+	})
+
+	t.Run("rejects calls that wait longer than the queue timeout", func(t *testing.T) {
+		l := newConcurrencyLimiter(&types.ServerPolicy{MaxConcurrency: 1})
+		l.queueTimeout = 10 * time.Millisecond
+
+		release, err := l.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("expected first call to acquire a slot, got %v", err)
+		}
+		defer release()
+
+		if _, err := l.acquire(context.Background()); !errors.Is(err, ErrServerBusy) {
+			t.Fatalf("expected ErrServerBusy after the queue timeout, got %v", err)
+		}
+	})
+
+	t.Run("stops waiting when the caller gives up", func(t *testing.T) {
+		l := newConcurrencyLimiter(&types.ServerPolicy{MaxConcurrency: 1})
+
+		release, err := l.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("expected first call to acquire a slot, got %v", err)
+		}
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := l.acquire(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	})
+}
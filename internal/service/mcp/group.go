@@ -0,0 +1,289 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"maps"
+	"regexp"
+	"slices"
+)
+
+// validGroupName restricts group names to characters that are safe to use in the group's URL path.
+var validGroupName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ErrToolGroupNotFound is returned when a tool group does not exist.
+var ErrToolGroupNotFound = errors.New("tool group not found")
+
+// toolGroupProxy is the MCP server that serves the tools of a tool group.
+type toolGroupProxy struct {
+	server   *server.MCPServer
+	tools    map[string]bool
+	servers  map[string]bool
+	selector types.LabelSelector
+}
+
+// includes returns true if the tool with the given canonical name belongs to the group.
+// labelsOf is only called if the group selects tools by label.
+func (g *toolGroupProxy) includes(canonicalToolName string, labelsOf func(string) map[string]string) bool {
+	if g.tools[canonicalToolName] {
+		return true
+	}
+	serverName, _, ok := splitServerToolName(canonicalToolName)
+	if ok && g.servers[serverName] {
+		return true
+	}
+	return len(g.selector) > 0 && g.selector.Matches(labelsOf(canonicalToolName))
+}
+
+// addProxyTools adds tools to the MCP proxy, to the tool search index and to every tool group that
+// includes them. The tool names must be in their canonical form. Tool overrides are applied and aliased
+// tools are also added under their alias.
+func (m *MCPService) addProxyTools(tools ...server.ServerTool) {
+	tools, canonicalNames := m.applyToolOverrides(tools)
+	m.mcpProxyServer.AddTools(tools...)
+	for _, t := range tools {
+		m.toolSearch.add(t.Tool)
+	}
+
+	m.groupsMu.RLock()
+	defer m.groupsMu.RUnlock()
+	labelsOf := m.toolLabelsLookup(canonicalNames)
+	for _, g := range m.groups {
+		var included []server.ServerTool
+		for i, t := range tools {
+			if g.includes(canonicalNames[i], labelsOf) {
+				included = append(included, t)
+			}
+		}
+		if len(included) > 0 {
+			g.server.AddTools(included...)
+		}
+	}
+}
+
+// addProxyTool adds a single tool to the MCP proxy and to every tool group that includes it.
+func (m *MCPService) addProxyTool(tool mcp.Tool) {
+	m.addProxyTools(server.ServerTool{Tool: tool, Handler: m.mcpProxyToolCallHandler})
+}
+
+// deleteProxyTools removes tools, along with their aliases, from the MCP proxy, from the tool search index
+// and from every tool group. The tool names must be in their canonical form.
+func (m *MCPService) deleteProxyTools(names ...string) {
+	names, canonicalNames := m.withToolAliases(names)
+	m.mcpProxyServer.DeleteTools(names...)
+	m.toolSearch.delete(names...)
+
+	m.groupsMu.RLock()
+	defer m.groupsMu.RUnlock()
+	for _, g := range m.groups {
+		var included []string
+		for i, n := range names {
+			// the labels of deleted tools may not be available anymore, so the groups that select tools
+			// by label are assumed to include them
+			if len(g.selector) > 0 || g.includes(canonicalNames[i], nil) {
+				included = append(included, n)
+			}
+		}
+		if len(included) > 0 {
+			g.server.DeleteTools(included...)
+		}
+	}
+}
+
+// canonicalToolNames maps the names of tools served by the MCP proxy to the canonical names of their tools.
+// Aliased tools are served under their alias too.
+func (m *MCPService) canonicalToolNames(served map[string]*server.ServerTool) map[string]string {
+	names := make(map[string]string, len(served))
+	for name := range served {
+		names[name] = m.resolveToolAlias(name)
+	}
+	return names
+}
+
+// newToolGroupProxy creates the MCP server of a tool group and fills it with the group's tools
+// that are currently served by the MCP proxy.
+func (m *MCPService) newToolGroupProxy(g *model.ToolGroup) (*toolGroupProxy, error) {
+	tools, err := g.GetIncludedTools()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tools of group %s: %w", g.Name, err)
+	}
+	servers, err := g.GetIncludedServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode servers of group %s: %w", g.Name, err)
+	}
+	selector, err := types.ParseLabelSelector(g.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse selector of group %s: %w", g.Name, err)
+	}
+
+	hooks := &server.Hooks{}
+	p := &toolGroupProxy{
+		server: server.NewMCPServer(
+			"MCPJungle Proxy MCP Server - "+g.Name,
+			"0.0.1",
+			server.WithToolCapabilities(true),
+			server.WithHooks(hooks),
+		),
+		tools:    make(map[string]bool, len(tools)),
+		servers:  make(map[string]bool, len(servers)),
+		selector: selector,
+	}
+	m.registerProxyHooks(p.server, hooks)
+	for _, t := range tools {
+		p.tools[t] = true
+	}
+	for _, s := range servers {
+		p.servers[s] = true
+	}
+
+	// the MCP proxy only contains the enabled tools of healthy servers, so it is the source of truth.
+	// it serves aliased tools under their alias too, which belong to the group if their tool does.
+	served := m.mcpProxyServer.ListTools()
+	canonicalNames := m.canonicalToolNames(served)
+	labelsOf := m.toolLabelsLookup(slices.Collect(maps.Values(canonicalNames)))
+
+	var included []server.ServerTool
+	for name, t := range served {
+		if p.includes(canonicalNames[name], labelsOf) {
+			included = append(included, *t)
+		}
+	}
+	if len(included) > 0 {
+		p.server.AddTools(included...)
+	}
+	return p, nil
+}
+
+// initToolGroups creates the MCP servers of all tool groups stored in the database.
+// It must be called after the MCP proxy has been initialized.
+func (m *MCPService) initToolGroups() error {
+	groups, err := m.ListToolGroups()
+	if err != nil {
+		return err
+	}
+
+	m.groupsMu.Lock()
+	defer m.groupsMu.Unlock()
+	for i := range groups {
+		p, err := m.newToolGroupProxy(&groups[i])
+		if err != nil {
+			return err
+		}
+		m.groups[groups[i].Name] = p
+	}
+	return nil
+}
+
+// ListToolGroups returns all tool groups.
+func (m *MCPService) ListToolGroups() ([]model.ToolGroup, error) {
+	var groups []model.ToolGroup
+	if err := m.db.Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tool groups: %w", err)
+	}
+	return groups, nil
+}
+
+// GetToolGroup returns the tool group with the given name.
+func (m *MCPService) GetToolGroup(name string) (*model.ToolGroup, error) {
+	var g model.ToolGroup
+	if err := m.db.Where("name = ?", name).First(&g).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrToolGroupNotFound
+		}
+		return nil, fmt.Errorf("failed to get tool group %s: %w", name, err)
+	}
+	return &g, nil
+}
+
+// CreateToolGroup creates a tool group from the given tools, servers and label selector, and starts serving it.
+// All the tools and servers must exist when the group is created.
+// Tools of servers registered later, tools re-enabled later and tools whose labels change to match
+// the selector are added to the group automatically.
+func (m *MCPService) CreateToolGroup(
+	name, description string, tools, servers []string, selector string,
+) (*model.ToolGroup, error) {
+	if name == "" || !validGroupName.MatchString(name) {
+		return nil, fmt.Errorf("invalid group name: '%s' must follow the regular expression %s", name, validGroupName)
+	}
+	sel, err := types.ParseLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(tools) == 0 && len(servers) == 0 && len(sel) == 0 {
+		return nil, errors.New("a tool group must include at least one tool or server, or select tools by label")
+	}
+	for _, t := range tools {
+		if _, err := m.GetTool(t); err != nil {
+			return nil, fmt.Errorf("tool %s does not exist: %w", t, err)
+		}
+	}
+	for _, s := range servers {
+		if _, err := m.GetMcpServer(s); err != nil {
+			return nil, fmt.Errorf("MCP server %s does not exist: %w", s, err)
+		}
+	}
+
+	if tools == nil {
+		tools = []string{}
+	}
+	if servers == nil {
+		servers = []string{}
+	}
+	toolsJSON, err := json.Marshal(tools)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tools: %w", err)
+	}
+	serversJSON, err := json.Marshal(servers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode servers: %w", err)
+	}
+	g := &model.ToolGroup{
+		Name:            name,
+		Description:     description,
+		IncludedTools:   toolsJSON,
+		IncludedServers: serversJSON,
+		Selector:        sel.String(),
+	}
+
+	m.groupsMu.Lock()
+	defer m.groupsMu.Unlock()
+	p, err := m.newToolGroupProxy(g)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.db.Create(g).Error; err != nil {
+		return nil, fmt.Errorf("failed to create tool group: %w", err)
+	}
+	m.groups[name] = p
+	return g, nil
+}
+
+// DeleteToolGroup deletes a tool group and stops serving it.
+// It is an idempotent operation. Deleting a group that does not exist does not return an error.
+func (m *MCPService) DeleteToolGroup(name string) error {
+	m.groupsMu.Lock()
+	defer m.groupsMu.Unlock()
+	if err := m.db.Unscoped().Where("name = ?", name).Delete(&model.ToolGroup{}).Error; err != nil {
+		return fmt.Errorf("failed to delete tool group %s: %w", name, err)
+	}
+	delete(m.groups, name)
+	return nil
+}
+
+// GetToolGroupProxy returns the MCP server that serves the tools of a tool group.
+// The server is replaced when a group is deleted and created again, so callers must not hold on to it.
+func (m *MCPService) GetToolGroupProxy(name string) (*server.MCPServer, error) {
+	m.groupsMu.RLock()
+	defer m.groupsMu.RUnlock()
+	p, ok := m.groups[name]
+	if !ok {
+		return nil, ErrToolGroupNotFound
+	}
+	return p.server, nil
+}
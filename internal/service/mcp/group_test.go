@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestToolGroupSync(t *testing.T) {
+	m := &MCPService{
+		mcpProxyServer: server.NewMCPServer("test", "0.0.1", server.WithToolCapabilities(true)),
+		groups:         make(map[string]*toolGroupProxy),
+		toolSearch:     newToolSearchIndex(),
+	}
+	m.addProxyTool(mcp.NewTool("github__git_commit"))
+	m.addProxyTool(mcp.NewTool("slack__post_message"))
+
+	g, err := m.newToolGroupProxy(&model.ToolGroup{
+		Name:            "chat",
+		IncludedTools:   []byte(`["slack__post_message"]`),
+		IncludedServers: []byte(`["calculator"]`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m.groups["chat"] = g
+
+	groupTools := func() []string {
+		return slices.Sorted(maps.Keys(g.server.ListTools()))
+	}
+	if got := groupTools(); !slices.Equal(got, []string{"slack__post_message"}) {
+		t.Errorf("expected the group to start with the tools it includes, got %v", got)
+	}
+
+	// tools of included servers join the group as they show up in the proxy
+	m.addProxyTool(mcp.NewTool("calculator__add"))
+	m.addProxyTool(mcp.NewTool("github__create_issue"))
+	if got := groupTools(); !slices.Equal(got, []string{"calculator__add", "slack__post_message"}) {
+		t.Errorf("unexpected tools in group: %v", got)
+	}
+
+	// and leave it when they are removed from the proxy, eg- when disabled or deregistered
+	m.deleteProxyTools("calculator__add", "slack__post_message", "github__git_commit")
+	if got := groupTools(); len(got) != 0 {
+		t.Errorf("expected the group to be empty, got %v", got)
+	}
+	if m.mcpProxyServer.GetTool("github__create_issue") == nil {
+		t.Errorf("expected the proxy to keep tools that were not deleted")
+	}
+}
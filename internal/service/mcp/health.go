@@ -0,0 +1,236 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"log"
+	"sync"
+	"time"
+)
+
+// healthCheckFailureThreshold is the number of consecutive failed probes after which an
+// MCP server is considered unhealthy and its tools are hidden from the MCP proxy.
+const healthCheckFailureThreshold = 2
+
+// healthCheckProbeTimeout is the maximum time a single health probe of an MCP server may take.
+const healthCheckProbeTimeout = 30 * time.Second
+
+// serverHealth is the health of an upstream MCP server as observed by the health checker.
+type serverHealth struct {
+	healthy             bool
+	consecutiveFailures int
+	lastCheckedAt       time.Time
+	lastError           string
+}
+
+// StartHealthChecker starts probing all registered MCP servers in the background at the given interval.
+// OpenAPI servers run inside mcpjungle, so they are never probed.
+// If a server fails healthCheckFailureThreshold consecutive probes, its tools are temporarily removed from
+// the MCP proxy until the server recovers. The Enabled flag of the tools in the DB is never modified.
+// The checker stops when ctx is cancelled. A non-positive interval disables health checking.
+func (m *MCPService) StartHealthChecker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.runHealthChecks(ctx)
+			}
+		}
+	}()
+}
+
+// GetServerStatus returns the runtime status of the given MCP server.
+func (m *MCPService) GetServerStatus(s *model.McpServer) *types.McpServerStatus {
+	status := &types.McpServerStatus{Health: types.HealthUnknown}
+
+	m.healthMu.RLock()
+	if h, ok := m.health[s.Name]; ok {
+		status.Health = types.HealthHealthy
+		if !h.healthy {
+			status.Health = types.HealthUnhealthy
+		}
+		checkedAt := h.lastCheckedAt
+		status.LastCheckedAt = &checkedAt
+		status.LastError = h.lastError
+	}
+	m.healthMu.RUnlock()
+
+	if p, err := s.GetPolicy(); err == nil && p.CircuitBreaker != nil {
+		status.CircuitBreaker = m.circuitBreakerState(s.Name)
+		if status.CircuitBreaker == "" {
+			// no calls have been made to the server yet
+			status.CircuitBreaker = types.CircuitClosed
+		}
+	}
+	status.ActiveCalls, status.QueuedCalls = m.concurrencyStats(s.Name)
+	return status
+}
+
+// runHealthChecks probes all registered MCP servers concurrently and waits for the probes to finish.
+func (m *MCPService) runHealthChecks(ctx context.Context) {
+	servers, err := m.ListMcpServers()
+	if err != nil {
+		log.Printf("[ERROR] health check: failed to list MCP servers from DB: %v", err)
+		return
+	}
+	var wg sync.WaitGroup
+	for i := range servers {
+		if servers[i].Transport == types.TransportOpenAPI {
+			continue
+		}
+		wg.Add(1)
+		go func(s *model.McpServer) {
+			defer wg.Done()
+			m.recordProbeResult(s, m.probeServer(ctx, s))
+		}(&servers[i])
+	}
+	wg.Wait()
+}
+
+// probeServer opens a new session with the MCP server and pings it.
+func (m *MCPService) probeServer(ctx context.Context, s *model.McpServer) error {
+	probeCtx, cancel := context.WithTimeout(ctx, healthCheckProbeTimeout)
+	defer cancel()
+
+	c, err := m.newMcpServerSession(probeCtx, s)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err := c.Ping(probeCtx); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	return nil
+}
+
+// recordProbeResult updates the health of an MCP server based on the outcome of a probe.
+// If the server transitions between healthy and unhealthy, its tools are hidden from or restored to the proxy.
+// The proxy is changed while holding healthMu, which DeregisterMcpServer also holds, so the result of a probe that
+// finishes after its server was deregistered is discarded instead of bringing back the tools of the server.
+func (m *MCPService) recordProbeResult(s *model.McpServer, probeErr error) {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+
+	registered, err := m.isServerRegistered(s)
+	if err != nil {
+		log.Printf("[ERROR] health check: failed to look up MCP server %s: %v", s.Name, err)
+		return
+	}
+	if !registered {
+		return
+	}
+
+	h, ok := m.health[s.Name]
+	if !ok {
+		// a server is assumed to be healthy until proven otherwise
+		h = &serverHealth{healthy: true}
+		m.health[s.Name] = h
+	}
+	h.lastCheckedAt = time.Now()
+
+	wasHealthy := h.healthy
+	if probeErr == nil {
+		h.healthy = true
+		h.consecutiveFailures = 0
+		h.lastError = ""
+	} else {
+		h.consecutiveFailures++
+		h.lastError = probeErr.Error()
+		if h.consecutiveFailures >= healthCheckFailureThreshold {
+			h.healthy = false
+		}
+	}
+
+	if wasHealthy == h.healthy {
+		return
+	}
+	if h.healthy {
+		log.Printf("[INFO] MCP server %s has recovered, restoring its tools in the proxy", s.Name)
+		if err := m.showServerTools(s); err != nil {
+			log.Printf("[ERROR] failed to restore tools of MCP server %s in the proxy: %v", s.Name, err)
+		}
+	} else {
+		log.Printf("[WARN] MCP server %s is unreachable, hiding its tools from the proxy: %v", s.Name, probeErr)
+		if err := m.hideServerTools(s); err != nil {
+			log.Printf("[ERROR] failed to hide tools of MCP server %s from the proxy: %v", s.Name, err)
+		}
+	}
+}
+
+// isServerRegistered returns true if the MCP server is still registered, and has not been replaced
+// by another server with the same name.
+func (m *MCPService) isServerRegistered(s *model.McpServer) (bool, error) {
+	var count int64
+	if err := m.db.Model(&model.McpServer{}).Where("id = ? AND name = ?", s.ID, s.Name).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// isServerHealthy returns false only if the health checker has marked the server as unhealthy.
+// Servers that have not been probed yet are considered healthy.
+func (m *MCPService) isServerHealthy(name string) bool {
+	m.healthMu.RLock()
+	defer m.healthMu.RUnlock()
+
+	h, ok := m.health[name]
+	return !ok || h.healthy
+}
+
+// hideServerTools removes all enabled tools of an MCP server from the proxy without modifying the DB.
+func (m *MCPService) hideServerTools(s *model.McpServer) error {
+	tools, err := m.listEnabledServerTools(s)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(tools))
+	for i, t := range tools {
+		names[i] = mergeServerToolNames(s.Name, t.Name)
+	}
+	if len(names) > 0 {
+		m.deleteProxyTools(names...)
+	}
+	return nil
+}
+
+// showServerTools adds all enabled tools of an MCP server back to the proxy.
+func (m *MCPService) showServerTools(s *model.McpServer) error {
+	tools, err := m.listEnabledServerTools(s)
+	if err != nil {
+		return err
+	}
+	serverTools := make([]server.ServerTool, 0, len(tools))
+	for i := range tools {
+		mcpTool, err := convertToolModelToMcpObject(&tools[i])
+		if err != nil {
+			return fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", tools[i].Name, err)
+		}
+		mcpTool.Name = mergeServerToolNames(s.Name, tools[i].Name)
+		serverTools = append(serverTools, server.ServerTool{Tool: mcpTool, Handler: m.mcpProxyToolCallHandler})
+	}
+	if len(serverTools) > 0 {
+		m.addProxyTools(serverTools...)
+	}
+	return nil
+}
+
+// listEnabledServerTools returns the enabled tools of an MCP server from the DB.
+// The tool names are NOT in their canonical form.
+func (m *MCPService) listEnabledServerTools(s *model.McpServer) ([]model.Tool, error) {
+	var tools []model.Tool
+	if err := m.db.Where("server_id = ? AND enabled = ?", s.ID, true).Find(&tools).Error; err != nil {
+		return nil, fmt.Errorf("failed to get enabled tools for server %s from DB: %w", s.Name, err)
+	}
+	return tools, nil
+}
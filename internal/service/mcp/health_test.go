@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"testing"
+)
+
+func TestRecordProbeResult(t *testing.T) {
+	db := newTestDB(t, &model.McpServer{}, &model.Tool{})
+	s := &model.McpServer{Name: "github", Transport: types.TransportStreamableHTTP, Config: []byte("{}")}
+	if err := db.Create(s).Error; err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	tool := &model.Tool{ServerID: s.ID, Name: "create_issue", Enabled: true, InputSchema: []byte(`{"type":"object"}`)}
+	if err := db.Create(tool).Error; err != nil {
+		t.Fatalf("failed to create tool: %v", err)
+	}
+	m := &MCPService{
+		db:             db,
+		mcpProxyServer: server.NewMCPServer("test", "0.0.1", server.WithToolCapabilities(true)),
+		groups:         make(map[string]*toolGroupProxy),
+		toolSearch:     newToolSearchIndex(),
+		health:         make(map[string]*serverHealth),
+	}
+	if err := m.showServerTools(s); err != nil {
+		t.Fatalf("failed to add tools to the proxy: %v", err)
+	}
+
+	served := func() bool { return m.mcpProxyServer.GetTool("github__create_issue") != nil }
+	probeErr := errors.New("connection refused")
+
+	// a single failed probe is not enough to hide the tools of a server
+	m.recordProbeResult(s, probeErr)
+	if !m.isServerHealthy("github") || !served() {
+		t.Fatalf("expected the server to stay healthy after 1 failed probe")
+	}
+
+	m.recordProbeResult(s, probeErr)
+	if m.isServerHealthy("github") || served() {
+		t.Fatalf("expected the tools to be hidden after %d failed probes", healthCheckFailureThreshold)
+	}
+	if status := m.GetServerStatus(s); status.Health != types.HealthUnhealthy || status.LastError != probeErr.Error() {
+		t.Errorf("expected an unhealthy status with the probe error, got %+v", status)
+	}
+
+	// further failures leave the server unhealthy, a successful probe restores its tools
+	m.recordProbeResult(s, probeErr)
+	if served() {
+		t.Fatalf("expected the tools to stay hidden")
+	}
+	m.recordProbeResult(s, nil)
+	if !m.isServerHealthy("github") || !served() {
+		t.Fatalf("expected the tools to be restored once the server recovers")
+	}
+
+	// the server goes down and is deregistered while a probe is in flight
+	m.recordProbeResult(s, probeErr)
+	m.recordProbeResult(s, probeErr)
+	if err := m.DeregisterMcpServer("github"); err != nil {
+		t.Fatalf("failed to deregister server: %v", err)
+	}
+	m.recordProbeResult(s, nil)
+	if served() {
+		t.Errorf("expected a late probe not to bring back the tools of a deregistered server")
+	}
+	if _, ok := m.health["github"]; ok {
+		t.Errorf("expected a late probe not to record the health of a deregistered server")
+	}
+}
+
+func TestRunHealthChecksSkipsOpenAPIServers(t *testing.T) {
+	db := newTestDB(t, &model.McpServer{}, &model.Tool{})
+	s := &model.McpServer{Name: "petstore", Transport: types.TransportOpenAPI, Config: []byte("{}")}
+	if err := db.Create(s).Error; err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	m := &MCPService{db: db, health: make(map[string]*serverHealth)}
+
+	// openapi servers run in-process, there is nothing to probe
+	m.runHealthChecks(context.Background())
+	if status := m.GetServerStatus(s); status.Health != types.HealthUnknown {
+		t.Errorf("expected the health of an openapi server to be unknown, got %s", status.Health)
+	}
+}
@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"log"
+	"net/http"
+	"time"
+)
+
+// proxyRequestIDHeader is the internal header through which the ID of a downstream tools/call request is
+// handed from the proxy hooks to the tool call handler, because mcp-go does not pass it to handlers.
+// It is removed before the request is forwarded upstream.
+const proxyRequestIDHeader = "Mcpjungle-Request-Id"
+
+// mcp-go does not define constants for these notification methods
+const (
+	methodNotificationCancelled = "notifications/cancelled"
+	methodNotificationProgress  = "notifications/progress"
+)
+
+// upstreamCancelTimeout is the maximum time spent notifying an MCP server that a call has been cancelled.
+const upstreamCancelTimeout = 5 * time.Second
+
+// inflightCallKey identifies a tool call in progress in the MCP proxy.
+// Request IDs are only unique within a session, so the session ID is part of the key.
+type inflightCallKey struct {
+	sessionID string
+	requestID string
+}
+
+// registerProxyHooks installs the hooks and notification handlers that let the proxy
+// cancel upstream tool calls when the downstream client cancels them and keep track of
+// the capabilities of downstream clients. It also installs the tool filter that hides preset parameters.
+// They are installed on the MCP proxy as well as on the MCP servers of tool groups.
+func (m *MCPService) registerProxyHooks(proxy *server.MCPServer, hooks *server.Hooks) {
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, request *mcp.CallToolRequest) {
+		if request.Header == nil {
+			request.Header = make(http.Header)
+		}
+		request.Header.Set(proxyRequestIDHeader, fmt.Sprint(id))
+	})
+	hooks.AddAfterInitialize(m.recordClientCapabilities)
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		m.forgetClientCapabilities(session.SessionID())
+	})
+	proxy.AddNotificationHandler(methodNotificationCancelled, m.handleCancelledNotification)
+	server.WithToolFilter(m.hidePresetParameters)(proxy)
+}
+
+// trackProxyCall registers a tool call received by the proxy so that it can be cancelled by the client.
+// It returns a context that is cancelled when the client cancels the call, and a function that must be
+// called once the call is over.
+func (m *MCPService) trackProxyCall(ctx context.Context, request mcp.CallToolRequest) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	session := server.ClientSessionFromContext(ctx)
+	requestID := request.Header.Get(proxyRequestIDHeader)
+	if session == nil || requestID == "" {
+		return ctx, cancel
+	}
+	key := inflightCallKey{sessionID: session.SessionID(), requestID: requestID}
+
+	m.inflightMu.Lock()
+	m.inflight[key] = cancel
+	m.inflightMu.Unlock()
+
+	return ctx, func() {
+		m.inflightMu.Lock()
+		delete(m.inflight, key)
+		m.inflightMu.Unlock()
+		cancel()
+	}
+}
+
+// handleCancelledNotification cancels the tool call that a downstream client has given up on.
+func (m *MCPService) handleCancelledNotification(ctx context.Context, notification mcp.JSONRPCNotification) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+	raw, err := json.Marshal(notification.Params)
+	if err != nil {
+		return
+	}
+	var params mcp.CancelledNotificationParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		log.Printf("[WARN] ignoring malformed cancellation notification: %v", err)
+		return
+	}
+	key := inflightCallKey{sessionID: session.SessionID(), requestID: fmt.Sprint(params.RequestId)}
+
+	m.inflightMu.Lock()
+	cancel, ok := m.inflight[key]
+	m.inflightMu.Unlock()
+
+	if ok {
+		log.Printf("[INFO] client cancelled tool call %s: %s", key.requestID, params.Reason)
+		cancel()
+	}
+}
+
+// relayProgressNotifications forwards the progress notifications that the MCP server sends for a tool call
+// to the downstream client that made the call. ctx must be the context of the downstream request.
+// Nothing is relayed if the downstream client did not ask for progress notifications.
+func (m *MCPService) relayProgressNotifications(ctx context.Context, c *client.Client, request mcp.CallToolRequest) {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return
+	}
+	if server.ClientSessionFromContext(ctx) == nil {
+		// the call was not made through the MCP proxy, there is nobody to relay to
+		return
+	}
+	token := fmt.Sprint(request.Params.Meta.ProgressToken)
+
+	c.OnNotification(func(notification mcp.JSONRPCNotification) {
+		if notification.Method != methodNotificationProgress {
+			return
+		}
+		raw, err := json.Marshal(notification.Params)
+		if err != nil {
+			return
+		}
+		var params map[string]any
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return
+		}
+		if fmt.Sprint(params["progressToken"]) != token {
+			return
+		}
+		if err := m.mcpProxyServer.SendNotificationToClient(ctx, notification.Method, params); err != nil {
+			log.Printf("[WARN] failed to relay progress notification to the client: %v", err)
+		}
+	})
+}
+
+// cancelUpstreamRequest notifies an MCP server that mcpjungle is no longer interested in a request,
+// so that the server can stop working on it.
+func cancelUpstreamRequest(c *client.Client, id mcp.RequestId, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), upstreamCancelTimeout)
+	defer cancel()
+
+	notification := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: methodNotificationCancelled,
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]any{"requestId": id, "reason": reason},
+			},
+		},
+	}
+	if err := c.GetTransport().SendNotification(ctx, notification); err != nil {
+		log.Printf("[WARN] failed to notify MCP server about cancelled request: %v", err)
+	}
+}
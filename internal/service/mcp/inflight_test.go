@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"testing"
+)
+
+type fakeSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *fakeSession) Initialize()       {}
+func (s *fakeSession) Initialized() bool { return true }
+func (s *fakeSession) SessionID() string { return s.id }
+func (s *fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestProxyCallCancellation(t *testing.T) {
+	hooks := &server.Hooks{}
+	proxy := server.NewMCPServer("test", "0.0.1", server.WithHooks(hooks))
+	m := &MCPService{
+		mcpProxyServer: proxy,
+		inflight:       make(map[inflightCallKey]context.CancelFunc),
+	}
+	m.registerProxyHooks(proxy, hooks)
+
+	session := &fakeSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 1)}
+	ctx := proxy.WithContext(context.Background(), session)
+
+	// the hook hands the ID of the request to the tool call handler
+	var request mcp.CallToolRequest
+	for _, hook := range hooks.OnBeforeCallTool {
+		hook(ctx, mcp.NewRequestId(int64(7)), &request)
+	}
+
+	callCtx, done := m.trackProxyCall(ctx, request)
+	defer done()
+
+	// a cancellation of another request must not affect the call
+	m.handleCancelledNotification(ctx, cancelledNotification(8))
+	if callCtx.Err() != nil {
+		t.Fatalf("expected the call to continue after another request was cancelled")
+	}
+
+	m.handleCancelledNotification(ctx, cancelledNotification(7))
+	if callCtx.Err() == nil {
+		t.Fatalf("expected the call to be cancelled")
+	}
+
+	done()
+	if len(m.inflight) != 0 {
+		t.Errorf("expected no calls to be tracked after the call is over, got %d", len(m.inflight))
+	}
+}
+
+func cancelledNotification(requestID int64) mcp.JSONRPCNotification {
+	return mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: methodNotificationCancelled,
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]any{"requestId": mcp.NewRequestId(requestID)},
+			},
+		},
+	}
+}
@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"fmt"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"log"
+	"maps"
+	"slices"
+)
+
+// applyLabelsUpdate returns the labels resulting from an update, in which labels set to nil are removed.
+func applyLabelsUpdate(labels map[string]string, update map[string]*string) map[string]string {
+	for k, v := range update {
+		if v == nil {
+			delete(labels, k)
+		} else {
+			labels[k] = *v
+		}
+	}
+	return labels
+}
+
+// UpdateServerLabels adds, changes or removes (nil value) labels of an MCP server and returns its new labels.
+// The tools of the server inherit the labels, so tool groups that select tools by label are updated as well.
+func (m *MCPService) UpdateServerLabels(name string, update map[string]*string) (map[string]string, error) {
+	s, err := m.GetMcpServer(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP server %s: %w", name, err)
+	}
+	labels, err := s.GetLabels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode labels of server %s: %w", name, err)
+	}
+	labels = applyLabelsUpdate(labels, update)
+	if err := s.SetLabels(labels); err != nil {
+		return nil, err
+	}
+	if err := m.db.Model(s).Update("labels", s.Labels).Error; err != nil {
+		return nil, fmt.Errorf("failed to update labels of server %s: %w", name, err)
+	}
+	m.resyncLabelSelectedToolGroups()
+	return labels, nil
+}
+
+// UpdateToolLabels adds, changes or removes (nil value) labels of a tool and returns its new labels,
+// without the ones inherited from its server.
+func (m *MCPService) UpdateToolLabels(name string, update map[string]*string) (map[string]string, error) {
+	t, err := m.GetTool(m.resolveToolAlias(name))
+	if err != nil {
+		return nil, err
+	}
+	labels, err := t.GetLabels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode labels of tool %s: %w", name, err)
+	}
+	labels = applyLabelsUpdate(labels, update)
+	if err := t.SetLabels(labels); err != nil {
+		return nil, err
+	}
+	if err := m.db.Model(t).Update("labels", t.Labels).Error; err != nil {
+		return nil, fmt.Errorf("failed to update labels of tool %s: %w", name, err)
+	}
+	m.resyncLabelSelectedToolGroups()
+	return labels, nil
+}
+
+// toolLabelsLookup returns a function that looks up the effective labels of the given tools by canonical name.
+// The labels of all of the tools are loaded with a single query the first time the function is called,
+// so nothing is loaded unless a tool group selects tools by label.
+// Tools whose labels cannot be loaded are treated as having none.
+func (m *MCPService) toolLabelsLookup(canonicalToolNames []string) func(canonicalToolName string) map[string]string {
+	var labels map[string]map[string]string
+	return func(name string) map[string]string {
+		if labels == nil {
+			var err error
+			if labels, err = m.loadToolLabels(canonicalToolNames); err != nil {
+				log.Printf("[WARN] failed to load labels of tools: %v", err)
+				labels = make(map[string]map[string]string)
+			}
+		}
+		return labels[name]
+	}
+}
+
+// loadToolLabels returns the labels of the given tools, including the ones they inherit from their servers,
+// keyed by canonical tool name. The servers are joined in, rather than looked up tool by tool.
+// Tools that are not provided by an MCP server (eg- composite tools) have no labels.
+func (m *MCPService) loadToolLabels(canonicalToolNames []string) (map[string]map[string]string, error) {
+	wanted := make(map[string]bool, len(canonicalToolNames))
+	servers := make(map[string]bool)
+	for _, name := range canonicalToolNames {
+		if serverName, _, ok := splitServerToolName(name); ok {
+			wanted[name] = true
+			servers[serverName] = true
+		}
+	}
+	labels := make(map[string]map[string]string, len(wanted))
+	if len(servers) == 0 {
+		return labels, nil
+	}
+
+	var rows []toolRow
+	err := m.db.Model(&model.Tool{}).
+		Select("tools.name, tools.labels, mcp_servers.name AS server_name, mcp_servers.labels AS server_labels").
+		Joins("JOIN mcp_servers ON mcp_servers.id = tools.server_id AND mcp_servers.deleted_at IS NULL").
+		Where("mcp_servers.name IN ?", slices.Collect(maps.Keys(servers))).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		name := mergeServerToolNames(r.ServerName, r.Tool.Name)
+		if !wanted[name] {
+			continue
+		}
+		l, err := r.effectiveLabels()
+		if err != nil {
+			log.Printf("[WARN] %v", err)
+			continue
+		}
+		labels[name] = l
+	}
+	return labels, nil
+}
+
+// EnableToolsByLabels enables all tools whose labels match the selector and returns the names of
+// the tools that were enabled.
+func (m *MCPService) EnableToolsByLabels(selector string) ([]string, error) {
+	return m.setToolsEnabledByLabels(selector, true)
+}
+
+// DisableToolsByLabels disables all tools whose labels match the selector and returns the names of
+// the tools that were disabled.
+func (m *MCPService) DisableToolsByLabels(selector string) ([]string, error) {
+	return m.setToolsEnabledByLabels(selector, false)
+}
+
+func (m *MCPService) setToolsEnabledByLabels(selector string, enabled bool) ([]string, error) {
+	// only the tools whose state changes are selected
+	current := !enabled
+	tools, err := m.SearchTools(types.ToolFilter{Labels: selector, Enabled: &current})
+	if err != nil {
+		return nil, err
+	}
+	changed := make([]string, 0, len(tools))
+	for _, t := range tools {
+		names, err := m.setToolsEnabled(t.Name, enabled)
+		if err != nil {
+			return changed, err
+		}
+		changed = append(changed, names...)
+	}
+	return changed, nil
+}
+
+// clientHasServerAccess returns true if an MCP client is allowed to access an MCP server, either because
+// the server is in its allow list or because the server's labels match one of its allow selectors.
+func (m *MCPService) clientHasServerAccess(c *model.McpClient, serverName string) bool {
+	if c.CheckHasServerAccess(serverName) {
+		return true
+	}
+	if len(c.AllowSelectors) == 0 {
+		return false
+	}
+	s, err := m.GetMcpServer(serverName)
+	if err != nil {
+		return false
+	}
+	labels, err := s.GetLabels()
+	if err != nil {
+		log.Printf("[WARN] failed to decode labels of server %s: %v", serverName, err)
+		return false
+	}
+	return c.CheckHasLabelAccess(labels)
+}
+
+// resyncLabelSelectedToolGroups recomputes the tools of the groups that select tools by label,
+// after the labels of a server or tool have changed.
+func (m *MCPService) resyncLabelSelectedToolGroups() {
+	m.groupsMu.RLock()
+	defer m.groupsMu.RUnlock()
+
+	served := m.mcpProxyServer.ListTools()
+	canonicalNames := m.canonicalToolNames(served)
+	labelsOf := m.toolLabelsLookup(slices.Collect(maps.Values(canonicalNames)))
+
+	for _, g := range m.groups {
+		if len(g.selector) == 0 {
+			continue
+		}
+		var included []server.ServerTool
+		for name, t := range served {
+			if g.includes(canonicalNames[name], labelsOf) {
+				included = append(included, *t)
+			}
+		}
+		g.server.SetTools(included...)
+	}
+}
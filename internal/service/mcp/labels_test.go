@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestLabelSelector(t *testing.T) {
+	labels := map[string]string{"team": "data", "env": "prod"}
+	tests := []struct {
+		selector string
+		want     bool
+		wantErr  bool
+	}{
+		{selector: "", want: true},
+		{selector: "team=data", want: true},
+		{selector: "team==data, env=prod", want: true},
+		{selector: "team=data,env=staging", want: false},
+		{selector: "env!=staging", want: true},
+		{selector: "env!=prod", want: false},
+		{selector: "owner!=alice", want: true},
+		{selector: "team", want: true},
+		{selector: "owner", want: false},
+		{selector: "!owner", want: true},
+		{selector: "!team", want: false},
+		{selector: "team=da ta", wantErr: true},
+		{selector: "=data", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			sel, err := types.ParseLabelSelector(tt.selector)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for selector '%s'", tt.selector)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := sel.Matches(labels); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+			// the string form of a selector parses back to the same selector
+			if again, err := types.ParseLabelSelector(sel.String()); err != nil || !slices.Equal(again, sel) {
+				t.Errorf("selector %v did not round trip through %q", sel, sel.String())
+			}
+		})
+	}
+}
+
+func TestLabelSelectedToolGroup(t *testing.T) {
+	db := newTestDB(t, &model.McpServer{}, &model.Tool{})
+	servers := []struct {
+		name   string
+		labels map[string]string
+		tools  map[string]map[string]string
+	}{
+		{
+			name:   "warehouse",
+			labels: map[string]string{"team": "data"},
+			tools: map[string]map[string]string{
+				"query":    nil,
+				"drop_all": {"team": "ops"},
+			},
+		},
+		{
+			name:   "github",
+			labels: map[string]string{"team": "platform"},
+			tools:  map[string]map[string]string{"create_issue": nil},
+		},
+	}
+	m := &MCPService{
+		db:             db,
+		mcpProxyServer: server.NewMCPServer("test", "0.0.1", server.WithToolCapabilities(true)),
+		groups:         make(map[string]*toolGroupProxy),
+		toolSearch:     newToolSearchIndex(),
+	}
+	for _, s := range servers {
+		record := &model.McpServer{Name: s.name, Transport: types.TransportStreamableHTTP, Config: []byte("{}")}
+		if err := record.SetLabels(s.labels); err != nil {
+			t.Fatalf("failed to set labels: %v", err)
+		}
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("failed to create server: %v", err)
+		}
+		for name, labels := range s.tools {
+			tool := &model.Tool{Name: name, ServerID: record.ID, Enabled: true}
+			if err := tool.SetLabels(labels); err != nil {
+				t.Fatalf("failed to set labels: %v", err)
+			}
+			if err := db.Create(tool).Error; err != nil {
+				t.Fatalf("failed to create tool: %v", err)
+			}
+			m.addProxyTool(mcp.NewTool(mergeServerToolNames(s.name, name)))
+		}
+	}
+
+	g, err := m.newToolGroupProxy(&model.ToolGroup{Name: "data", Selector: "team=data"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m.groups["data"] = g
+
+	groupTools := func() []string {
+		return slices.Sorted(maps.Keys(g.server.ListTools()))
+	}
+	// tools inherit the labels of their server unless they override them
+	if got := groupTools(); !slices.Equal(got, []string{"warehouse__query"}) {
+		t.Errorf("unexpected tools in group: %v", got)
+	}
+
+	// the group follows changes of labels
+	data := "data"
+	if _, err := m.UpdateServerLabels("github", map[string]*string{"team": &data}); err != nil {
+		t.Fatalf("failed to update server labels: %v", err)
+	}
+	if _, err := m.UpdateToolLabels("warehouse__drop_all", map[string]*string{"team": nil}); err != nil {
+		t.Fatalf("failed to update tool labels: %v", err)
+	}
+	want := []string{"github__create_issue", "warehouse__drop_all", "warehouse__query"}
+	if got := groupTools(); !slices.Equal(got, want) {
+		t.Errorf("expected group tools %v after relabeling, got %v", want, got)
+	}
+	tools, err := m.SearchTools(types.ToolFilter{Labels: "team=data"})
+	if err != nil {
+		t.Fatalf("failed to search tools: %v", err)
+	}
+	if len(tools) != len(want) {
+		t.Errorf("expected %d tools to match the selector, got %d", len(want), len(tools))
+	}
+
+	// labels are only loaded for the requested tools, tools not provided by a server have none
+	labels, err := m.loadToolLabels([]string{"warehouse__drop_all", "github__create_issue", "report", "gone__tool"})
+	if err != nil {
+		t.Fatalf("failed to load tool labels: %v", err)
+	}
+	wantLabels := map[string]map[string]string{
+		"warehouse__drop_all":  {"team": "data"},
+		"github__create_issue": {"team": "data"},
+	}
+	if !maps.EqualFunc(labels, wantLabels, maps.Equal) {
+		t.Errorf("expected labels %v, got %v", wantLabels, labels)
+	}
+
+	c := &model.McpClient{Name: "analyst", AllowList: []byte(`[]`), AllowSelectors: []byte(`["team=data,env!=prod"]`)}
+	if !m.clientHasServerAccess(c, "warehouse") {
+		t.Errorf("expected the client to access a server matching its allow selector")
+	}
+	prod := "prod"
+	if _, err := m.UpdateServerLabels("warehouse", map[string]*string{"env": &prod}); err != nil {
+		t.Fatalf("failed to update server labels: %v", err)
+	}
+	if m.clientHasServerAccess(c, "warehouse") {
+		t.Errorf("expected the client to lose access once the server no longer matches its allow selector")
+	}
+}
@@ -1,9 +1,14 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"gorm.io/gorm"
+	"sync"
 )
 
 // MCPService coordinates operations amongst the registry database, mcp proxy server and upstream MCP servers.
@@ -11,17 +16,127 @@ import (
 type MCPService struct {
 	db             *gorm.DB
 	mcpProxyServer *server.MCPServer
+
+	rateLimitService *ratelimit.RateLimitService
+
+	// health tracks the result of the periodic health probes of upstream MCP servers, keyed by server name.
+	// It is kept in memory only and never affects the Enabled flag of tools in the DB.
+	// healthMu is also held while probe results change the proxy and while servers are deregistered.
+	healthMu sync.RWMutex
+	health   map[string]*serverHealth
+
+	// breakers holds the circuit breakers of upstream MCP servers that have one configured, keyed by server name.
+	breakersMu sync.Mutex
+	breakers   map[string]*circuitBreaker
+
+	// limiters holds the concurrency limiters of upstream MCP servers that have one configured, keyed by server name.
+	limitersMu sync.Mutex
+	limiters   map[string]*concurrencyLimiter
+
+	// caches holds the tool result caches of upstream MCP servers that have one configured, keyed by server name.
+	cachesMu sync.Mutex
+	caches   map[string]*resultCache
+
+	// openAPIServers holds the in-process MCP servers that serve the operations of openapi servers,
+	// keyed by server name.
+	openAPIServersMu sync.Mutex
+	openAPIServers   map[string]*server.MCPServer
+
+	// inflight holds the cancel functions of the tool calls in progress in the MCP proxy,
+	// so that they can be cancelled when the client sends a cancellation notification.
+	inflightMu sync.Mutex
+	inflight   map[inflightCallKey]context.CancelFunc
+
+	// clientCapabilities holds the capabilities declared by downstream client sessions that don't keep
+	// track of them themselves (eg- streamable http sessions), keyed by session ID.
+	clientCapabilitiesMu sync.Mutex
+	clientCapabilities   map[string]mcp.ClientCapabilities
+
+	// oauthMu guards the authorizations pending with users.
+	oauthMu               sync.Mutex
+	pendingAuthorizations map[string]*pendingOAuthAuthorization
+
+	// upstreamTokens holds the upstream OAuth tokens in memory, keyed by server name.
+	// Each token has its own lock, so refreshing the token of one MCP server never blocks calls to the others.
+	upstreamTokensMu sync.Mutex
+	upstreamTokens   map[string]*upstreamOAuthToken
+
+	// overrides holds the tool overrides keyed by canonical tool name, and aliases maps each alias
+	// to the canonical name of its tool. They mirror the overrides stored in the DB.
+	overridesMu sync.RWMutex
+	overrides   map[string]*types.ToolOverride
+	aliases     map[string]string
+
+	// presets holds the argument presets keyed by canonical tool name and then by client name
+	// (empty for all clients). They mirror the presets stored in the DB.
+	presetsMu sync.RWMutex
+	presets   map[string]map[string]map[string]any
+
+	// schemas caches the compiled input schemas used to validate tool arguments, keyed by the canonical
+	// name of the tool (or the name of the composite tool).
+	schemasMu sync.Mutex
+	schemas   map[string]*compiledSchema
+
+	// groups holds the MCP servers that serve the tool groups, keyed by group name.
+	groupsMu sync.RWMutex
+	groups   map[string]*toolGroupProxy
+
+	// toolSearch indexes the tools served by the MCP proxy for the meta-tools of toolSearchServer.
+	toolSearch       *toolSearchIndex
+	toolSearchServer *server.MCPServer
 }
 
 // NewMCPService creates a new instance of MCPService.
 // It initializes the MCP proxy server by loading all registered tools from the database.
-func NewMCPService(db *gorm.DB, mcpProxyServer *server.MCPServer) (*MCPService, error) {
+// proxyHooks must be the hooks the proxy server was created with, they are used to track tool calls
+// so that they can be cancelled by the client.
+// All tool calls are subject to the rate limits enforced by rateLimitService.
+func NewMCPService(
+	db *gorm.DB,
+	mcpProxyServer *server.MCPServer,
+	proxyHooks *server.Hooks,
+	rateLimitService *ratelimit.RateLimitService,
+) (*MCPService, error) {
 	s := &MCPService{
-		db:             db,
-		mcpProxyServer: mcpProxyServer,
+		db:               db,
+		mcpProxyServer:   mcpProxyServer,
+		rateLimitService: rateLimitService,
+		health:           make(map[string]*serverHealth),
+		breakers:         make(map[string]*circuitBreaker),
+		limiters:         make(map[string]*concurrencyLimiter),
+		caches:           make(map[string]*resultCache),
+		openAPIServers:   make(map[string]*server.MCPServer),
+		inflight:         make(map[inflightCallKey]context.CancelFunc),
+
+		clientCapabilities: make(map[string]mcp.ClientCapabilities),
+
+		pendingAuthorizations: make(map[string]*pendingOAuthAuthorization),
+		upstreamTokens:        make(map[string]*upstreamOAuthToken),
+
+		overrides: make(map[string]*types.ToolOverride),
+		aliases:   make(map[string]string),
+		presets:   make(map[string]map[string]map[string]any),
+		groups:    make(map[string]*toolGroupProxy),
+		schemas:   make(map[string]*compiledSchema),
+
+		toolSearch: newToolSearchIndex(),
+	}
+	s.toolSearchServer = s.newToolSearchServer()
+	s.registerProxyHooks(mcpProxyServer, proxyHooks)
+	if err := s.initToolOverrides(); err != nil {
+		return nil, fmt.Errorf("failed to load tool overrides: %w", err)
+	}
+	if err := s.initArgumentPresets(); err != nil {
+		return nil, fmt.Errorf("failed to load argument presets: %w", err)
 	}
 	if err := s.initMCPProxyServer(); err != nil {
 		return nil, fmt.Errorf("failed to initialize MCP proxy server: %w", err)
 	}
+	if err := s.initCompositeTools(); err != nil {
+		return nil, fmt.Errorf("failed to initialize composite tools: %w", err)
+	}
+	if err := s.initToolGroups(); err != nil {
+		return nil, fmt.Errorf("failed to initialize tool groups: %w", err)
+	}
 	return s, nil
 }
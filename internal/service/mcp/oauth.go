@@ -0,0 +1,292 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"gorm.io/gorm/clause"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// oauthAuthorizationTimeout is how long a user has to authorize mcpjungle after starting an authorization.
+const oauthAuthorizationTimeout = 10 * time.Minute
+
+// oauthTokenRequestTimeout bounds the requests that fetch or refresh an upstream OAuth token,
+// so that an authorization server that hangs cannot hold up the calls to its MCP server forever.
+const oauthTokenRequestTimeout = 30 * time.Second
+
+// ErrOAuthAuthorizationRequired is returned when mcpjungle cannot access an MCP server
+// until a user authorizes it.
+var ErrOAuthAuthorizationRequired = errors.New("authorization required")
+
+// pendingOAuthAuthorization is an authorization_code flow waiting for the user to authorize mcpjungle.
+type pendingOAuthAuthorization struct {
+	serverName string
+	config     *oauth2.Config
+	verifier   string
+	startedAt  time.Time
+}
+
+// newOAuth2Config converts the OAuth configuration of an MCP server into an authorization_code flow configuration.
+func newOAuth2Config(c *types.UpstreamOAuthConfig, redirectURI string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		Endpoint:     oauth2.Endpoint{AuthURL: c.AuthURL, TokenURL: c.TokenURL},
+		RedirectURL:  redirectURI,
+		Scopes:       c.Scopes,
+	}
+}
+
+// StartOAuthAuthorization starts an authorization_code flow (with PKCE) for an MCP server and returns the URL
+// that the user must visit to authorize mcpjungle, along with the state identifying the flow.
+// If conf is nil, the OAuth configuration of the registered server is used.
+func (m *MCPService) StartOAuthAuthorization(
+	serverName, redirectURI string, conf *types.UpstreamOAuthConfig,
+) (*types.OAuthAuthorizeResponse, error) {
+	if redirectURI == "" {
+		return nil, errors.New("redirect_uri is required")
+	}
+	if conf == nil {
+		s, err := m.GetMcpServer(serverName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get MCP server %s: %w", serverName, err)
+		}
+		if conf, err = s.GetOAuthConfig(); err != nil {
+			return nil, fmt.Errorf("failed to get oauth config of MCP server %s: %w", serverName, err)
+		}
+		if conf == nil {
+			return nil, fmt.Errorf("MCP server %s does not use oauth", serverName)
+		}
+	} else if err := conf.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid oauth configuration: %w", err)
+	}
+	if conf.GrantType != types.OAuthGrantAuthorizationCode {
+		return nil, fmt.Errorf(
+			"MCP server %s uses the %s grant, which doesn't need a user's authorization", serverName, conf.GrantType,
+		)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate state: %w", err)
+	}
+	state := base64.RawURLEncoding.EncodeToString(b)
+
+	p := &pendingOAuthAuthorization{
+		serverName: serverName,
+		config:     newOAuth2Config(conf, redirectURI),
+		verifier:   oauth2.GenerateVerifier(),
+		startedAt:  time.Now(),
+	}
+
+	m.oauthMu.Lock()
+	for k, v := range m.pendingAuthorizations {
+		if time.Since(v.startedAt) > oauthAuthorizationTimeout {
+			delete(m.pendingAuthorizations, k)
+		}
+	}
+	m.pendingAuthorizations[state] = p
+	m.oauthMu.Unlock()
+
+	return &types.OAuthAuthorizeResponse{
+		AuthorizationURL: p.config.AuthCodeURL(state, oauth2.S256ChallengeOption(p.verifier)),
+		State:            state,
+	}, nil
+}
+
+// CompleteOAuthAuthorization exchanges the authorization code for tokens and stores them.
+// It returns the name of the MCP server the tokens belong to.
+func (m *MCPService) CompleteOAuthAuthorization(ctx context.Context, state, code string) (string, error) {
+	m.oauthMu.Lock()
+	p, ok := m.pendingAuthorizations[state]
+	delete(m.pendingAuthorizations, state)
+	m.oauthMu.Unlock()
+
+	if !ok || time.Since(p.startedAt) > oauthAuthorizationTimeout {
+		return "", errors.New("unknown or expired authorization, please start over")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, oauthTokenRequestTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: oauthTokenRequestTimeout})
+
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(p.verifier))
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange authorization code for tokens: %w", err)
+	}
+
+	// a refresh in progress must not overwrite the newly authorized token once it is done
+	t := m.upstreamOAuthToken(p.serverName)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := m.saveOAuthToken(p.serverName, token); err != nil {
+		return "", err
+	}
+	t.token = token
+	return p.serverName, nil
+}
+
+// upstreamTokenSource returns the source of access tokens for an MCP server that authenticates using OAuth.
+// It returns nil if the server does not use OAuth.
+func (m *MCPService) upstreamTokenSource(s *model.McpServer) (oauth2.TokenSource, error) {
+	conf, err := s.GetOAuthConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oauth config of MCP server %s: %w", s.Name, err)
+	}
+	if conf == nil {
+		return nil, nil
+	}
+	return &persistedTokenSource{m: m, serverName: s.Name, conf: conf}, nil
+}
+
+// persistedTokenSource hands out the access token of an MCP server stored in the database.
+// Expired tokens are refreshed (or, for the client_credentials grant, fetched again) and stored.
+type persistedTokenSource struct {
+	m          *MCPService
+	serverName string
+	conf       *types.UpstreamOAuthConfig
+}
+
+// upstreamOAuthToken is the OAuth token of an MCP server held in memory.
+type upstreamOAuthToken struct {
+	// mu serializes the refreshes of the token, so that concurrent calls don't use up a rotating refresh token
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// upstreamOAuthToken returns the in-memory OAuth token of an MCP server, creating an empty one if needed.
+func (m *MCPService) upstreamOAuthToken(serverName string) *upstreamOAuthToken {
+	m.upstreamTokensMu.Lock()
+	defer m.upstreamTokensMu.Unlock()
+
+	t, ok := m.upstreamTokens[serverName]
+	if !ok {
+		t = &upstreamOAuthToken{}
+		m.upstreamTokens[serverName] = t
+	}
+	return t
+}
+
+// forgetUpstreamOAuthToken discards the in-memory OAuth token of an MCP server.
+func (m *MCPService) forgetUpstreamOAuthToken(serverName string) {
+	m.upstreamTokensMu.Lock()
+	defer m.upstreamTokensMu.Unlock()
+	delete(m.upstreamTokens, serverName)
+}
+
+func (p *persistedTokenSource) Token() (*oauth2.Token, error) {
+	// only calls to the same MCP server wait for each other
+	t := p.m.upstreamOAuthToken(p.serverName)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != nil && t.token.Valid() {
+		return t.token, nil
+	}
+	stored, err := p.m.loadOAuthToken(p.serverName)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil && stored.Valid() {
+		t.token = stored
+		return stored, nil
+	}
+
+	// token requests are not bound to any tool call so that a fresh token is never thrown away
+	ctx, cancel := context.WithTimeout(context.Background(), oauthTokenRequestTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: oauthTokenRequestTimeout})
+
+	var token *oauth2.Token
+	switch p.conf.GrantType {
+	case types.OAuthGrantClientCredentials:
+		cc := &clientcredentials.Config{
+			ClientID:     p.conf.ClientID,
+			ClientSecret: p.conf.ClientSecret,
+			TokenURL:     p.conf.TokenURL,
+			Scopes:       p.conf.Scopes,
+		}
+		token, err = cc.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain oauth token for MCP server %s: %w", p.serverName, err)
+		}
+	default:
+		if stored == nil || stored.RefreshToken == "" {
+			return nil, fmt.Errorf(
+				"%w: run 'mcpjungle authorize %s' to authorize access to this MCP server",
+				ErrOAuthAuthorizationRequired, p.serverName,
+			)
+		}
+		token, err = newOAuth2Config(p.conf, "").TokenSource(ctx, stored).Token()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: failed to refresh oauth token for MCP server %s, run 'mcpjungle authorize %s': %v",
+				ErrOAuthAuthorizationRequired, p.serverName, p.serverName, err,
+			)
+		}
+	}
+
+	if err := p.m.saveOAuthToken(p.serverName, token); err != nil {
+		return nil, err
+	}
+	t.token = token
+	return token, nil
+}
+
+// loadOAuthToken returns the stored OAuth token of an MCP server, or nil if there is none.
+func (m *MCPService) loadOAuthToken(serverName string) (*oauth2.Token, error) {
+	var t model.UpstreamOAuthToken
+	res := m.db.Where("server_name = ?", serverName).Limit(1).Find(&t)
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to load oauth token of MCP server %s: %w", serverName, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &oauth2.Token{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		TokenType:    t.TokenType,
+		Expiry:       t.Expiry,
+	}, nil
+}
+
+// saveOAuthToken stores the OAuth token of an MCP server, replacing the previous one.
+func (m *MCPService) saveOAuthToken(serverName string, token *oauth2.Token) error {
+	t := &model.UpstreamOAuthToken{
+		ServerName:   serverName,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		TokenType:    token.TokenType,
+		Expiry:       token.Expiry,
+	}
+	err := m.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "server_name"}},
+		DoUpdates: clause.AssignmentColumns(
+			[]string{"access_token", "refresh_token", "token_type", "expiry", "updated_at"},
+		),
+	}).Create(t).Error
+	if err != nil {
+		return fmt.Errorf("failed to store oauth token of MCP server %s: %w", serverName, err)
+	}
+	return nil
+}
+
+// deleteOAuthToken removes the stored OAuth token of an MCP server.
+func (m *MCPService) deleteOAuthToken(serverName string) error {
+	err := m.db.Unscoped().Where("server_name = ?", serverName).Delete(&model.UpstreamOAuthToken{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete oauth token of MCP server %s: %w", serverName, err)
+	}
+	m.forgetUpstreamOAuthToken(serverName)
+	return nil
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newOAuthTestService(t *testing.T) *MCPService {
+	db := newTestDB(t, &model.UpstreamOAuthToken{})
+	return &MCPService{
+		db:                    db,
+		pendingAuthorizations: make(map[string]*pendingOAuthAuthorization),
+		upstreamTokens:        make(map[string]*upstreamOAuthToken),
+	}
+}
+
+func TestPersistedTokenSourceClientCredentials(t *testing.T) {
+	var issued atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": 3600}`, n)
+	}))
+	defer tokenServer.Close()
+
+	m := newOAuthTestService(t)
+	ts := &persistedTokenSource{m: m, serverName: "test", conf: &types.UpstreamOAuthConfig{
+		GrantType:    types.OAuthGrantClientCredentials,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		TokenURL:     tokenServer.URL,
+	}}
+
+	for i := 0; i < 2; i++ {
+		token, err := ts.Token()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token.AccessToken != "token-1" {
+			t.Errorf("expected the stored token to be reused, got %s", token.AccessToken)
+		}
+	}
+	if issued.Load() != 1 {
+		t.Errorf("expected 1 token to be issued, got %d", issued.Load())
+	}
+
+	// the token is kept in memory, so the database is not read again
+	if err := m.db.Unscoped().Where("server_name = ?", "test").Delete(&model.UpstreamOAuthToken{}).Error; err != nil {
+		t.Fatalf("failed to delete token: %v", err)
+	}
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "token-1" {
+		t.Errorf("expected the token in memory to be reused, got %s", token.AccessToken)
+	}
+
+	// an expired token is replaced
+	if err := m.db.Model(&model.UpstreamOAuthToken{}).Where("server_name = ?", "test").
+		Update("expiry", gorm.Expr("'2000-01-01 00:00:00'")).Error; err != nil {
+		t.Fatalf("failed to expire token: %v", err)
+	}
+	m.upstreamOAuthToken("test").token.Expiry = time.Now().Add(-time.Minute)
+	token, err = ts.Token()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "token-2" {
+		t.Errorf("expected a new token after expiry, got %s", token.AccessToken)
+	}
+}
+
+func TestPersistedTokenSourceRequiresAuthorization(t *testing.T) {
+	m := newOAuthTestService(t)
+	ts := &persistedTokenSource{m: m, serverName: "test", conf: &types.UpstreamOAuthConfig{
+		GrantType: types.OAuthGrantAuthorizationCode,
+		ClientID:  "id",
+		AuthURL:   "https://auth.example.com/authorize",
+		TokenURL:  "https://auth.example.com/token",
+	}}
+
+	if _, err := ts.Token(); !errors.Is(err, ErrOAuthAuthorizationRequired) {
+		t.Errorf("expected ErrOAuthAuthorizationRequired, got %v", err)
+	}
+}
+
+func TestPersistedTokenSourcePerServer(t *testing.T) {
+	release := make(chan struct{})
+	hungServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer hungServer.Close()
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "token", "token_type": "Bearer", "expires_in": 3600}`)
+	}))
+	defer tokenServer.Close()
+
+	m := newOAuthTestService(t)
+	newSource := func(name, tokenURL string) *persistedTokenSource {
+		return &persistedTokenSource{m: m, serverName: name, conf: &types.UpstreamOAuthConfig{
+			GrantType:    types.OAuthGrantClientCredentials,
+			ClientID:     "id",
+			ClientSecret: "secret",
+			TokenURL:     tokenURL,
+		}}
+	}
+
+	hungDone := make(chan error, 1)
+	go func() {
+		_, err := newSource("hung", hungServer.URL).Token()
+		hungDone <- err
+	}()
+
+	// a hung authorization server must not hold up tokens of other MCP servers
+	got := make(chan error, 1)
+	go func() {
+		_, err := newSource("ok", tokenServer.URL).Token()
+		got <- err
+	}()
+	select {
+	case err := <-got:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("token of a server was blocked by the token request of another server")
+	}
+
+	close(release)
+	if err := <-hungDone; err == nil {
+		t.Error("expected an error from the failing authorization server")
+	}
+}
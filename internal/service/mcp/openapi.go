@@ -0,0 +1,293 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// openAPISpecFetchTimeout is the timeout for downloading the OpenAPI spec of a server being registered.
+const openAPISpecFetchTimeout = 30 * time.Second
+
+// openAPIMaxResponseSize is the maximum size of an API response relayed to the client as a tool result.
+const openAPIMaxResponseSize = 10 << 20
+
+// loadOpenAPISpec takes a snapshot of the OpenAPI spec of a server being registered, downloading it if
+// only its URL was supplied, and checks that the operations of the API can be served as tools.
+// If no base url was configured, the one derived from the spec is recorded.
+func loadOpenAPISpec(ctx context.Context, s *model.McpServer) error {
+	conf, err := s.GetOpenAPIConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get openapi config for server %s: %w", s.Name, err)
+	}
+
+	if conf.Spec == "" {
+		httpClient, err := newUpstreamHTTPClient(conf.TLS, nil)
+		if err != nil {
+			return err
+		}
+		fetchCtx, cancel := context.WithTimeout(ctx, openAPISpecFetchTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(fetchCtx, http.MethodGet, conf.SpecURL, nil)
+		if err != nil {
+			return fmt.Errorf("invalid spec url %s: %w", conf.SpecURL, err)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to download OpenAPI spec from %s: %w", conf.SpecURL, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("failed to download OpenAPI spec from %s: HTTP %d", conf.SpecURL, resp.StatusCode)
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to download OpenAPI spec from %s: %w", conf.SpecURL, err)
+		}
+		conf.Spec = string(data)
+	}
+
+	spec, err := parseOpenAPISpec([]byte(conf.Spec))
+	if err != nil {
+		return err
+	}
+	// like the spec, the base url of the API is fixed at registration
+	if conf.BaseURL, err = spec.baseURL(conf.BaseURL, conf.SpecURL); err != nil {
+		return err
+	}
+	return s.SetOpenAPIConfig(conf)
+}
+
+// getOpenAPIMcpServer returns the in-process MCP server that serves the operations of an openapi server.
+// It is created from the server's spec on first use and kept until the server is deregistered.
+func (m *MCPService) getOpenAPIMcpServer(s *model.McpServer) (*server.MCPServer, error) {
+	m.openAPIServersMu.Lock()
+	defer m.openAPIServersMu.Unlock()
+	if srv, ok := m.openAPIServers[s.Name]; ok {
+		return srv, nil
+	}
+	srv, err := newOpenAPIMcpServer(s)
+	if err != nil {
+		return nil, err
+	}
+	m.openAPIServers[s.Name] = srv
+	return srv, nil
+}
+
+// forgetOpenAPIMcpServer discards the in-process MCP server of a deregistered openapi server.
+func (m *MCPService) forgetOpenAPIMcpServer(name string) {
+	m.openAPIServersMu.Lock()
+	defer m.openAPIServersMu.Unlock()
+	delete(m.openAPIServers, name)
+}
+
+// newOpenAPIMcpServer creates an MCP server with one tool per operation of the OpenAPI spec of s.
+// Calling a tool sends the corresponding HTTP request to the API.
+func newOpenAPIMcpServer(s *model.McpServer) (*server.MCPServer, error) {
+	conf, err := s.GetOpenAPIConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get openapi config for server %s: %w", s.Name, err)
+	}
+	spec, err := parseOpenAPISpec([]byte(conf.Spec))
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := spec.baseURL(conf.BaseURL, conf.SpecURL)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := newUpstreamHTTPClient(conf.TLS, nil)
+	if err != nil {
+		return nil, err
+	}
+	headers := upstreamHeaders(conf.Headers, conf.BearerToken)
+
+	srv := server.NewMCPServer(s.Name, "0.1", server.WithToolCapabilities(false))
+	for _, op := range spec.operations {
+		srv.AddTool(op.tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return op.call(ctx, httpClient, baseURL, headers, request.GetArguments())
+		})
+	}
+	return srv, nil
+}
+
+// createOpenAPIMcpServerConn creates a new connection with the in-process MCP server of an openapi server
+// and returns the client.
+func (m *MCPService) createOpenAPIMcpServerConn(
+	ctx context.Context, s *model.McpServer, clientOpts ...client.ClientOption,
+) (*client.Client, error) {
+	srv, err := m.getOpenAPIMcpServer(s)
+	if err != nil {
+		return nil, err
+	}
+	c := client.NewClient(transport.NewInProcessTransport(srv), clientOpts...)
+	if err := c.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start client for openapi server: %w", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "mcpjungle mcp client for openapi",
+		Version: "0.1",
+	}
+	initRequest.Params.Capabilities = mcp.ClientCapabilities{}
+	if _, err := c.Initialize(ctx, initRequest); err != nil {
+		return nil, fmt.Errorf("failed to initialize connection with openapi server: %w", err)
+	}
+	return c, nil
+}
+
+// call sends the HTTP request of the operation built from the tool arguments and returns the response
+// as the tool result. Error responses of the API are returned as tool errors so that the LLM can see them.
+// An error is only returned if the API could not be reached.
+func (o *openAPIOperation) call(
+	ctx context.Context, httpClient *http.Client, baseURL string, headers map[string]string, args map[string]any,
+) (*mcp.CallToolResult, error) {
+	req, err := o.newRequest(ctx, baseURL, headers, args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	// read one byte more than the limit to find out if the response was too large, instead of truncating it
+	data, err := io.ReadAll(io.LimitReader(resp.Body, openAPIMaxResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", req.URL.Redacted(), err)
+	}
+	if len(data) > openAPIMaxResponseSize {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("%s %s returned a response larger than %d bytes", req.Method, o.path, openAPIMaxResponseSize),
+		), nil
+	}
+	text := string(data)
+	if text == "" {
+		text = resp.Status
+	}
+	if resp.StatusCode >= 400 {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("%s %s returned %s: %s", req.Method, o.path, resp.Status, text),
+		), nil
+	}
+
+	// JSON objects are also returned as structured content, so that their fields can be used directly
+	var obj map[string]any
+	if json.Unmarshal(data, &obj) == nil && obj != nil {
+		return mcp.NewToolResultStructured(obj, text), nil
+	}
+	return mcp.NewToolResultText(text), nil
+}
+
+// protectedOpenAPIHeaders are the headers that header parameters of an operation can never set.
+var protectedOpenAPIHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+}
+
+// newRequest builds the HTTP request of the operation from the tool arguments.
+// Header parameters are skipped if they would replace one of the configured headers or a credential.
+func (o *openAPIOperation) newRequest(
+	ctx context.Context, baseURL string, headers map[string]string, args map[string]any,
+) (*http.Request, error) {
+	path := o.path
+	query := url.Values{}
+	paramHeaders := make(map[string]string)
+	for _, p := range o.params {
+		v, ok := args[p.arg]
+		if !ok || v == nil {
+			continue
+		}
+		switch p.in {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.name+"}", url.PathEscape(openAPIParamString(v)))
+		case "query":
+			if list, ok := v.([]any); ok {
+				for _, e := range list {
+					query.Add(p.name, openAPIParamString(e))
+				}
+			} else {
+				query.Add(p.name, openAPIParamString(v))
+			}
+		case "header":
+			paramHeaders[p.name] = openAPIParamString(v)
+		}
+	}
+	if strings.Contains(path, "{") {
+		return nil, fmt.Errorf("missing path parameters in %s", path)
+	}
+
+	u := strings.TrimSuffix(baseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if v, ok := args[openAPIBodyArg]; ok && o.bodyContentType != "" {
+		if s, isString := v.(string); isString && !strings.Contains(o.bodyContentType, "json") {
+			body = strings.NewReader(s)
+		} else {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode the request body: %w", err)
+			}
+			body = bytes.NewReader(data)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(o.method), u, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range paramHeaders {
+		// the arguments come from the LLM, they must not replace the credentials or headers configured for the API
+		if req.Header.Get(k) != "" || protectedOpenAPIHeaders[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", o.bodyContentType)
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json, */*")
+	}
+	return req, nil
+}
+
+// openAPIParamString formats the value of a path, query or header parameter.
+func openAPIParamString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
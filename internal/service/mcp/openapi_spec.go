@@ -0,0 +1,391 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// openAPIMethods are the operations of a path item that are exposed as tools, in the order they are listed.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// invalidToolNameChars matches the characters that are replaced when deriving a tool name from an operation.
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// openAPIBodyArg is the name of the tool argument that carries the request body of an operation.
+const openAPIBodyArg = "body"
+
+// openAPISpec is an OpenAPI 3.x spec parsed into the operations that are exposed as tools.
+type openAPISpec struct {
+	operations []*openAPIOperation
+
+	// serverURL is the URL of the first server listed in the spec, with its variables set to their defaults.
+	serverURL string
+}
+
+// openAPIOperation is an operation of an OpenAPI spec along with the tool that exposes it.
+type openAPIOperation struct {
+	tool   mcp.Tool
+	method string
+	path   string
+	params []openAPIParameter
+
+	// bodyContentType is the media type of the request body. It is empty if the operation takes no body.
+	bodyContentType string
+}
+
+// openAPIParameter is a parameter of an operation, supplied as the tool argument arg.
+// The argument is named after the parameter, unless the name is already taken by another argument.
+type openAPIParameter struct {
+	name string
+	in   string
+	arg  string
+}
+
+// parseOpenAPISpec parses an OpenAPI 3.x spec in JSON or YAML format.
+func parseOpenAPISpec(data []byte) (*openAPISpec, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	doc, ok := normalizeYAML(raw).(map[string]any)
+	if !ok {
+		return nil, errors.New("failed to parse OpenAPI spec: the document is not an object")
+	}
+	if version, _ := doc["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, errors.New("only OpenAPI 3.x specs are supported")
+	}
+
+	r := &openAPIRefResolver{doc: doc}
+	spec := &openAPISpec{serverURL: firstServerURL(doc)}
+	names := make(map[string]bool)
+
+	paths, _ := doc["paths"].(map[string]any)
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, err := r.inline(paths[path], nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %s: %w", path, err)
+		}
+		pathItem, _ := item.(map[string]any)
+		for _, method := range openAPIMethods {
+			op, ok := pathItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			o, err := newOpenAPIOperation(method, path, op, pathItem["parameters"], names)
+			if err != nil {
+				return nil, fmt.Errorf("invalid operation %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			spec.operations = append(spec.operations, o)
+		}
+	}
+	if len(spec.operations) == 0 {
+		return nil, errors.New("the OpenAPI spec does not define any operations")
+	}
+	return spec, nil
+}
+
+// newOpenAPIOperation creates the tool of an operation whose references have been inlined.
+// names holds the tool names already in use, the name of the new tool is added to it.
+func newOpenAPIOperation(
+	method, path string, op map[string]any, commonParams any, names map[string]bool,
+) (*openAPIOperation, error) {
+	o := &openAPIOperation{method: method, path: path}
+	properties := make(map[string]any)
+	var required []string
+
+	if body, ok := op["requestBody"].(map[string]any); ok {
+		content, _ := body["content"].(map[string]any)
+		o.bodyContentType = requestBodyContentType(content)
+		if o.bodyContentType != "" {
+			media, _ := content[o.bodyContentType].(map[string]any)
+			schema, ok := media["schema"].(map[string]any)
+			if !ok {
+				schema = map[string]any{}
+			}
+			if desc, ok := body["description"].(string); ok && schema["description"] == nil {
+				schema = withDescription(schema, desc)
+			}
+			properties[openAPIBodyArg] = schema
+			if req, _ := body["required"].(bool); req {
+				required = append(required, openAPIBodyArg)
+			}
+		}
+	}
+
+	for _, p := range operationParameters(commonParams, op["parameters"]) {
+		name, _ := p["name"].(string)
+		in, _ := p["in"].(string)
+		if name == "" || in == "cookie" {
+			// cookies are left to the custom headers of the server
+			continue
+		}
+		if in != "path" && in != "query" && in != "header" {
+			return nil, fmt.Errorf("parameter %s has an unsupported location %q", name, in)
+		}
+		arg := name
+		if _, taken := properties[arg]; taken {
+			arg = in + "_" + name
+		}
+
+		schema, ok := p["schema"].(map[string]any)
+		if !ok {
+			schema = map[string]any{"type": "string"}
+		}
+		if desc, ok := p["description"].(string); ok && schema["description"] == nil {
+			schema = withDescription(schema, desc)
+		}
+		properties[arg] = schema
+		if req, _ := p["required"].(bool); req || in == "path" {
+			required = append(required, arg)
+		}
+		o.params = append(o.params, openAPIParameter{name: name, in: in, arg: arg})
+	}
+
+	inputSchema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		inputSchema["required"] = required
+	}
+	rawSchema, err := json.Marshal(inputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode input schema: %w", err)
+	}
+
+	summary, _ := op["summary"].(string)
+	description, _ := op["description"].(string)
+	switch {
+	case summary != "" && description != "":
+		description = summary + "\n\n" + description
+	case description == "" && summary != "":
+		description = summary
+	case description == "":
+		description = strings.ToUpper(method) + " " + path
+	}
+
+	readOnly := method == "get" || method == "head" || method == "options"
+	idempotent := readOnly || method == "put" || method == "delete"
+	o.tool = mcp.Tool{
+		Name:           operationToolName(method, path, op, names),
+		Description:    description,
+		RawInputSchema: rawSchema,
+		Annotations: mcp.ToolAnnotation{
+			Title:          summary,
+			ReadOnlyHint:   mcp.ToBoolPtr(readOnly),
+			IdempotentHint: mcp.ToBoolPtr(idempotent),
+			OpenWorldHint:  mcp.ToBoolPtr(true),
+		},
+	}
+	return o, nil
+}
+
+// operationParameters merges the parameters common to a path with the ones of an operation.
+// An operation's parameter overrides a common parameter with the same name and location.
+func operationParameters(common, own any) []map[string]any {
+	var params []map[string]any
+	index := make(map[string]int)
+	for _, list := range []any{common, own} {
+		items, _ := list.([]any)
+		for _, item := range items {
+			p, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			key := fmt.Sprint(p["in"]) + ":" + fmt.Sprint(p["name"])
+			if i, ok := index[key]; ok {
+				params[i] = p
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// requestBodyContentType picks the media type in which the request body is sent, preferring JSON.
+func requestBodyContentType(content map[string]any) string {
+	types := slices.Sorted(maps.Keys(content))
+	for _, t := range types {
+		if t == "application/json" {
+			return t
+		}
+	}
+	for _, t := range types {
+		if strings.HasSuffix(t, "+json") {
+			return t
+		}
+	}
+	if len(types) > 0 {
+		return types[0]
+	}
+	return ""
+}
+
+// operationToolName derives a unique tool name from the operationId of an operation, or from its method and path.
+func operationToolName(method, path string, op map[string]any, names map[string]bool) string {
+	name, _ := op["operationId"].(string)
+	if name == "" {
+		name = method + "_" + path
+	}
+	name = strings.Trim(invalidToolNameChars.ReplaceAllString(name, "_"), "_")
+	for strings.Contains(name, "__") {
+		name = strings.ReplaceAll(name, "__", "_")
+	}
+
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	names[unique] = true
+	return unique
+}
+
+func withDescription(schema map[string]any, description string) map[string]any {
+	s := maps.Clone(schema)
+	s["description"] = description
+	return s
+}
+
+// firstServerURL returns the URL of the first server listed in the spec, with its variables set to their defaults.
+func firstServerURL(doc map[string]any) string {
+	servers, _ := doc["servers"].([]any)
+	if len(servers) == 0 {
+		return ""
+	}
+	s, _ := servers[0].(map[string]any)
+	u, _ := s["url"].(string)
+	vars, _ := s["variables"].(map[string]any)
+	for name, v := range vars {
+		variable, _ := v.(map[string]any)
+		if def, ok := variable["default"]; ok {
+			u = strings.ReplaceAll(u, "{"+name+"}", fmt.Sprint(def))
+		}
+	}
+	return u
+}
+
+// baseURL returns the URL that the paths of the API are relative to.
+// The configured base URL takes precedence over the server listed in the spec, which may be relative to
+// the URL the spec was fetched from.
+func (s *openAPISpec) baseURL(configured, specURL string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	if s.serverURL == "" {
+		return "", errors.New("the OpenAPI spec does not list any servers, so the base url of the API is required")
+	}
+	u, err := url.Parse(s.serverURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid server url %s in the OpenAPI spec: %w", s.serverURL, err)
+	}
+	if u.IsAbs() {
+		return u.String(), nil
+	}
+	if specURL == "" {
+		return "", fmt.Errorf(
+			"the server url %s in the OpenAPI spec is relative, so the base url of the API is required", s.serverURL,
+		)
+	}
+	base, err := url.Parse(specURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid spec url %s: %w", specURL, err)
+	}
+	return base.ResolveReference(u).String(), nil
+}
+
+// openAPIRefResolver inlines the local references ($ref) of an OpenAPI spec,
+// so that the input schemas of the tools are self-contained.
+type openAPIRefResolver struct {
+	doc map[string]any
+}
+
+// inline returns v with all references replaced by the values they refer to.
+// seen holds the references being inlined, a reference to one of them is recursive and is replaced by
+// an empty schema, which accepts any value.
+func (r *openAPIRefResolver) inline(v any, seen []string) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			if slices.Contains(seen, ref) {
+				return map[string]any{}, nil
+			}
+			target, err := r.lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+			return r.inline(target, append(seen, ref))
+		}
+		inlined := make(map[string]any, len(v))
+		for k, e := range v {
+			i, err := r.inline(e, seen)
+			if err != nil {
+				return nil, err
+			}
+			inlined[k] = i
+		}
+		return inlined, nil
+	case []any:
+		inlined := make([]any, len(v))
+		for i, e := range v {
+			var err error
+			if inlined[i], err = r.inline(e, seen); err != nil {
+				return nil, err
+			}
+		}
+		return inlined, nil
+	default:
+		return v, nil
+	}
+}
+
+// lookup returns the value a local reference (eg- #/components/schemas/Pet) points to.
+func (r *openAPIRefResolver) lookup(ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %s: only references within the spec are supported", ref)
+	}
+	var v any = r.doc
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("reference %s does not exist", ref)
+		}
+		if v, ok = obj[token]; !ok {
+			return nil, fmt.Errorf("reference %s does not exist", ref)
+		}
+	}
+	return v, nil
+}
+
+// normalizeYAML converts the maps decoded by the YAML parser, whose keys may not be strings (eg- 200),
+// into maps with string keys so that they can be encoded as JSON.
+func normalizeYAML(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = normalizeYAML(e)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = normalizeYAML(e)
+		}
+		return m
+	case []any:
+		for i, e := range v {
+			v[i] = normalizeYAML(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,345 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: "1.0"
+servers:
+  - url: /api/{version}
+    variables:
+      version:
+        default: v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        schema:
+          type: string
+    get:
+      operationId: list.pets
+      parameters:
+        - name: X-Trace
+          in: header
+        - name: session
+          in: cookie
+    delete: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        parent:
+          $ref: '#/components/schemas/Pet'
+`
+
+func TestParseOpenAPISpec(t *testing.T) {
+	spec, err := parseOpenAPISpec([]byte(testOpenAPISpec))
+	if err != nil {
+		t.Fatalf("failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		tool   string
+		method string
+		schema string
+	}{
+		{
+			tool:   "listPets",
+			method: "get",
+			schema: `{"properties":{"limit":{"type":"integer"}},"type":"object"}`,
+		},
+		{
+			tool:   "createPet",
+			method: "post",
+			schema: `{"properties":{"body":{"properties":{"name":{"type":"string"},"parent":{}},` +
+				`"required":["name"],"type":"object"}},"required":["body"],"type":"object"}`,
+		},
+		{
+			tool:   "list_pets",
+			method: "get",
+			schema: `{"properties":{"X-Trace":{"type":"string"},"petId":{"type":"string"}},` +
+				`"required":["petId"],"type":"object"}`,
+		},
+		{
+			tool:   "delete_pets_petId",
+			method: "delete",
+			schema: `{"properties":{"petId":{"type":"string"}},"required":["petId"],"type":"object"}`,
+		},
+	}
+	if len(spec.operations) != len(tests) {
+		t.Fatalf("expected %d operations, got %d", len(tests), len(spec.operations))
+	}
+	for i, tt := range tests {
+		op := spec.operations[i]
+		if op.tool.Name != tt.tool || op.method != tt.method {
+			t.Errorf("operation %d: expected %s %s, got %s %s", i, tt.method, tt.tool, op.method, op.tool.Name)
+		}
+		if string(op.tool.RawInputSchema) != tt.schema {
+			t.Errorf("tool %s: expected schema %s, got %s", tt.tool, tt.schema, op.tool.RawInputSchema)
+		}
+	}
+
+	if _, err := parseOpenAPISpec([]byte(`{"swagger": "2.0", "paths": {}}`)); err == nil {
+		t.Error("expected an error for a swagger 2.0 spec")
+	}
+}
+
+func TestOpenAPISpecBaseURL(t *testing.T) {
+	spec, err := parseOpenAPISpec([]byte(testOpenAPISpec))
+	if err != nil {
+		t.Fatalf("failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		configured string
+		specURL    string
+		want       string
+		wantErr    bool
+	}{
+		{name: "configured", configured: "https://pets.example.com", want: "https://pets.example.com"},
+		{name: "relative to spec", specURL: "https://example.com/docs/openapi.yaml", want: "https://example.com/api/v1"},
+		{name: "relative without spec url", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := spec.baseURL(tt.configured, tt.specURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOpenAPIOperationNewRequest(t *testing.T) {
+	spec, err := parseOpenAPISpec([]byte(testOpenAPISpec))
+	if err != nil {
+		t.Fatalf("failed to parse spec: %v", err)
+	}
+	ops := make(map[string]*openAPIOperation)
+	for _, op := range spec.operations {
+		ops[op.tool.Name] = op
+	}
+
+	tests := []struct {
+		name     string
+		tool     string
+		args     map[string]any
+		wantURL  string
+		wantBody string
+		wantErr  bool
+	}{
+		{
+			name:    "query parameter",
+			tool:    "listPets",
+			args:    map[string]any{"limit": float64(10)},
+			wantURL: "https://example.com/api/v1/pets?limit=10",
+		},
+		{
+			name:     "json body",
+			tool:     "createPet",
+			args:     map[string]any{"body": map[string]any{"name": "rex"}},
+			wantURL:  "https://example.com/api/v1/pets",
+			wantBody: `{"name":"rex"}`,
+		},
+		{
+			name:    "escaped path parameter",
+			tool:    "delete_pets_petId",
+			args:    map[string]any{"petId": "a/b"},
+			wantURL: "https://example.com/api/v1/pets/a%2Fb",
+		},
+		{name: "missing path parameter", tool: "delete_pets_petId", args: map[string]any{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := ops[tt.tool].newRequest(context.Background(), "https://example.com/api/v1/", nil, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if req.URL.String() != tt.wantURL {
+				t.Errorf("expected url %s, got %s", tt.wantURL, req.URL)
+			}
+			var body string
+			if req.Body != nil {
+				data, _ := io.ReadAll(req.Body)
+				body = string(data)
+			}
+			if body != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, body)
+			}
+			if tt.wantBody != "" && !strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+				t.Errorf("expected a json content type, got %s", req.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
+
+const testOpenAPIHeadersSpec = `
+openapi: 3.0.3
+info:
+  title: Notes
+  version: "1.0"
+servers:
+  - url: https://example.com
+paths:
+  /notes/{id}:
+    parameters:
+      - name: id
+        in: path
+        schema:
+          type: string
+    get:
+      operationId: getNote
+    put:
+      operationId: putNote
+      parameters:
+        - name: Authorization
+          in: header
+        - name: X-Tenant
+          in: header
+        - name: X-Trace
+          in: header
+    delete:
+      operationId: deleteNote
+    patch:
+      operationId: patchNote
+  /notes:
+    post:
+      operationId: createNote
+`
+
+func TestOpenAPIOperationHeaderParameters(t *testing.T) {
+	spec, err := parseOpenAPISpec([]byte(testOpenAPIHeadersSpec))
+	if err != nil {
+		t.Fatalf("failed to parse spec: %v", err)
+	}
+	var put *openAPIOperation
+	for _, op := range spec.operations {
+		if op.tool.Name == "putNote" {
+			put = op
+		}
+	}
+	if put == nil {
+		t.Fatalf("expected the spec to have a putNote operation")
+	}
+
+	headers := upstreamHeaders(map[string]string{"x-tenant": "acme"}, "secret")
+	args := map[string]any{"id": "1", "Authorization": "Bearer stolen", "X-Tenant": "other", "X-Trace": "abc"}
+	req, err := put.newRequest(context.Background(), "https://example.com", headers, args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("expected the configured bearer token to be kept, got %s", got)
+	}
+	if got := req.Header.Get("X-Tenant"); got != "acme" {
+		t.Errorf("expected the configured header to be kept, got %s", got)
+	}
+	if got := req.Header.Get("X-Trace"); got != "abc" {
+		t.Errorf("expected other header parameters to be sent, got %s", got)
+	}
+
+	// credentials are protected even if the API is not configured with any
+	req, err = put.newRequest(context.Background(), "https://example.com", nil, args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "" {
+		t.Errorf("expected the Authorization argument to be ignored, got %s", got)
+	}
+}
+
+func TestOpenAPIOperationsCacheable(t *testing.T) {
+	spec, err := parseOpenAPISpec([]byte(testOpenAPIHeadersSpec))
+	if err != nil {
+		t.Fatalf("failed to parse spec: %v", err)
+	}
+	if len(spec.operations) != 5 {
+		t.Fatalf("expected 5 operations, got %d", len(spec.operations))
+	}
+	// an openapi server with a cache policy must never answer a call that changes data from the cache
+	for _, op := range spec.operations {
+		want := op.method == "get"
+		if got := isReadOnlyTool(&op.tool.Annotations); got != want {
+			t.Errorf("%s %s: expected cacheable to be %v, got %v", op.method, op.path, want, got)
+		}
+	}
+}
+
+func TestOpenAPIOperationCallResponseLimit(t *testing.T) {
+	spec, err := parseOpenAPISpec([]byte(testOpenAPISpec))
+	if err != nil {
+		t.Fatalf("failed to parse spec: %v", err)
+	}
+	var list *openAPIOperation
+	for _, op := range spec.operations {
+		if op.tool.Name == "listPets" {
+			list = op
+		}
+	}
+	if list == nil {
+		t.Fatalf("expected the spec to have a listPets operation")
+	}
+
+	size := openAPIMaxResponseSize
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", size)))
+	}))
+	defer srv.Close()
+
+	// a response of exactly the maximum size is relayed as is
+	res, err := list.call(context.Background(), srv.Client(), srv.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.IsError {
+		t.Errorf("expected a response of the maximum size to succeed, got %v", res.Content)
+	}
+
+	// a larger response is never truncated, the call fails instead
+	size = openAPIMaxResponseSize + 1
+	res, err = list.call(context.Background(), srv.Client(), srv.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Errorf("expected a tool error for a response over the maximum size")
+	}
+}
@@ -2,9 +2,11 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
 )
 
 // initMCPProxyServer initializes the MCP proxy server.
@@ -26,7 +28,7 @@ func (m *MCPService) initMCPProxyServer() error {
 			return fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", tm.Name, err)
 		}
 
-		m.mcpProxyServer.AddTool(tool, m.mcpProxyToolCallHandler)
+		m.addProxyTool(tool)
 	}
 	return nil
 }
@@ -35,24 +37,30 @@ func (m *MCPService) initMCPProxyServer() error {
 // by forwarding the request to the appropriate upstream MCP server and
 // relaying the response back.
 func (m *MCPService) mcpProxyToolCallHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	name := request.Params.Name
+	// clients may call a tool by its alias, but it is identified by its canonical name everywhere else
+	name := m.resolveToolAlias(request.Params.Name)
 	serverName, toolName, ok := splitServerToolName(name)
 	if !ok {
 		return nil, fmt.Errorf("invalid input: tool name does not contain a %s separator", serverToolNameSep)
 	}
 
+	var clientName string
 	serverMode := ctx.Value("mode").(model.ServerMode)
 	if serverMode == model.ModeProd {
 		// In production mode, we need to check whether the MCP client is authorized to access the MCP server.
 		// If not, return error Unauthorized.
 		c := ctx.Value("client").(*model.McpClient)
-		if !c.CheckHasServerAccess(serverName) {
+		if !m.clientHasServerAccess(c, serverName) {
 			return nil, fmt.Errorf(
 				"client %s is not authorized to access MCP server %s", c.Name, serverName,
 			)
 		}
+		clientName = c.Name
 	}
 
+	// inject the pinned arguments before validation, so that the call is validated as the upstream server sees it
+	m.applyArgumentPresets(&request, name, clientName)
+
 	// get the MCP server details from the database
 	server, err := m.GetMcpServer(serverName)
 	if err != nil {
@@ -61,15 +69,33 @@ func (m *MCPService) mcpProxyToolCallHandler(ctx context.Context, request mcp.Ca
 		)
 	}
 
-	mcpClient, err := newMcpServerSession(ctx, server)
-	if err != nil {
+	// reject bad arguments before they count against any rate limit or reach the upstream server
+	if err := m.validateToolArguments(server, toolName, request.Params.Arguments); err != nil {
+		if result, ok := toolArgumentsErrorResult(err); ok {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	if err := m.rateLimitService.Allow(clientName, serverName, name); err != nil {
+		var rlErr *ratelimit.RateLimitError
+		if errors.As(err, &rlErr) {
+			// report the rate limit as a tool error so that the LLM can see it and back off
+			result := mcp.NewToolResultError(rlErr.Error())
+			result.Meta = mcp.NewMetaFromMap(map[string]any{"retryAfterSeconds": rlErr.RetryAfterSeconds()})
+			return result, nil
+		}
 		return nil, err
 	}
-	defer mcpClient.Close()
+
+	// let the client cancel the call, then make sure its headers don't leak to the upstream server
+	ctx, done := m.trackProxyCall(ctx, request)
+	defer done()
+	request.Header = nil
 
 	// Ensure the tool name is set correctly, ie, without the server name prefix
 	request.Params.Name = toolName
 
 	// forward the request to the upstream MCP server and relay the response back
-	return mcpClient.CallTool(ctx, request)
+	return m.callUpstreamTool(ctx, server, request)
 }
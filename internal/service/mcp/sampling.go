@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxTrackedClientCapabilities bounds the number of downstream sessions whose capabilities are remembered.
+// Streamable http sessions that never open a listening connection are not unregistered when the client
+// goes away, so their entries would otherwise accumulate.
+const maxTrackedClientCapabilities = 10000
+
+// downstreamBridge forwards the sampling and elicitation requests that an upstream MCP server makes
+// during a tool call to the downstream client session that made the call.
+type downstreamBridge struct {
+	proxy *server.MCPServer
+	// ctx is the context of the downstream tool call, it carries the client session
+	ctx context.Context
+}
+
+func (b *downstreamBridge) CreateMessage(
+	ctx context.Context, request mcp.CreateMessageRequest,
+) (*mcp.CreateMessageResult, error) {
+	return b.proxy.RequestSampling(b.ctx, request)
+}
+
+func (b *downstreamBridge) Elicit(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	return b.proxy.RequestElicitation(b.ctx, request)
+}
+
+// downstreamClientOptions returns the options for a session with an upstream MCP server that is opened
+// to serve a tool call made through the proxy.
+// Sampling and elicitation are advertised to the upstream server only if the downstream client declared
+// support for them and its session can relay them.
+// No options are returned for calls that were not made through the proxy.
+func (m *MCPService) downstreamClientOptions(ctx context.Context) []client.ClientOption {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil
+	}
+	capabilities := m.sessionClientCapabilities(session)
+	bridge := &downstreamBridge{proxy: m.mcpProxyServer, ctx: ctx}
+
+	var opts []client.ClientOption
+	if _, ok := session.(server.SessionWithSampling); ok && capabilities.Sampling != nil {
+		opts = append(opts, client.WithSamplingHandler(bridge))
+	}
+	if _, ok := session.(server.SessionWithElicitation); ok && capabilities.Elicitation != nil {
+		opts = append(opts, client.WithElicitationHandler(bridge))
+	}
+	return opts
+}
+
+// sessionClientCapabilities returns the capabilities that a downstream client declared when it initialized
+// its session with the proxy.
+func (m *MCPService) sessionClientCapabilities(session server.ClientSession) mcp.ClientCapabilities {
+	if s, ok := session.(server.SessionWithClientInfo); ok {
+		return s.GetClientCapabilities()
+	}
+	m.clientCapabilitiesMu.Lock()
+	defer m.clientCapabilitiesMu.Unlock()
+	return m.clientCapabilities[session.SessionID()]
+}
+
+// recordClientCapabilities remembers the capabilities declared by a downstream client for sessions
+// that don't keep track of them.
+// Only sessions that declare sampling or elicitation support are remembered, since nothing else is needed.
+func (m *MCPService) recordClientCapabilities(
+	ctx context.Context, id any, request *mcp.InitializeRequest, result *mcp.InitializeResult,
+) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+	if _, ok := session.(server.SessionWithClientInfo); ok {
+		return
+	}
+	capabilities := request.Params.Capabilities
+	if capabilities.Sampling == nil && capabilities.Elicitation == nil {
+		return
+	}
+
+	m.clientCapabilitiesMu.Lock()
+	defer m.clientCapabilitiesMu.Unlock()
+	if len(m.clientCapabilities) >= maxTrackedClientCapabilities {
+		// evict an arbitrary session to make room
+		for k := range m.clientCapabilities {
+			delete(m.clientCapabilities, k)
+			break
+		}
+	}
+	m.clientCapabilities[session.SessionID()] = capabilities
+}
+
+// forgetClientCapabilities removes the recorded capabilities of a downstream session once it has ended.
+func (m *MCPService) forgetClientCapabilities(sessionID string) {
+	m.clientCapabilitiesMu.Lock()
+	delete(m.clientCapabilities, sessionID)
+	m.clientCapabilitiesMu.Unlock()
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"testing"
+)
+
+// samplingSession is a session that can relay sampling & elicitation requests to its client
+type samplingSession struct {
+	fakeSession
+}
+
+func (s *samplingSession) RequestSampling(
+	ctx context.Context, request mcp.CreateMessageRequest,
+) (*mcp.CreateMessageResult, error) {
+	return &mcp.CreateMessageResult{Model: "test"}, nil
+}
+
+func (s *samplingSession) RequestElicitation(
+	ctx context.Context, request mcp.ElicitationRequest,
+) (*mcp.ElicitationResult, error) {
+	return &mcp.ElicitationResult{}, nil
+}
+
+func TestDownstreamClientOptions(t *testing.T) {
+	sampling := mcp.ClientCapabilities{Sampling: &struct{}{}}
+	both := mcp.ClientCapabilities{Sampling: &struct{}{}, Elicitation: &struct{}{}}
+
+	tests := []struct {
+		name         string
+		session      server.ClientSession
+		capabilities *mcp.ClientCapabilities
+		wantOpts     int
+	}{
+		{name: "call not made through the proxy", wantOpts: 0},
+		{
+			name:         "session cannot relay requests",
+			session:      &fakeSession{id: "s1"},
+			capabilities: &both,
+			wantOpts:     0,
+		},
+		{name: "client declared no capabilities", session: &samplingSession{fakeSession{id: "s2"}}, wantOpts: 0},
+		{
+			name:         "client supports sampling",
+			session:      &samplingSession{fakeSession{id: "s3"}},
+			capabilities: &sampling,
+			wantOpts:     1,
+		},
+		{
+			name:         "client supports sampling and elicitation",
+			session:      &samplingSession{fakeSession{id: "s4"}},
+			capabilities: &both,
+			wantOpts:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := &server.Hooks{}
+			proxy := server.NewMCPServer("test", "0.0.1", server.WithHooks(hooks))
+			m := &MCPService{
+				mcpProxyServer:     proxy,
+				inflight:           make(map[inflightCallKey]context.CancelFunc),
+				clientCapabilities: make(map[string]mcp.ClientCapabilities),
+			}
+			m.registerProxyHooks(proxy, hooks)
+
+			ctx := context.Background()
+			if tt.session != nil {
+				ctx = proxy.WithContext(ctx, tt.session)
+			}
+			if tt.capabilities != nil {
+				request := &mcp.InitializeRequest{}
+				request.Params.Capabilities = *tt.capabilities
+				for _, hook := range hooks.OnAfterInitialize {
+					hook(ctx, mcp.NewRequestId(int64(1)), request, &mcp.InitializeResult{})
+				}
+			}
+
+			if got := len(m.downstreamClientOptions(ctx)); got != tt.wantOpts {
+				t.Errorf("expected %d client options, got %d", tt.wantOpts, got)
+			}
+
+			if tt.session != nil {
+				for _, hook := range hooks.OnUnregisterSession {
+					hook(ctx, tt.session)
+				}
+			}
+			if len(m.clientCapabilities) != 0 {
+				t.Errorf("expected capabilities to be forgotten once the session ended")
+			}
+		})
+	}
+}
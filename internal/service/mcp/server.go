@@ -3,7 +3,10 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/db"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"log"
 )
 
 // RegisterMcpServer registers a new MCP server in the database.
@@ -14,8 +17,15 @@ func (m *MCPService) RegisterMcpServer(ctx context.Context, s *model.McpServer)
 	if err := validateServerName(s.Name); err != nil {
 		return err
 	}
+	if s.Transport == types.TransportOpenAPI {
+		if err := loadOpenAPISpec(ctx, s); err != nil {
+			return fmt.Errorf("failed to load OpenAPI spec of server %s: %w", s.Name, err)
+		}
+		// discard the tools of a previous attempt to register the server, which may have used another spec
+		m.forgetOpenAPIMcpServer(s.Name)
+	}
 
-	mcpClient, err := newMcpServerSession(ctx, s)
+	mcpClient, err := m.newMcpServerSession(ctx, s)
 	if err != nil {
 		return err
 	}
@@ -41,6 +51,12 @@ func (m *MCPService) DeregisterMcpServer(name string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
 	}
+
+	// a health probe that finishes meanwhile must not add the tools of the server back to the proxy,
+	// see recordProbeResult
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+
 	if err := m.deregisterServerTools(s); err != nil {
 		return fmt.Errorf(
 			"failed to deregister tools for server %s, cannot proceed with server deregistration: %w",
@@ -51,16 +67,57 @@ func (m *MCPService) DeregisterMcpServer(name string) error {
 	if err := m.db.Unscoped().Delete(s).Error; err != nil {
 		return fmt.Errorf("failed to deregister server %s: %w", name, err)
 	}
+	delete(m.health, name)
+	m.forgetCircuitBreaker(name)
+	m.forgetConcurrencyLimiter(name)
+	m.forgetResultCache(name)
+	m.forgetOpenAPIMcpServer(name)
+	if err := m.deleteOAuthToken(name); err != nil {
+		log.Printf("[WARN] %v", err)
+	}
 	return nil
 }
 
 // ListMcpServers returns all registered MCP servers.
 func (m *MCPService) ListMcpServers() ([]model.McpServer, error) {
-	var servers []model.McpServer
-	if err := m.db.Find(&servers).Error; err != nil {
-		return nil, err
+	servers, _, err := m.ListMcpServersPage(nil, types.ListOptions{})
+	return servers, err
+}
+
+// serverSortFields are the fields that MCP servers can be sorted by.
+var serverSortFields = map[string]db.SortField[model.McpServer]{
+	"name": {
+		Columns: []db.SortColumn{{Expr: "name"}, {Expr: "id"}},
+		Values:  func(s model.McpServer) []any { return []any{s.Name, s.ID} },
+	},
+	"created_at": {
+		Columns: []db.SortColumn{{Expr: "created_at"}, {Expr: "id"}},
+		Values:  func(s model.McpServer) []any { return []any{s.CreatedAt, s.ID} },
+	},
+}
+
+// ListMcpServersPage returns a page of the registered MCP servers whose labels match the selector,
+// along with the cursor of the next page, which is empty on the last page.
+// Servers are sorted by name unless the options specify otherwise.
+func (m *MCPService) ListMcpServersPage(
+	sel types.LabelSelector,
+	opts types.ListOptions,
+) ([]model.McpServer, string, error) {
+	if opts.Sort == "" {
+		opts.Sort = "name"
+	}
+	var keep func(model.McpServer) bool
+	if len(sel) > 0 {
+		keep = func(s model.McpServer) bool {
+			labels, err := s.GetLabels()
+			if err != nil {
+				log.Printf("[WARN] failed to decode labels of server %s: %v", s.Name, err)
+				return false
+			}
+			return sel.Matches(labels)
+		}
 	}
-	return servers, nil
+	return db.FindPage(m.db.Model(&model.McpServer{}), serverSortFields, opts, keep)
 }
 
 // GetMcpServer fetches a server from the database by name.
@@ -5,27 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/db"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
 	"log"
+	"maps"
+	"strings"
 )
 
 // ListTools returns all tools registered in the registry.
 func (m *MCPService) ListTools() ([]model.Tool, error) {
-	var tools []model.Tool
-	if err := m.db.Find(&tools).Error; err != nil {
-		return nil, err
-	}
-	// prepend server name to tool names to ensure we only return the unique names of tools to user
-	for i := range tools {
-		var s model.McpServer
-		if err := m.db.First(&s, "id = ?", tools[i].ServerID).Error; err != nil {
-			return nil, fmt.Errorf("failed to get server for tool %s: %w", tools[i].Name, err)
-		}
-		tools[i].Name = mergeServerToolNames(s.Name, tools[i].Name)
-	}
-	return tools, nil
+	return m.SearchTools(types.ToolFilter{})
 }
 
 // ListToolsByServer fetches tools provided by an MCP server from the registry.
@@ -33,25 +26,160 @@ func (m *MCPService) ListToolsByServer(name string) ([]model.Tool, error) {
 	if err := validateServerName(name); err != nil {
 		return nil, err
 	}
+	if _, err := m.GetMcpServer(name); err != nil {
+		return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
+	}
+	return m.SearchTools(types.ToolFilter{Server: name})
+}
 
-	s, err := m.GetMcpServer(name)
+// SearchTools returns all tools that match the filter, with their canonical names.
+// If the filter has a query, tools are ranked by relevance, otherwise they are sorted by name.
+func (m *MCPService) SearchTools(filter types.ToolFilter) ([]model.Tool, error) {
+	tools, _, err := m.ListToolsPage(filter, types.ListOptions{})
+	return tools, err
+}
+
+// toolRow is a tool as returned by the queries that list tools, along with the columns of its server.
+type toolRow struct {
+	model.Tool
+	ServerName   string
+	ServerLabels datatypes.JSON
+	Score        int
+}
+
+// effectiveLabels returns the labels of the tool, including the ones it inherits from its server.
+func (r *toolRow) effectiveLabels() (map[string]string, error) {
+	server := model.McpServer{Labels: r.ServerLabels}
+	serverLabels, err := server.GetLabels()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
+		return nil, fmt.Errorf("failed to decode labels of server %s: %w", r.ServerName, err)
 	}
+	toolLabels, err := r.Tool.GetLabels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode labels of tool %s: %w", r.Tool.Name, err)
+	}
+	return model.EffectiveLabels(serverLabels, toolLabels), nil
+}
 
-	var tools []model.Tool
-	if err := m.db.Where("server_id = ?", s.ID).Find(&tools).Error; err != nil {
-		return nil, fmt.Errorf("failed to get tools for server %s from DB: %w", name, err)
+// toolSortFields are the fields that tools can be sorted by.
+// Tools are sorted by their canonical name, ie- by the name of their server first.
+var toolSortFields = map[string]db.SortField[toolRow]{
+	"name": {
+		Columns: []db.SortColumn{{Expr: "mcp_servers.name"}, {Expr: "tools.name"}, {Expr: "tools.id"}},
+		Values:  func(r toolRow) []any { return []any{r.ServerName, r.Tool.Name, r.ID} },
+	},
+	"created_at": {
+		Columns: []db.SortColumn{{Expr: "tools.created_at"}, {Expr: "tools.id"}},
+		Values:  func(r toolRow) []any { return []any{r.CreatedAt, r.ID} },
+	},
+}
+
+// ListToolsPage returns a page of the tools that match the filter, with their canonical names, along with
+// the cursor of the next page, which is empty on the last page.
+// If the filter has a query, tools are ranked by relevance unless the options specify another sort field,
+// otherwise they are sorted by name.
+// The search only relies on LIKE so that it behaves the same on SQLite and Postgres.
+// Labels are stored as JSON, so the label selector is applied to the results of the query.
+func (m *MCPService) ListToolsPage(filter types.ToolFilter, opts types.ListOptions) ([]model.Tool, string, error) {
+	sel, err := types.ParseLabelSelector(filter.Labels)
+	if err != nil {
+		return nil, "", err
+	}
+	if filter.Tag != "" {
+		// a tag is a label whose value does not matter
+		if err := types.ValidateLabels(map[string]string{filter.Tag: ""}); err != nil {
+			return nil, "", fmt.Errorf("invalid tag: %w", err)
+		}
+		sel = append(sel, types.LabelRequirement{Key: filter.Tag, Operator: types.LabelExists})
 	}
 
-	// prepend server name to tool names to ensure we only return the unique names of tools to user
-	for i := range tools {
-		tools[i].Name = mergeServerToolNames(s.Name, tools[i].Name)
+	// the server of each tool is joined in, rather than looked up tool by tool
+	q := m.db.Model(&model.Tool{}).
+		Joins("JOIN mcp_servers ON mcp_servers.id = tools.server_id AND mcp_servers.deleted_at IS NULL")
+
+	if filter.Server != "" {
+		q = q.Where("mcp_servers.name = ?", filter.Server)
+	}
+	if filter.Enabled != nil {
+		q = q.Where("tools.enabled = ?", *filter.Enabled)
+	}
+
+	fields := toolSortFields
+	score, scoreArgs := toolSearchScore(strings.Fields(strings.ToLower(filter.Query)))
+	if score != "" {
+		q = q.Select(
+			"tools.*, mcp_servers.name AS server_name, mcp_servers.labels AS server_labels, "+score+" AS score",
+			scoreArgs...,
+		).
+			Where(score+" > 0", scoreArgs...)
+
+		fields = maps.Clone(toolSortFields)
+		fields["relevance"] = db.SortField[toolRow]{
+			Columns: append(
+				[]db.SortColumn{{Expr: score, Args: scoreArgs, Desc: true}},
+				toolSortFields["name"].Columns...,
+			),
+			Values: func(r toolRow) []any { return append([]any{r.Score}, toolSortFields["name"].Values(r)...) },
+		}
+		if opts.Sort == "" {
+			opts.Sort = "relevance"
+		}
+	} else {
+		q = q.Select("tools.*, mcp_servers.name AS server_name, mcp_servers.labels AS server_labels")
+	}
+	if opts.Sort == "" {
+		opts.Sort = "name"
+	}
+
+	var keep func(toolRow) bool
+	if len(sel) > 0 {
+		keep = func(r toolRow) bool {
+			labels, err := r.effectiveLabels()
+			if err != nil {
+				log.Printf("[WARN] %v", err)
+				return false
+			}
+			return sel.Matches(labels)
+		}
 	}
 
-	return tools, nil
+	rows, next, err := db.FindPage(q, fields, opts, keep)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list tools: %w", err)
+	}
+	tools := make([]model.Tool, len(rows))
+	for i, r := range rows {
+		tools[i] = r.Tool
+		tools[i].Name = mergeServerToolNames(r.ServerName, r.Tool.Name)
+	}
+	return tools, next, nil
+}
+
+// toolSearchScore builds the SQL expression that scores the relevance of a tool for the search terms,
+// along with its arguments. A term found in the canonical name of a tool weighs more than one found
+// in its title, which weighs more than one found in its description.
+// It returns an empty expression if there are no terms.
+func toolSearchScore(terms []string) (string, []any) {
+	if len(terms) == 0 {
+		return "", nil
+	}
+	var parts []string
+	var args []any
+	for _, term := range terms {
+		pattern := "%" + likeEscaper.Replace(term) + "%"
+		parts = append(parts,
+			`(CASE WHEN LOWER(mcp_servers.name || '__' || tools.name) LIKE ? ESCAPE '\' THEN 3 ELSE 0 END)`,
+			`(CASE WHEN LOWER(tools.title) LIKE ? ESCAPE '\' THEN 2 ELSE 0 END)`,
+			`(CASE WHEN LOWER(tools.description) LIKE ? ESCAPE '\' THEN 1 ELSE 0 END)`,
+		)
+		args = append(args, pattern, pattern, pattern)
+	}
+	return "(" + strings.Join(parts, " + ") + ")", args
 }
 
+// likeEscaper escapes the wildcards of LIKE patterns, tool names often contain underscores.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 func (m *MCPService) GetTool(name string) (*model.Tool, error) {
 	serverName, toolName, ok := splitServerToolName(name)
 	if !ok {
@@ -72,35 +200,24 @@ func (m *MCPService) GetTool(name string) (*model.Tool, error) {
 	return &tool, nil
 }
 
-// InvokeTool invokes a tool from a registered MCP server and returns its response.
+// InvokeTool invokes a tool from a registered MCP server or a composite tool and returns its response.
+// The tool can be referred to by its canonical name or its alias.
 func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[string]any) (*types.ToolInvokeResult, error) {
-	serverName, toolName, ok := splitServerToolName(name)
-	if !ok {
-		return nil, fmt.Errorf("invalid input: tool name does not contain a %s separator", serverToolNameSep)
+	name = m.resolveToolAlias(name)
+
+	var (
+		callToolResp *mcp.CallToolResult
+		err          error
+	)
+	if _, _, ok := splitServerToolName(name); ok {
+		callToolResp, err = m.invokeUpstreamTool(ctx, name, args)
+	} else {
+		// composite tools are the only tools whose names don't contain the server name
+		callToolResp, err = m.invokeCompositeTool(ctx, name, args)
 	}
-	serverModel, err := m.GetMcpServer(serverName)
-	if err != nil {
-		return nil, fmt.Errorf(
-			"failed to get details about MCP server %s from DB: %w",
-			serverName,
-			err,
-		)
-	}
-
-	mcpClient, err := newMcpServerSession(ctx, serverModel)
 	if err != nil {
 		return nil, err
 	}
-	defer mcpClient.Close()
-
-	callToolReq := mcp.CallToolRequest{}
-	callToolReq.Params.Name = toolName
-	callToolReq.Params.Arguments = args
-
-	callToolResp, err := mcpClient.CallTool(ctx, callToolReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call tool %s on MCP server %s: %w", toolName, serverName, err)
-	}
 
 	// NOTE: callToolResp.Content is a list of Content objects.
 	// If the tool returns a list as its result, it gets converted to a list of Content objects.
@@ -111,30 +228,85 @@ func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[strin
 	// We don't attempt to cast the data into specific types because this method should simply
 	// forward the tool's response to the client.
 	// It is up to the client of this API to convert the data into specific types like
-	// Text, Image, etc.
+	// Text, Image, Resource, etc.
 	contentList := make([]map[string]any, 0, len(callToolResp.Content))
-	for _, item := range callToolResp.Content {
-		var m map[string]any
-		serialized, err := json.Marshal(item)
+	for i, item := range callToolResp.Content {
+		c, err := convertContentToMap(item)
 		if err != nil {
-			// TODO
-			continue
+			// never silently drop a part of the tool's response, the caller would be misled
+			return nil, fmt.Errorf("failed to convert content item %d of the response of tool %s: %w", i, name, err)
 		}
-		if err = json.Unmarshal(serialized, &m); err != nil {
-			// TODO
-			continue
+		contentList = append(contentList, c)
+	}
+
+	var meta map[string]any
+	if callToolResp.Meta != nil {
+		meta = make(map[string]any, len(callToolResp.Meta.AdditionalFields)+1)
+		maps.Copy(meta, callToolResp.Meta.AdditionalFields)
+		if callToolResp.Meta.ProgressToken != nil {
+			meta["progressToken"] = callToolResp.Meta.ProgressToken
 		}
-		contentList = append(contentList, m)
 	}
 
 	result := &types.ToolInvokeResult{
-		Meta:    callToolResp.Meta,
-		IsError: callToolResp.IsError,
-		Content: contentList,
+		Meta:              meta,
+		IsError:           callToolResp.IsError,
+		Content:           contentList,
+		StructuredContent: callToolResp.StructuredContent,
 	}
 	return result, nil
 }
 
+// invokeUpstreamTool calls a tool of a registered MCP server on behalf of the API.
+// name must be the canonical name of the tool.
+func (m *MCPService) invokeUpstreamTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	serverName, toolName, ok := splitServerToolName(name)
+	if !ok {
+		return nil, fmt.Errorf("invalid input: tool name does not contain a %s separator", serverToolNameSep)
+	}
+	serverModel, err := m.GetMcpServer(serverName)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get details about MCP server %s from DB: %w",
+			serverName,
+			err,
+		)
+	}
+
+	callToolReq := mcp.CallToolRequest{}
+	callToolReq.Params.Name = toolName
+	callToolReq.Params.Arguments = args
+	// tools invoked via the API are not called by an MCP client, so only the presets for all clients apply
+	m.applyArgumentPresets(&callToolReq, name, "")
+
+	if err := m.validateToolArguments(serverModel, toolName, callToolReq.GetArguments()); err != nil {
+		return nil, err
+	}
+
+	// tools invoked via the API are not called by an MCP client, so only tool-scoped rate limits apply
+	if err := m.rateLimitService.Allow("", serverName, name); err != nil {
+		return nil, err
+	}
+
+	return m.callUpstreamTool(ctx, serverModel, callToolReq)
+}
+
+// convertContentToMap converts a content item of a tool result into a generic JSON object.
+func convertContentToMap(item mcp.Content) (map[string]any, error) {
+	serialized, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize content: %w", err)
+	}
+	var c map[string]any
+	if err := json.Unmarshal(serialized, &c); err != nil {
+		return nil, fmt.Errorf("failed to deserialize content: %w", err)
+	}
+	if _, ok := c["type"]; !ok {
+		return nil, fmt.Errorf("content has no type: %s", serialized)
+	}
+	return c, nil
+}
+
 // EnableTools enables one or more tools.
 // If the entity is a tool name, only that tool is enabled.
 // If the entity is a server name, all tools of that server are enabled.
@@ -181,6 +353,11 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 		}
 
 		if enabled {
+			if !m.isServerHealthy(s.Name) {
+				// the server is currently unreachable, so its tools stay hidden from the proxy.
+				// the health checker adds the tool to the proxy once the server recovers.
+				return []string{entity}, nil
+			}
 			// if the tool was enabled, add it back to the MCP proxy server
 			mcpTool, err := convertToolModelToMcpObject(&tool)
 			if err != nil {
@@ -188,10 +365,10 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 			}
 			// set the tool name to its canonical form in the proxy
 			mcpTool.Name = entity
-			m.mcpProxyServer.AddTool(mcpTool, m.mcpProxyToolCallHandler)
+			m.addProxyTool(mcpTool)
 		} else {
 			// if the tool was disabled, remove it from the MCP proxy server
-			m.mcpProxyServer.DeleteTools(entity)
+			m.deleteProxyTools(entity)
 		}
 
 		return []string{entity}, nil
@@ -219,8 +396,13 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 			return nil, fmt.Errorf("failed to set tool %s enabled=%t: %w", tools[i].Name, enabled, err)
 		}
 		canonicalToolName := mergeServerToolNames(s.Name, tools[i].Name)
+		changedToolNames = append(changedToolNames, canonicalToolName)
 
 		if enabled {
+			if !m.isServerHealthy(s.Name) {
+				// tools of an unreachable server stay hidden from the proxy until it recovers
+				continue
+			}
 			mcpTool, err := convertToolModelToMcpObject(&tools[i])
 			if err != nil {
 				return nil, fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", tools[i].Name, err)
@@ -228,12 +410,10 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 			// set the tool name to its canonical form in the proxy
 			mcpTool.Name = canonicalToolName
 
-			m.mcpProxyServer.AddTool(mcpTool, m.mcpProxyToolCallHandler)
+			m.addProxyTool(mcpTool)
 		} else {
-			m.mcpProxyServer.DeleteTools(canonicalToolName)
+			m.deleteProxyTools(canonicalToolName)
 		}
-
-		changedToolNames = append(changedToolNames, canonicalToolName)
 	}
 
 	return changedToolNames, nil
@@ -242,37 +422,119 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 // registerServerTools fetches all tools from an MCP server and registers them in the DB.
 func (m *MCPService) registerServerTools(ctx context.Context, s *model.McpServer, c *client.Client) error {
 	// fetch all tools from the server so they can be added to the DB
-	resp, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	rawTools, err := listUpstreamTools(ctx, c)
 	if err != nil {
 		return fmt.Errorf("failed to fetch tools from MCP server %s: %w", s.Name, err)
 	}
-	for _, tool := range resp.Tools {
-		canonicalToolName := mergeServerToolNames(s.Name, tool.GetName())
-
-		// extracting json schema is currently on best-effort basis
-		// if it fails, we log the error and continue with the next tool
-		jsonSchema, _ := json.Marshal(tool.InputSchema)
-
-		t := &model.Tool{
-			ServerID:    s.ID,
-			Name:        tool.GetName(),
-			Description: tool.Description,
-			InputSchema: jsonSchema,
+	for _, raw := range rawTools {
+		t, err := newToolModel(raw)
+		if err != nil {
+			// If a tool definition cannot be parsed, we should not fail the entire server registration.
+			// Instead, continue with the next tool.
+			log.Printf("[ERROR] failed to parse tool definition from MCP server %s: %v", s.Name, err)
+			continue
 		}
+		t.ServerID = s.ID
+		canonicalToolName := mergeServerToolNames(s.Name, t.Name)
+		// the server may have been registered before with a different schema for the tool
+		m.forgetInputSchemas(canonicalToolName)
+
 		if err := m.db.Create(t).Error; err != nil {
 			// If registration of a tool fails, we should not fail the entire server registration.
 			// Instead, continue with the next tool.
 			log.Printf("[ERROR] failed to register tool %s in DB: %v", canonicalToolName, err)
-		} else {
-			// Set tool name to include the server name prefix to make it recognizable by MCPJungle
-			// then add the tool to the MCP proxy server
-			tool.Name = canonicalToolName
-			m.mcpProxyServer.AddTool(tool, m.mcpProxyToolCallHandler)
+			continue
 		}
+
+		tool, err := convertToolModelToMcpObject(t)
+		if err != nil {
+			log.Printf("[ERROR] failed to add tool %s to the MCP proxy: %v", canonicalToolName, err)
+			continue
+		}
+		// Set tool name to include the server name prefix to make it recognizable by MCPJungle
+		// then add the tool to the MCP proxy server
+		tool.Name = canonicalToolName
+		m.addProxyTool(tool)
 	}
 	return nil
 }
 
+// listUpstreamTools fetches the definitions of all tools provided by an MCP server, following pagination.
+// The definitions are returned as raw JSON because mcp-go's Tool type drops fields (eg- title) and
+// parts of JSON schemas (eg- additionalProperties) that mcpjungle needs to pass on to its clients.
+func listUpstreamTools(ctx context.Context, c *client.Client) ([]json.RawMessage, error) {
+	var tools []json.RawMessage
+	var cursor mcp.Cursor
+	for page := 1; ; page++ {
+		req := transport.JSONRPCRequest{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			// use string IDs so that they never clash with the numeric IDs used by the client itself
+			ID:     mcp.NewRequestId(fmt.Sprintf("mcpjungle-tools-list-%d", page)),
+			Method: string(mcp.MethodToolsList),
+		}
+		if cursor != "" {
+			req.Params = map[string]any{"cursor": cursor}
+		}
+		resp, err := c.GetTransport().SendRequest(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Error != nil {
+			return nil, fmt.Errorf("tools/list request failed: %s", resp.Error.Message)
+		}
+
+		var result struct {
+			Tools      []json.RawMessage `json:"tools"`
+			NextCursor mcp.Cursor        `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode tools/list response: %w", err)
+		}
+		tools = append(tools, result.Tools...)
+
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = result.NextCursor
+	}
+}
+
+// newToolModel creates a tool model from the raw definition of a tool supplied by its MCP server.
+// The name of the returned tool is NOT in its canonical form and its server ID is not set.
+func newToolModel(raw json.RawMessage) (*model.Tool, error) {
+	var def struct {
+		Name         string          `json:"name"`
+		Title        string          `json:"title"`
+		Description  string          `json:"description"`
+		InputSchema  json.RawMessage `json:"inputSchema"`
+		OutputSchema json.RawMessage `json:"outputSchema"`
+		Annotations  json.RawMessage `json:"annotations"`
+		Meta         json.RawMessage `json:"_meta"`
+	}
+	if err := json.Unmarshal(raw, &def); err != nil {
+		return nil, err
+	}
+	if def.Name == "" {
+		return nil, fmt.Errorf("tool definition %s has no name", raw)
+	}
+
+	nullToEmpty := func(v json.RawMessage) datatypes.JSON {
+		if string(v) == "null" {
+			return nil
+		}
+		return datatypes.JSON(v)
+	}
+	return &model.Tool{
+		Name:         def.Name,
+		Title:        def.Title,
+		Description:  def.Description,
+		InputSchema:  nullToEmpty(def.InputSchema),
+		OutputSchema: nullToEmpty(def.OutputSchema),
+		Annotations:  nullToEmpty(def.Annotations),
+		Meta:         nullToEmpty(def.Meta),
+	}, nil
+}
+
 // deregisterServerTools deletes all tools that belong to an MCP server from the DB.
 // It also removes the tools from the MCP proxy server.
 func (m *MCPService) deregisterServerTools(s *model.McpServer) error {
@@ -293,7 +555,8 @@ func (m *MCPService) deregisterServerTools(s *model.McpServer) error {
 	for i, tool := range tools {
 		toolNames[i] = tool.Name
 	}
-	m.mcpProxyServer.DeleteTools(toolNames...)
+	m.deleteProxyTools(toolNames...)
+	m.forgetInputSchemas(toolNames...)
 
 	return nil
 }
@@ -0,0 +1,265 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm/clause"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// validToolAlias restricts aliases to the characters allowed in tool names.
+var validToolAlias = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// initToolOverrides loads all tool overrides from the database.
+// It must be called before any tools are added to the MCP proxy.
+func (m *MCPService) initToolOverrides() error {
+	records, err := m.listToolOverrideRecords()
+	if err != nil {
+		return err
+	}
+
+	m.overridesMu.Lock()
+	defer m.overridesMu.Unlock()
+	for i := range records {
+		o, err := toolOverrideModelToType(&records[i])
+		if err != nil {
+			return err
+		}
+		m.overrides[o.Tool] = o
+		if o.Alias != "" {
+			m.aliases[o.Alias] = o.Tool
+		}
+	}
+	return nil
+}
+
+func (m *MCPService) listToolOverrideRecords() ([]model.ToolOverride, error) {
+	var records []model.ToolOverride
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tool overrides: %w", err)
+	}
+	return records, nil
+}
+
+func toolOverrideModelToType(r *model.ToolOverride) (*types.ToolOverride, error) {
+	o := &types.ToolOverride{Tool: r.ToolName, Alias: r.Alias, Description: r.Description}
+	if len(r.ParameterDescriptions) > 0 {
+		if err := json.Unmarshal(r.ParameterDescriptions, &o.ParameterDescriptions); err != nil {
+			return nil, fmt.Errorf("failed to decode parameter descriptions of tool %s: %w", r.ToolName, err)
+		}
+	}
+	return o, nil
+}
+
+// ListToolOverrides returns all tool overrides.
+func (m *MCPService) ListToolOverrides() ([]*types.ToolOverride, error) {
+	records, err := m.listToolOverrideRecords()
+	if err != nil {
+		return nil, err
+	}
+	overrides := make([]*types.ToolOverride, len(records))
+	for i := range records {
+		if overrides[i], err = toolOverrideModelToType(&records[i]); err != nil {
+			return nil, err
+		}
+	}
+	return overrides, nil
+}
+
+// SetToolOverride creates or replaces the override of a tool and applies it to the MCP proxy right away.
+func (m *MCPService) SetToolOverride(o *types.ToolOverride) error {
+	if o.Alias == "" && o.Description == "" && len(o.ParameterDescriptions) == 0 {
+		return errors.New("an override must set an alias, a description or parameter descriptions")
+	}
+	if _, err := m.GetTool(o.Tool); err != nil {
+		return fmt.Errorf("tool %s does not exist: %w", o.Tool, err)
+	}
+	if o.Alias != "" {
+		if !validToolAlias.MatchString(o.Alias) {
+			return fmt.Errorf("invalid alias: '%s' must follow the regular expression %s", o.Alias, validToolAlias)
+		}
+		if strings.Contains(o.Alias, serverToolNameSep) {
+			return fmt.Errorf(
+				"invalid alias: '%s' must not contain %s, which is reserved for canonical tool names",
+				o.Alias, serverToolNameSep,
+			)
+		}
+		m.overridesMu.RLock()
+		owner, taken := m.aliases[o.Alias]
+		m.overridesMu.RUnlock()
+		if taken && owner != o.Tool {
+			return fmt.Errorf("alias %s is already used by tool %s", o.Alias, owner)
+		}
+		if _, err := m.GetCompositeTool(o.Alias); err == nil {
+			return fmt.Errorf("alias %s is already used by a composite tool", o.Alias)
+		}
+	}
+
+	params, err := json.Marshal(o.ParameterDescriptions)
+	if err != nil {
+		return fmt.Errorf("failed to encode parameter descriptions: %w", err)
+	}
+	record := &model.ToolOverride{
+		ToolName:              o.Tool,
+		Alias:                 o.Alias,
+		Description:           o.Description,
+		ParameterDescriptions: params,
+	}
+	err = m.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tool_name"}},
+		DoUpdates: clause.AssignmentColumns(
+			[]string{"alias", "description", "parameter_descriptions", "updated_at"},
+		),
+	}).Create(record).Error
+	if err != nil {
+		return fmt.Errorf("failed to save override of tool %s: %w", o.Tool, err)
+	}
+
+	return m.reloadProxyTool(o.Tool, func() {
+		if prev, ok := m.overrides[o.Tool]; ok && prev.Alias != "" {
+			delete(m.aliases, prev.Alias)
+		}
+		m.overrides[o.Tool] = o
+		if o.Alias != "" {
+			m.aliases[o.Alias] = o.Tool
+		}
+	})
+}
+
+// DeleteToolOverride removes the override of a tool, so that the proxy presents it as supplied by its MCP server.
+// It is an idempotent operation. Deleting an override that does not exist does not return an error.
+func (m *MCPService) DeleteToolOverride(toolName string) error {
+	err := m.db.Unscoped().Where("tool_name = ?", toolName).Delete(&model.ToolOverride{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete override of tool %s: %w", toolName, err)
+	}
+	return m.reloadProxyTool(toolName, func() {
+		if prev, ok := m.overrides[toolName]; ok && prev.Alias != "" {
+			delete(m.aliases, prev.Alias)
+		}
+		delete(m.overrides, toolName)
+	})
+}
+
+// reloadProxyTool changes the overrides of a tool using update and, if the tool is currently served by
+// the MCP proxy, replaces it so that clients see the change.
+func (m *MCPService) reloadProxyTool(canonicalToolName string, update func()) error {
+	served := m.mcpProxyServer.GetTool(canonicalToolName) != nil
+	if served {
+		// remove the tool while the previous alias is still known
+		m.deleteProxyTools(canonicalToolName)
+	}
+
+	m.overridesMu.Lock()
+	update()
+	m.overridesMu.Unlock()
+
+	if !served {
+		return nil
+	}
+	t, err := m.GetTool(canonicalToolName)
+	if err != nil {
+		return fmt.Errorf("failed to get tool %s: %w", canonicalToolName, err)
+	}
+	mcpTool, err := convertToolModelToMcpObject(t)
+	if err != nil {
+		return fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", canonicalToolName, err)
+	}
+	m.addProxyTool(mcpTool)
+	return nil
+}
+
+// resolveToolAlias returns the canonical name of the tool that an alias refers to.
+// Names that are not aliases are returned unchanged.
+func (m *MCPService) resolveToolAlias(name string) string {
+	m.overridesMu.RLock()
+	defer m.overridesMu.RUnlock()
+	if canonical, ok := m.aliases[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// applyToolOverrides applies the overrides of the given tools and adds a copy of each aliased tool under its alias.
+// It also returns the canonical name of every returned tool.
+func (m *MCPService) applyToolOverrides(tools []server.ServerTool) ([]server.ServerTool, []string) {
+	m.overridesMu.RLock()
+	defer m.overridesMu.RUnlock()
+
+	result := make([]server.ServerTool, 0, len(tools))
+	canonicalNames := make([]string, 0, len(tools))
+	for _, t := range tools {
+		name := t.Tool.Name
+		if o, ok := m.overrides[name]; ok {
+			if o.Description != "" {
+				t.Tool.Description = o.Description
+			}
+			if err := overrideParameterDescriptions(&t.Tool, o.ParameterDescriptions); err != nil {
+				// a broken schema shouldn't keep the tool from being served, so only the override is skipped
+				log.Printf("[WARN] failed to override parameter descriptions of tool %s: %v", name, err)
+			}
+			if o.Alias != "" {
+				alias := t
+				alias.Tool.Name = o.Alias
+				result = append(result, alias)
+				canonicalNames = append(canonicalNames, name)
+			}
+		}
+		result = append(result, t)
+		canonicalNames = append(canonicalNames, name)
+	}
+	return result, canonicalNames
+}
+
+// withToolAliases returns the given canonical tool names along with their aliases.
+// It also returns the canonical name of every returned name.
+func (m *MCPService) withToolAliases(names []string) ([]string, []string) {
+	m.overridesMu.RLock()
+	defer m.overridesMu.RUnlock()
+
+	result := make([]string, 0, len(names))
+	canonicalNames := make([]string, 0, len(names))
+	for _, n := range names {
+		if o, ok := m.overrides[n]; ok && o.Alias != "" {
+			result = append(result, o.Alias)
+			canonicalNames = append(canonicalNames, n)
+		}
+		result = append(result, n)
+		canonicalNames = append(canonicalNames, n)
+	}
+	return result, canonicalNames
+}
+
+// overrideParameterDescriptions replaces the descriptions of input parameters in the tool's input schema.
+// Parameters that are not in the schema are ignored.
+func overrideParameterDescriptions(tool *mcp.Tool, descriptions map[string]string) error {
+	if len(descriptions) == 0 || len(tool.RawInputSchema) == 0 {
+		return nil
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(tool.RawInputSchema, &schema); err != nil {
+		return err
+	}
+	props, ok := schema["properties"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	for param, desc := range descriptions {
+		if p, ok := props[param].(map[string]any); ok {
+			p["description"] = desc
+		}
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	tool.RawInputSchema = raw
+	return nil
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"encoding/json"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"testing"
+)
+
+func TestApplyToolOverrides(t *testing.T) {
+	m := &MCPService{
+		mcpProxyServer: server.NewMCPServer("test", "0.0.1", server.WithToolCapabilities(true)),
+		overrides: map[string]*types.ToolOverride{
+			"github__search_issues_v2": {
+				Tool:                  "github__search_issues_v2",
+				Alias:                 "search_issues",
+				Description:           "Search GitHub issues and pull requests",
+				ParameterDescriptions: map[string]string{"q": "GitHub search syntax, eg- is:open label:bug"},
+			},
+		},
+		aliases:    map[string]string{"search_issues": "github__search_issues_v2"},
+		groups:     make(map[string]*toolGroupProxy),
+		toolSearch: newToolSearchIndex(),
+	}
+	m.groups["github"] = &toolGroupProxy{
+		server:  server.NewMCPServer("group", "0.0.1", server.WithToolCapabilities(true)),
+		servers: map[string]bool{"github": true},
+	}
+
+	m.addProxyTool(mcp.Tool{
+		Name:           "github__search_issues_v2",
+		Description:    "search",
+		RawInputSchema: json.RawMessage(`{"type":"object","properties":{"q":{"type":"string","description":"query"}}}`),
+	})
+
+	for _, s := range []*server.MCPServer{m.mcpProxyServer, m.groups["github"].server} {
+		for _, name := range []string{"github__search_issues_v2", "search_issues"} {
+			tool := s.GetTool(name)
+			if tool == nil {
+				t.Fatalf("expected tool %s to be served", name)
+			}
+			if tool.Tool.Description != "Search GitHub issues and pull requests" {
+				t.Errorf("expected the description of %s to be overridden, got %q", name, tool.Tool.Description)
+			}
+			var schema struct {
+				Properties map[string]struct {
+					Description string `json:"description"`
+				} `json:"properties"`
+			}
+			if err := json.Unmarshal(tool.Tool.RawInputSchema, &schema); err != nil {
+				t.Fatalf("invalid input schema: %v", err)
+			}
+			if got := schema.Properties["q"].Description; got != "GitHub search syntax, eg- is:open label:bug" {
+				t.Errorf("expected the parameter description of %s to be overridden, got %q", name, got)
+			}
+		}
+	}
+
+	if got := m.resolveToolAlias("search_issues"); got != "github__search_issues_v2" {
+		t.Errorf("expected the alias to resolve to the canonical name, got %s", got)
+	}
+
+	m.deleteProxyTools("github__search_issues_v2")
+	for _, s := range []*server.MCPServer{m.mcpProxyServer, m.groups["github"].server} {
+		if len(s.ListTools()) != 0 {
+			t.Errorf("expected the tool and its alias to be removed, got %v", s.ListTools())
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// names of the meta-tools served by the tool search server
+const (
+	searchToolsToolName  = "search_tools"
+	describeToolToolName = "describe_tool"
+	callToolToolName     = "call_tool"
+)
+
+const (
+	toolSearchDefaultLimit = 10
+	toolSearchMaxLimit     = 50
+)
+
+// weights of a query term found in the different parts of a tool
+const (
+	toolSearchNameWeight        = 3
+	toolSearchDescriptionWeight = 2
+	toolSearchSchemaWeight      = 1
+)
+
+// toolSearchIndex indexes the tools served by the MCP proxy by keyword.
+// Tools are indexed under the name they are served with, so aliased tools are found by their alias.
+type toolSearchIndex struct {
+	mu      sync.RWMutex
+	entries map[string]*toolSearchEntry
+}
+
+// toolSearchEntry holds the lowercase text of a tool that search terms are matched against.
+type toolSearchEntry struct {
+	tool        mcp.Tool
+	name        string
+	description string
+	schema      string
+}
+
+// toolSearchMatch is a tool found by a search along with its relevance score.
+type toolSearchMatch struct {
+	tool  mcp.Tool
+	score int
+}
+
+func newToolSearchIndex() *toolSearchIndex {
+	return &toolSearchIndex{entries: make(map[string]*toolSearchEntry)}
+}
+
+// add indexes tools, replacing the entries of tools with the same names.
+func (x *toolSearchIndex) add(tools ...mcp.Tool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, t := range tools {
+		x.entries[t.Name] = &toolSearchEntry{
+			tool:        t,
+			name:        strings.ToLower(t.Name),
+			description: strings.ToLower(t.Description),
+			schema:      strings.ToLower(schemaSearchText(t)),
+		}
+	}
+}
+
+// delete removes tools from the index.
+func (x *toolSearchIndex) delete(names ...string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, n := range names {
+		delete(x.entries, n)
+	}
+}
+
+// get returns the indexed tool with the given name.
+func (x *toolSearchIndex) get(name string) (mcp.Tool, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	e, ok := x.entries[name]
+	if !ok {
+		return mcp.Tool{}, false
+	}
+	return e.tool, true
+}
+
+// search returns the tools that match at least one term of the query, most relevant first.
+// A term matches a tool if it appears in its name, description or the names and descriptions
+// of its parameters. visible decides which tools may be returned at all.
+func (x *toolSearchIndex) search(query string, limit int, visible func(name string) bool) []toolSearchMatch {
+	terms := strings.Fields(strings.ToLower(query))
+
+	x.mu.RLock()
+	var matches []toolSearchMatch
+	for _, e := range x.entries {
+		score := 0
+		for _, term := range terms {
+			if strings.Contains(e.name, term) {
+				score += toolSearchNameWeight
+			}
+			if strings.Contains(e.description, term) {
+				score += toolSearchDescriptionWeight
+			}
+			if strings.Contains(e.schema, term) {
+				score += toolSearchSchemaWeight
+			}
+		}
+		if score > 0 && visible(e.tool.Name) {
+			matches = append(matches, toolSearchMatch{tool: e.tool, score: score})
+		}
+	}
+	x.mu.RUnlock()
+
+	slices.SortFunc(matches, func(a, b toolSearchMatch) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.tool.Name, b.tool.Name)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// schemaSearchText returns the names and descriptions of the parameters of a tool, separated by newlines.
+func schemaSearchText(t mcp.Tool) string {
+	raw := t.RawInputSchema
+	if len(raw) == 0 {
+		var err error
+		if raw, err = json.Marshal(t.InputSchema); err != nil {
+			return ""
+		}
+	}
+	var schema any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	collectSchemaText(schema, &sb)
+	return sb.String()
+}
+
+func collectSchemaText(v any, sb *strings.Builder) {
+	switch v := v.(type) {
+	case map[string]any:
+		if desc, ok := v["description"].(string); ok {
+			sb.WriteString(desc + "\n")
+		}
+		if props, ok := v["properties"].(map[string]any); ok {
+			for name, p := range props {
+				sb.WriteString(name + "\n")
+				collectSchemaText(p, sb)
+			}
+		}
+		if items, ok := v["items"]; ok {
+			collectSchemaText(items, sb)
+		}
+	case []any:
+		for _, e := range v {
+			collectSchemaText(e, sb)
+		}
+	}
+}
+
+// newToolSearchServer creates the MCP server that only exposes the meta-tools used to search
+// the tools of the MCP proxy, describe them and call them by name.
+func (m *MCPService) newToolSearchServer() *server.MCPServer {
+	hooks := &server.Hooks{}
+	s := server.NewMCPServer(
+		"MCPJungle Proxy MCP Server",
+		"0.0.1",
+		server.WithToolCapabilities(false),
+		server.WithHooks(hooks),
+	)
+	m.registerProxyHooks(s, hooks)
+
+	s.AddTool(
+		mcp.NewTool(
+			searchToolsToolName,
+			mcp.WithDescription(
+				"Search the available tools by keyword. Terms are matched against the name, description and"+
+					" parameters of each tool. Use describe_tool to get the input schema of a tool found,"+
+					" then call it with call_tool.",
+			),
+			mcp.WithString("query", mcp.Required(), mcp.Description("Keywords describing the task, eg- 'create github issue'")),
+			mcp.WithNumber(
+				"limit",
+				mcp.Description(fmt.Sprintf("Maximum number of tools to return (default %d)", toolSearchDefaultLimit)),
+				mcp.Min(1),
+				mcp.Max(toolSearchMaxLimit),
+			),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		m.searchToolsHandler,
+	)
+	s.AddTool(
+		mcp.NewTool(
+			describeToolToolName,
+			mcp.WithDescription("Get the full definition of a tool, including its input schema."),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Name of the tool, as returned by search_tools")),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		m.describeToolHandler,
+	)
+	s.AddTool(
+		mcp.NewTool(
+			callToolToolName,
+			mcp.WithDescription("Call a tool by name. The arguments must match the input schema returned by describe_tool."),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Name of the tool, as returned by search_tools")),
+			mcp.WithObject("arguments", mcp.Description("Arguments of the tool call")),
+		),
+		m.callToolHandler,
+	)
+	return s
+}
+
+// ToolSearchServer returns the MCP server that exposes the tools of the MCP proxy through
+// the search_tools, describe_tool and call_tool meta-tools, for registries too large to list all tools.
+func (m *MCPService) ToolSearchServer() *server.MCPServer {
+	return m.toolSearchServer
+}
+
+func (m *MCPService) searchToolsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	limit := request.GetInt("limit", toolSearchDefaultLimit)
+	limit = max(1, min(limit, toolSearchMaxLimit))
+
+	matches := m.toolSearch.search(query, limit, func(name string) bool { return m.canSeeTool(ctx, name) })
+	tools := make([]map[string]any, 0, len(matches))
+	var sb strings.Builder
+	for _, match := range matches {
+		tools = append(tools, map[string]any{"name": match.tool.Name, "description": match.tool.Description})
+		if match.tool.Description == "" {
+			fmt.Fprintf(&sb, "- %s\n", match.tool.Name)
+		} else {
+			fmt.Fprintf(&sb, "- %s: %s\n", match.tool.Name, match.tool.Description)
+		}
+	}
+	if len(matches) == 0 {
+		sb.WriteString("No tools found, try other keywords.")
+	}
+	return mcp.NewToolResultStructured(map[string]any{"tools": tools}, sb.String()), nil
+}
+
+func (m *MCPService) describeToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	tool, ok := m.toolSearch.get(name)
+	if !ok || !m.canSeeTool(ctx, name) {
+		return mcp.NewToolResultError(fmt.Sprintf("tool %s not found, use search_tools to find tools", name)), nil
+	}
+	// parameters pinned by presets are hidden just like when the tool is listed
+	tool = m.hidePresetParameters(ctx, []mcp.Tool{tool})[0]
+
+	data, err := json.Marshal(tool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tool %s: %w", name, err)
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to encode tool %s: %w", name, err)
+	}
+	return mcp.NewToolResultStructured(obj, string(data)), nil
+}
+
+// callToolHandler calls a tool of the MCP proxy as if the client had called it directly,
+// so that access control, presets, validation and rate limits all apply.
+func (m *MCPService) callToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	t := m.mcpProxyServer.GetTool(name)
+	if t == nil || !m.canSeeTool(ctx, name) {
+		return mcp.NewToolResultError(fmt.Sprintf("tool %s not found, use search_tools to find tools", name)), nil
+	}
+	arguments, _ := request.GetArguments()["arguments"].(map[string]any)
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+
+	// the header and meta are kept so that the call can be cancelled and report progress
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+	return t.Handler(ctx, request)
+}
+
+// canSeeTool returns true if the MCP client making the request has access to the server of a tool.
+// Composite tools are visible to all clients, access to the tools they call is checked when they run.
+func (m *MCPService) canSeeTool(ctx context.Context, name string) bool {
+	if mode, _ := ctx.Value("mode").(model.ServerMode); mode != model.ModeProd {
+		return true
+	}
+	serverName, _, ok := splitServerToolName(m.resolveToolAlias(name))
+	if !ok {
+		return true
+	}
+	c, ok := ctx.Value("client").(*model.McpClient)
+	return ok && c != nil && m.clientHasServerAccess(c, serverName)
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"slices"
+	"testing"
+)
+
+func TestToolSearchIndex(t *testing.T) {
+	x := newToolSearchIndex()
+	x.add(
+		mcp.NewTool(
+			"github__create_issue",
+			mcp.WithDescription("Open a new issue in a repository"),
+			mcp.WithString("repo", mcp.Description("owner/name of the repository")),
+		),
+		mcp.NewTool("github__search_code", mcp.WithDescription("Search code across repositories")),
+		mcp.NewTool("jira__create_ticket", mcp.WithDescription("Create a Jira issue")),
+		mcp.NewTool(
+			"time__now",
+			mcp.WithDescription("Current time"),
+			mcp.WithString("timezone", mcp.Description("IANA zone, eg- Europe/Berlin")),
+		),
+	)
+	all := func(string) bool { return true }
+
+	tests := []struct {
+		name    string
+		query   string
+		limit   int
+		visible func(string) bool
+		want    []string
+	}{
+		{
+			name:  "ranked by relevance",
+			query: "create issue",
+			limit: 10,
+			want:  []string{"github__create_issue", "jira__create_ticket"},
+		},
+		{
+			name:  "matches parameters",
+			query: "berlin",
+			limit: 10,
+			want:  []string{"time__now"},
+		},
+		{
+			name:  "case insensitive, ties sorted by name",
+			query: "REPOSITOR",
+			limit: 10,
+			want:  []string{"github__create_issue", "github__search_code"},
+		},
+		{
+			name:  "limited",
+			query: "github",
+			limit: 1,
+			want:  []string{"github__create_issue"},
+		},
+		{
+			name:    "hidden tools are skipped",
+			query:   "issue",
+			limit:   10,
+			visible: func(name string) bool { return name != "github__create_issue" },
+			want:    []string{"jira__create_ticket"},
+		},
+		{name: "no match", query: "weather", limit: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visible := tt.visible
+			if visible == nil {
+				visible = all
+			}
+			var got []string
+			for _, match := range x.search(tt.query, tt.limit, visible) {
+				got = append(got, match.tool.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	x.delete("jira__create_ticket")
+	if _, ok := x.get("jira__create_ticket"); ok {
+		t.Error("expected deleted tool to be removed from the index")
+	}
+	if got := x.search("ticket", 10, all); len(got) != 0 {
+		t.Errorf("expected no matches after deletion, got %d", len(got))
+	}
+}
@@ -0,0 +1,348 @@
+package mcp
+
+import (
+	"encoding/json"
+	"github.com/glebarez/sqlite"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestDB opens a SQLite database in a temporary directory and creates the tables of the given models.
+func newTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func TestToolDefinitionRoundTrip(t *testing.T) {
+	raw := json.RawMessage(`{
+		"name": "search",
+		"title": "Search documents",
+		"description": "Full text search",
+		"inputSchema": {
+			"type": "object",
+			"properties": {"query": {"type": "string"}},
+			"required": ["query"],
+			"additionalProperties": false
+		},
+		"outputSchema": {"type": "object", "properties": {"hits": {"type": "integer"}}},
+		"annotations": {"readOnlyHint": true},
+		"_meta": {"vendor/version": "1.2"}
+	}`)
+
+	tm, err := newToolModel(raw)
+	if err != nil {
+		t.Fatalf("newToolModel() error = %v", err)
+	}
+	if tm.Name != "search" || tm.Title != "Search documents" {
+		t.Errorf("unexpected name or title: %q, %q", tm.Name, tm.Title)
+	}
+
+	tool, err := convertToolModelToMcpObject(tm)
+	if err != nil {
+		t.Fatalf("convertToolModelToMcpObject() error = %v", err)
+	}
+	b, err := json.Marshal(tool)
+	if err != nil {
+		t.Fatalf("failed to marshal tool: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("failed to unmarshal tool: %v", err)
+	}
+
+	inputSchema := got["inputSchema"].(map[string]any)
+	if inputSchema["additionalProperties"] != false {
+		t.Errorf("expected additionalProperties to be preserved in the input schema, got %v", inputSchema)
+	}
+	if _, ok := got["outputSchema"]; !ok {
+		t.Errorf("expected output schema to be preserved, got %s", b)
+	}
+	annotations := got["annotations"].(map[string]any)
+	if annotations["readOnlyHint"] != true || annotations["title"] != "Search documents" {
+		t.Errorf("expected annotations to contain the hints and title, got %v", annotations)
+	}
+	meta, ok := got["_meta"].(map[string]any)
+	if !ok || meta["vendor/version"] != "1.2" {
+		t.Errorf("expected _meta to be preserved, got %s", b)
+	}
+}
+
+func TestNewToolModelWithoutName(t *testing.T) {
+	if _, err := newToolModel(json.RawMessage(`{"description": "no name"}`)); err == nil {
+		t.Errorf("expected an error for a tool definition without a name")
+	}
+}
+
+func TestConvertContentToMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  mcp.Content
+		wantType string
+		wantKey  string
+	}{
+		{"text", mcp.NewTextContent("hello"), "text", "text"},
+		{"image", mcp.NewImageContent("aGVsbG8=", "image/png"), "image", "data"},
+		{
+			"embedded resource",
+			mcp.NewEmbeddedResource(mcp.TextResourceContents{URI: "file:///a.txt", Text: "hello"}),
+			"resource",
+			"resource",
+		},
+		{
+			"resource link",
+			mcp.NewResourceLink("file:///a.txt", "a.txt", "a text file", "text/plain"),
+			"resource_link",
+			"uri",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := convertContentToMap(tt.content)
+			if err != nil {
+				t.Fatalf("convertContentToMap() error = %v", err)
+			}
+			if c["type"] != tt.wantType {
+				t.Errorf("expected type %q, got %v", tt.wantType, c["type"])
+			}
+			if _, ok := c[tt.wantKey]; !ok {
+				t.Errorf("expected content to have field %q, got %v", tt.wantKey, c)
+			}
+		})
+	}
+}
+
+func TestSearchTools(t *testing.T) {
+	db := newTestDB(t, &model.McpServer{}, &model.Tool{})
+	servers := map[string][]model.Tool{
+		"github": {
+			{Name: "create_issue", Description: "Open a new issue", Enabled: true},
+			{
+				Name:        "search_code",
+				Description: "Search code, issues are not included",
+				Enabled:     true,
+				Labels:      []byte(`{"tracker":"code"}`),
+			},
+			{Name: "list_commits", Description: "List the commits of a branch", Enabled: false},
+		},
+		"jira": {
+			{Name: "create_ticket", Title: "Create issue", Description: "Create a ticket", Enabled: true},
+		},
+	}
+	serverLabels := map[string][]byte{"jira": []byte(`{"tracker":"issues"}`)}
+	for name, tools := range servers {
+		s := &model.McpServer{
+			Name:      name,
+			Transport: types.TransportStreamableHTTP,
+			Config:    []byte("{}"),
+			Labels:    serverLabels[name],
+		}
+		if err := db.Create(s).Error; err != nil {
+			t.Fatalf("failed to create server: %v", err)
+		}
+		for i := range tools {
+			tools[i].ServerID = s.ID
+			enabled := tools[i].Enabled
+			if err := db.Create(&tools[i]).Error; err != nil {
+				t.Fatalf("failed to create tool: %v", err)
+			}
+			// gorm replaces zero values by the column default on create, so disabled tools are updated explicitly
+			if !enabled {
+				if err := db.Model(&tools[i]).Update("enabled", false).Error; err != nil {
+					t.Fatalf("failed to disable tool: %v", err)
+				}
+			}
+		}
+	}
+	m := &MCPService{db: db}
+	disabled := false
+
+	tests := []struct {
+		name   string
+		filter types.ToolFilter
+		want   []string
+	}{
+		{
+			name:   "no filter",
+			filter: types.ToolFilter{},
+			want:   []string{"github__create_issue", "github__list_commits", "github__search_code", "jira__create_ticket"},
+		},
+		{
+			name:   "ranked by relevance",
+			filter: types.ToolFilter{Query: "issue"},
+			want:   []string{"github__create_issue", "jira__create_ticket", "github__search_code"},
+		},
+		{
+			name:   "underscores are not wildcards",
+			filter: types.ToolFilter{Query: "b_anch"},
+		},
+		{
+			name:   "server name",
+			filter: types.ToolFilter{Query: "JIRA"},
+			want:   []string{"jira__create_ticket"},
+		},
+		{
+			name:   "by server",
+			filter: types.ToolFilter{Query: "create", Server: "github"},
+			want:   []string{"github__create_issue"},
+		},
+		{
+			name:   "disabled only",
+			filter: types.ToolFilter{Enabled: &disabled},
+			want:   []string{"github__list_commits"},
+		},
+		{
+			name:   "by tag, including the labels of the server",
+			filter: types.ToolFilter{Tag: "tracker"},
+			want:   []string{"github__search_code", "jira__create_ticket"},
+		},
+		{
+			name:   "by tag and query",
+			filter: types.ToolFilter{Query: "issue", Tag: "tracker"},
+			want:   []string{"jira__create_ticket", "github__search_code"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools, err := m.SearchTools(tt.filter)
+			if err != nil {
+				t.Fatalf("failed to search tools: %v", err)
+			}
+			var got []string
+			for _, tool := range tools {
+				got = append(got, tool.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestListToolsPage(t *testing.T) {
+	db := newTestDB(t, &model.McpServer{}, &model.Tool{})
+	// tools are created in an order that differs from their names, with the same creation time for some
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tools := []struct {
+		server, name, description string
+		createdAt                 time.Time
+	}{
+		{"jira", "create_ticket", "Create an issue", created},
+		{"github", "search_code", "Search code", created},
+		{"github", "create_issue", "Open an issue", created.Add(time.Minute)},
+		{"github", "list_issues", "List the issues", created.Add(2 * time.Minute)},
+		{"jira", "assign", "Assign an issue", created.Add(2 * time.Minute)},
+	}
+	serverIDs := make(map[string]uint)
+	for _, tool := range tools {
+		if _, ok := serverIDs[tool.server]; !ok {
+			s := &model.McpServer{Name: tool.server, Transport: types.TransportStreamableHTTP, Config: []byte("{}")}
+			if err := s.SetLabels(map[string]string{"app": tool.server}); err != nil {
+				t.Fatalf("failed to set labels: %v", err)
+			}
+			if err := db.Create(s).Error; err != nil {
+				t.Fatalf("failed to create server: %v", err)
+			}
+			serverIDs[tool.server] = s.ID
+		}
+		record := &model.Tool{Name: tool.name, Description: tool.description, ServerID: serverIDs[tool.server]}
+		record.CreatedAt = tool.createdAt
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("failed to create tool: %v", err)
+		}
+	}
+	m := &MCPService{db: db}
+
+	tests := []struct {
+		name   string
+		filter types.ToolFilter
+		sort   string
+		want   []string
+	}{
+		{
+			name: "by name",
+			want: []string{
+				"github__create_issue", "github__list_issues", "github__search_code", "jira__assign", "jira__create_ticket",
+			},
+		},
+		{
+			name: "by name descending",
+			sort: "-name",
+			want: []string{
+				"jira__create_ticket", "jira__assign", "github__search_code", "github__list_issues", "github__create_issue",
+			},
+		},
+		{
+			name: "by creation time",
+			sort: "created_at",
+			want: []string{
+				"jira__create_ticket", "github__search_code", "github__create_issue", "github__list_issues", "jira__assign",
+			},
+		},
+		{
+			// the tools that do not match are spread across pages
+			name:   "by labels",
+			filter: types.ToolFilter{Labels: "app=jira"},
+			sort:   "created_at",
+			want:   []string{"jira__create_ticket", "jira__assign"},
+		},
+		{
+			name:   "by relevance",
+			filter: types.ToolFilter{Query: "issue"},
+			want:   []string{"github__create_issue", "github__list_issues", "jira__assign", "jira__create_ticket"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := types.ListOptions{Sort: tt.sort, Limit: 2}
+			var got []string
+			for pages := 0; ; pages++ {
+				if pages > len(tt.want) {
+					t.Fatalf("too many pages, got %v so far", got)
+				}
+				page, next, err := m.ListToolsPage(tt.filter, opts)
+				if err != nil {
+					t.Fatalf("failed to list tools: %v", err)
+				}
+				if len(page) > opts.Limit {
+					t.Fatalf("expected at most %d tools per page, got %d", opts.Limit, len(page))
+				}
+				for _, tool := range page {
+					got = append(got, tool.Name)
+				}
+				if next == "" {
+					break
+				}
+				opts.Cursor = next
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// a cursor only makes sense for the sort it was created for
+	_, next, err := m.ListToolsPage(types.ToolFilter{}, types.ListOptions{Limit: 1})
+	if err != nil {
+		t.Fatalf("failed to list tools: %v", err)
+	}
+	if _, _, err := m.ListToolsPage(types.ToolFilter{}, types.ListOptions{Sort: "-name", Cursor: next}); err == nil {
+		t.Errorf("expected an error when using a cursor with another sort")
+	}
+	if _, _, err := m.ListToolsPage(types.ToolFilter{}, types.ListOptions{Sort: "description"}); err == nil {
+		t.Errorf("expected an error when sorting by an unknown field")
+	}
+}
@@ -10,20 +10,26 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"golang.org/x/oauth2"
 	"io"
 	"log"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
 	"regexp"
 	"strings"
 	"syscall"
 	"time"
 )
 
-// serverInitRequestTimeout is the timeout (in seconds) for the initialization request to the MCP server
+// serverInitRequestTimeout is the default timeout (in seconds) for the initialization request to the MCP server
 const serverInitRequestTimeout = 10
 
+// serverToolCallTimeout is the default timeout (in seconds) for a tool call to the MCP server
+const serverToolCallTimeout = 300
+
 // serverToolNameSep is the separator used to combine server name and tool name.
 // This combination produces the canonical name that uniquely identifies a tool across MCPJungle.
 const serverToolNameSep = "__"
@@ -88,6 +94,23 @@ func isLoopbackURL(rawURL string) bool {
 	return false
 }
 
+// initTimeout returns the timeout for the initialization request to the MCP server as per its policy.
+func initTimeout(s *model.McpServer) time.Duration {
+	p, err := s.GetPolicy()
+	if err != nil || p.InitTimeoutSec == 0 {
+		return serverInitRequestTimeout * time.Second
+	}
+	return time.Duration(p.InitTimeoutSec) * time.Second
+}
+
+// callTimeout returns the timeout for a tool call to the MCP server as per its policy.
+func callTimeout(p *types.ServerPolicy) time.Duration {
+	if p.CallTimeoutSec == 0 {
+		return serverToolCallTimeout * time.Second
+	}
+	return time.Duration(p.CallTimeoutSec) * time.Second
+}
+
 // convertToolModelToMcpObject converts a tool model from the database to a mcp.Tool object
 func convertToolModelToMcpObject(t *model.Tool) (mcp.Tool, error) {
 	mcpTool := mcp.Tool{
@@ -95,40 +118,113 @@ func convertToolModelToMcpObject(t *model.Tool) (mcp.Tool, error) {
 		Description: t.Description,
 	}
 
-	var inputSchema mcp.ToolInputSchema
-	if err := json.Unmarshal(t.InputSchema, &inputSchema); err != nil {
-		return mcp.Tool{}, fmt.Errorf(
-			"failed to unmarshal input schema %s for tool %s: %w", t.InputSchema, t.Name, err,
-		)
+	// the schemas are passed on as-is so that no part of them is lost in translation
+	if len(t.InputSchema) > 0 {
+		if !json.Valid(t.InputSchema) {
+			return mcp.Tool{}, fmt.Errorf("invalid input schema %s for tool %s", t.InputSchema, t.Name)
+		}
+		mcpTool.RawInputSchema = json.RawMessage(t.InputSchema)
+	} else {
+		mcpTool.InputSchema = mcp.ToolInputSchema{Type: "object"}
+	}
+	if len(t.OutputSchema) > 0 {
+		if !json.Valid(t.OutputSchema) {
+			return mcp.Tool{}, fmt.Errorf("invalid output schema %s for tool %s", t.OutputSchema, t.Name)
+		}
+		mcpTool.RawOutputSchema = json.RawMessage(t.OutputSchema)
+	}
+
+	if len(t.Annotations) > 0 {
+		if err := json.Unmarshal(t.Annotations, &mcpTool.Annotations); err != nil {
+			return mcp.Tool{}, fmt.Errorf(
+				"failed to unmarshal annotations %s for tool %s: %w", t.Annotations, t.Name, err,
+			)
+		}
+	}
+	if mcpTool.Annotations.Title == "" {
+		// mcp-go has no top-level title for tools, so the title is passed on as an annotation
+		mcpTool.Annotations.Title = t.Title
+	}
+
+	if len(t.Meta) > 0 {
+		var meta map[string]any
+		if err := json.Unmarshal(t.Meta, &meta); err != nil {
+			return mcp.Tool{}, fmt.Errorf("failed to unmarshal _meta %s for tool %s: %w", t.Meta, t.Name, err)
+		}
+		mcpTool.Meta = mcp.NewMetaFromMap(meta)
 	}
-	mcpTool.InputSchema = inputSchema
 
-	// TODO: Add other attributes to the tool, such as annotations
 	// NOTE: if more fields are added to the tool in DB, they should be set here as well
 
 	return mcpTool, nil
 }
 
+// upstreamHeaders returns the headers to send in all requests to an HTTP-based MCP server.
+// If a bearer token is provided, it takes precedence over any custom Authorization header.
+func upstreamHeaders(custom map[string]string, bearerToken string) map[string]string {
+	headers := make(map[string]string, len(custom)+1)
+	for k, v := range custom {
+		if bearerToken != "" && http.CanonicalHeaderKey(k) == "Authorization" {
+			continue
+		}
+		headers[k] = v
+	}
+	if bearerToken != "" {
+		headers["Authorization"] = "Bearer " + bearerToken
+	}
+	return headers
+}
+
+// newUpstreamHTTPClient creates an HTTP client that connects to an MCP server with the given TLS configuration.
+// If tokenSource is not nil, the client attaches its OAuth access tokens to all requests.
+func newUpstreamHTTPClient(conf *types.UpstreamTLSConfig, tokenSource oauth2.TokenSource) (*http.Client, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if conf != nil {
+		tlsConfig, err := conf.ClientTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls configuration: %w", err)
+		}
+		t.TLSClientConfig = tlsConfig
+	}
+	if tokenSource == nil {
+		return &http.Client{Transport: t}, nil
+	}
+	return &http.Client{Transport: &oauth2.Transport{Source: tokenSource, Base: t}}, nil
+}
+
 // createHTTPMcpServerConn creates a new connection with a streamable http MCP server and returns the client.
-func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+// If tokenSource is not nil, its OAuth access tokens are attached to all requests.
+// clientOpts configure the client, eg- to handle requests made by the server.
+func createHTTPMcpServerConn(
+	ctx context.Context, s *model.McpServer, tokenSource oauth2.TokenSource, clientOpts ...client.ClientOption,
+) (*client.Client, error) {
 	conf, err := s.GetStreamableHTTPConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get streamable HTTP config for MCP server %s: %w", s.Name, err)
 	}
 
 	var opts []transport.StreamableHTTPCOption
-	if conf.BearerToken != "" {
-		// If bearer token is provided, set the Authorization header
-		o := transport.WithHTTPHeaders(map[string]string{
-			"Authorization": "Bearer " + conf.BearerToken,
-		})
-		opts = append(opts, o)
+	if headers := upstreamHeaders(conf.Headers, conf.BearerToken); len(headers) > 0 {
+		opts = append(opts, transport.WithHTTPHeaders(headers))
+	}
+	if conf.TLS != nil || tokenSource != nil {
+		httpClient, err := newUpstreamHTTPClient(conf.TLS, tokenSource)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, transport.WithHTTPBasicClient(httpClient))
 	}
 
-	c, err := client.NewStreamableHttpClient(conf.URL, opts...)
+	t, err := transport.NewStreamableHTTP(conf.URL, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create streamable HTTP client for MCP server: %w", err)
 	}
+	c := client.NewClient(t, clientOpts...)
+	// starting the client wires up the handlers of notifications & requests sent by the server
+	if err := c.Start(ctx); err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("failed to start streamable HTTP client for MCP server: %w", err)
+	}
 
 	initRequest := mcp.InitializeRequest{}
 	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
@@ -138,14 +234,61 @@ func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer) (*client.C
 	}
 	initRequest.Params.Capabilities = mcp.ClientCapabilities{}
 
-	initCtx, cancel := context.WithTimeout(ctx, serverInitRequestTimeout*time.Second)
+	timeout := initTimeout(s)
+	initCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	_, err = c.Initialize(initCtx, initRequest)
 	if err != nil {
+		// close the session (if the server opened one) so that it doesn't leak
+		_ = c.Close()
 		if errors.Is(err, context.DeadlineExceeded) {
-			return nil, fmt.Errorf("initialization request to MCP server timed out after %d seconds", serverInitRequestTimeout)
+			return nil, fmt.Errorf("initialization request to MCP server timed out after %s", timeout)
+		}
+		if errors.Is(err, syscall.ECONNREFUSED) && isLoopbackURL(conf.URL) {
+			return nil, fmt.Errorf(
+				"connection to the MCP server %s was refused. "+
+					"If mcpjungle is running inside Docker, use 'host.docker.internal' as your MCP server's hostname",
+				conf.URL,
+			)
+		}
+		return nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
+	}
+
+	return c, nil
+}
+
+// createSSEMcpServerConn creates a new connection with an SSE MCP server and returns the client.
+// If tokenSource is not nil, its OAuth access tokens are attached to all requests.
+// clientOpts configure the client, eg- to handle requests made by the server.
+func createSSEMcpServerConn(
+	ctx context.Context, s *model.McpServer, tokenSource oauth2.TokenSource, clientOpts ...client.ClientOption,
+) (*client.Client, error) {
+	conf, err := s.GetSSEConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sse config for MCP server %s: %w", s.Name, err)
+	}
+
+	var opts []transport.ClientOption
+	if headers := upstreamHeaders(conf.Headers, conf.BearerToken); len(headers) > 0 {
+		opts = append(opts, transport.WithHeaders(headers))
+	}
+	if conf.TLS != nil || tokenSource != nil {
+		httpClient, err := newUpstreamHTTPClient(conf.TLS, tokenSource)
+		if err != nil {
+			return nil, err
 		}
+		opts = append(opts, transport.WithHTTPClient(httpClient))
+	}
+
+	t, err := transport.NewSSE(conf.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sse client for MCP server: %w", err)
+	}
+	c := client.NewClient(t, clientOpts...)
+
+	// the event stream lives as long as ctx, so it must not be bound to the initialization timeout
+	if err := c.Start(ctx); err != nil {
 		if errors.Is(err, syscall.ECONNREFUSED) && isLoopbackURL(conf.URL) {
 			return nil, fmt.Errorf(
 				"connection to the MCP server %s was refused. "+
@@ -153,6 +296,26 @@ func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer) (*client.C
 				conf.URL,
 			)
 		}
+		return nil, fmt.Errorf("failed to open event stream with MCP server: %w", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "mcpjungle mcp client for " + conf.URL,
+		Version: "0.1",
+	}
+	initRequest.Params.Capabilities = mcp.ClientCapabilities{}
+
+	timeout := initTimeout(s)
+	initCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if _, err = c.Initialize(initCtx, initRequest); err != nil {
+		_ = c.Close()
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("initialization request to MCP server timed out after %s", timeout)
+		}
 		return nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
 	}
 
@@ -186,7 +349,8 @@ func captureStdioServerStderr(name string, c *client.Client) {
 }
 
 // runStdioServer runs a stdio MCP server and returns the client.
-func runStdioServer(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+// clientOpts configure the client, eg- to handle requests made by the server.
+func runStdioServer(ctx context.Context, s *model.McpServer, clientOpts ...client.ClientOption) (*client.Client, error) {
 	conf, err := s.GetStdioConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stdio config for MCP server %s: %w", s.Name, err)
@@ -200,8 +364,22 @@ func runStdioServer(ctx context.Context, s *model.McpServer) (*client.Client, er
 		}
 	}
 
-	c, err := client.NewStdioMCPClient(conf.Command, envVars, conf.Args...)
-	if err != nil {
+	// keep hold of the server process so that it can be killed if the session cannot be established
+	var cmd *exec.Cmd
+	stdio := transport.NewStdioWithOptions(
+		conf.Command, envVars, conf.Args,
+		transport.WithCommandFunc(func(ctx context.Context, command string, env, args []string) (*exec.Cmd, error) {
+			cmd = exec.CommandContext(ctx, command, args...)
+			cmd.Env = append(os.Environ(), env...)
+			return cmd, nil
+		}),
+	)
+
+	// starting the client spawns the server process and wires up the handlers of notifications & requests
+	// sent by the server. The process must outlive ctx, it is stopped when the client is closed.
+	c := client.NewClient(stdio, clientOpts...)
+	if err := c.Start(context.Background()); err != nil {
+		stopStdioServer(c, cmd)
 		return nil, fmt.Errorf("failed to create stdio client for MCP server: %w", err)
 	}
 
@@ -217,16 +395,18 @@ func runStdioServer(ctx context.Context, s *model.McpServer) (*client.Client, er
 	}
 	initRequest.Params.Capabilities = mcp.ClientCapabilities{}
 
-	initCtx, cancel := context.WithTimeout(ctx, serverInitRequestTimeout*time.Second)
+	timeout := initTimeout(s)
+	initCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	_, err = c.Initialize(initCtx, initRequest)
 	if err != nil {
+		stopStdioServer(c, cmd)
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf(
-				"initialization request to MCP server timed out after %d seconds,"+
+				"initialization request to MCP server timed out after %s,"+
 					" check mcpungle server logs for any errors from this MCP server",
-				serverInitRequestTimeout,
+				timeout,
 			)
 		}
 		return nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
@@ -235,9 +415,28 @@ func runStdioServer(ctx context.Context, s *model.McpServer) (*client.Client, er
 	return c, nil
 }
 
-func newMcpServerSession(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+// stopStdioServer stops the process of a stdio MCP server whose session could not be established.
+// The process is killed because a server that failed to initialize may not exit once its stdin is closed,
+// closing the client then reaps it.
+func stopStdioServer(c *client.Client, cmd *exec.Cmd) {
+	if cmd != nil && cmd.Process != nil {
+		_ = cmd.Process.Kill()
+	}
+	_ = c.Close()
+}
+
+// newMcpServerSession opens a new session with the MCP server.
+// clientOpts configure the client, eg- to handle sampling & elicitation requests made by the server.
+func (m *MCPService) newMcpServerSession(
+	ctx context.Context, s *model.McpServer, clientOpts ...client.ClientOption,
+) (*client.Client, error) {
+	tokenSource, err := m.upstreamTokenSource(s)
+	if err != nil {
+		return nil, err
+	}
+
 	if s.Transport == types.TransportStreamableHTTP {
-		mcpClient, err := createHTTPMcpServerConn(ctx, s)
+		mcpClient, err := createHTTPMcpServerConn(ctx, s, tokenSource, clientOpts...)
 		if err != nil {
 			return nil, fmt.Errorf(
 				"failed to create connection to streamable http MCP server %s: %w", s.Name, err,
@@ -245,12 +444,26 @@ func newMcpServerSession(ctx context.Context, s *model.McpServer) (*client.Clien
 		}
 		return mcpClient, nil
 	}
+	if s.Transport == types.TransportSSE {
+		mcpClient, err := createSSEMcpServerConn(ctx, s, tokenSource, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create connection to sse MCP server %s: %w", s.Name, err)
+		}
+		return mcpClient, nil
+	}
+	if s.Transport == types.TransportOpenAPI {
+		mcpClient, err := m.createOpenAPIMcpServerConn(ctx, s, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serve openapi server %s: %w", s.Name, err)
+		}
+		return mcpClient, nil
+	}
 
 	// A new sub-process is spun up for each call to a STDIO mcp server.
 	// This is especially a problem for the MCP proxy server, which is expected to call tools frequently.
 	// This causes a serious performance hit, but is easy to implement so it is used for now.
 	// TODO: Think of a better solution, ie, re-use connections to stdio MCP servers.
-	mcpClient, err := runStdioServer(ctx, s)
+	mcpClient, err := runStdioServer(ctx, s, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run stdio MCP server %s: %w", s.Name, err)
 	}
@@ -1,8 +1,26 @@
 package mcp
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 )
 
 func TestValidateServerName(t *testing.T) {
@@ -129,3 +147,166 @@ func TestIsLoopbackURL(t *testing.T) {
 }
 
 // todo: add tests for convertToolModelToMcpObject()
+
+func TestUpstreamHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		custom      map[string]string
+		bearerToken string
+		want        map[string]string
+	}{
+		{"no headers", nil, "", map[string]string{}},
+		{"custom only", map[string]string{"X-API-Key": "k"}, "", map[string]string{"X-API-Key": "k"}},
+		{
+			"bearer token is added",
+			map[string]string{"X-Tenant": "t"},
+			"tok",
+			map[string]string{"X-Tenant": "t", "Authorization": "Bearer tok"},
+		},
+		{
+			"bearer token overrides custom authorization",
+			map[string]string{"authorization": "Basic abc"},
+			"tok",
+			map[string]string{"Authorization": "Bearer tok"},
+		},
+		{
+			"custom authorization is kept without bearer token",
+			map[string]string{"Authorization": "Basic abc"},
+			"",
+			map[string]string{"Authorization": "Basic abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := upstreamHeaders(tt.custom, tt.bearerToken)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("upstreamHeaders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUpstreamHTTPClient(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	tests := []struct {
+		name    string
+		conf    *types.UpstreamTLSConfig
+		wantErr bool
+	}{
+		{"system roots reject the test certificate", &types.UpstreamTLSConfig{}, true},
+		{"custom CA", &types.UpstreamTLSConfig{CACert: caCert}, false},
+		{"insecure skip verify", &types.UpstreamTLSConfig{InsecureSkipVerify: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newUpstreamHTTPClient(tt.conf, nil)
+			if err != nil {
+				t.Fatalf("unexpected error creating client: %v", err)
+			}
+			resp, err := c.Get(srv.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := newUpstreamHTTPClient(&types.UpstreamTLSConfig{ClientCert: caCert}, nil); err == nil {
+		t.Errorf("expected an error when the client certificate is supplied without its key")
+	}
+}
+
+func TestCreateSSEMcpServerConn(t *testing.T) {
+	upstream := server.NewMCPServer("upstream", "0.0.1", server.WithToolCapabilities(false))
+	upstream.AddTool(mcp.NewTool("echo"), func(ctx context.Context, r mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	// record the Authorization header of every request made to the event stream & message endpoints
+	var (
+		mu          sync.Mutex
+		authHeaders = make(map[string]string)
+	)
+	var sseServer *server.SSEServer
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authHeaders[r.URL.Path] = r.Header.Get("Authorization")
+		mu.Unlock()
+		sseServer.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+	sseServer = server.NewSSEServer(upstream, server.WithBaseURL(ts.URL))
+
+	config, _ := json.Marshal(model.SSEConfig{URL: ts.URL + "/sse", BearerToken: "secret"})
+	s := &model.McpServer{Name: "upstream", Transport: types.TransportSSE, Config: config}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, err := createSSEMcpServerConn(ctx, s, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to the SSE server: %v", err)
+	}
+	defer c.Close()
+
+	// the session outlives the initialization request
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "echo"
+	result, err := c.CallTool(ctx, request)
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "ok" {
+		t.Errorf("unexpected result: %+v", result.Content)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, path := range []string{"/sse", "/message"} {
+		if got := authHeaders[path]; got != "Bearer secret" {
+			t.Errorf("expected the bearer token to be sent to %s, got %q", path, got)
+		}
+	}
+
+	t.Run("unreachable server", func(t *testing.T) {
+		config, _ := json.Marshal(model.SSEConfig{URL: "http://127.0.0.1:1/sse"})
+		s := &model.McpServer{Name: "down", Transport: types.TransportSSE, Config: config}
+		if _, err := createSSEMcpServerConn(ctx, s, nil); err == nil {
+			t.Errorf("expected an error when the server cannot be reached")
+		}
+	})
+}
+
+func TestRunStdioServerInitFailure(t *testing.T) {
+	// the server never answers the initialization request and ignores its stdin being closed
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	config, _ := json.Marshal(model.StdioConfig{
+		Command: "sh",
+		Args:    []string{"-c", fmt.Sprintf("echo $$ > %s; exec sleep 60", pidFile)},
+	})
+	s := &model.McpServer{Name: "silent", Transport: types.TransportStdio, Config: config}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if _, err := runStdioServer(ctx, s); err == nil {
+		t.Fatal("expected the initialization to fail")
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("failed to read the pid of the server process: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatalf("invalid pid %q: %v", data, err)
+	}
+	if err := syscall.Kill(pid, 0); !errors.Is(err, syscall.ESRCH) {
+		t.Errorf("expected the server process %d to be gone, got %v", pid, err)
+	}
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// ToolArgumentsError is returned when the arguments of a tool call do not match the tool's input schema.
+type ToolArgumentsError struct {
+	Tool   string
+	Errors []types.ToolArgumentError
+}
+
+func (e *ToolArgumentsError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		if fe.Field == "" {
+			msgs[i] = fe.Message
+		} else {
+			msgs[i] = fe.Field + ": " + fe.Message
+		}
+	}
+	return fmt.Sprintf("invalid arguments for tool %s: %s", e.Tool, strings.Join(msgs, "; "))
+}
+
+// toolArgumentsErrorResult reports invalid arguments as a tool error so that the LLM can correct them.
+// It returns false if err is not a *ToolArgumentsError.
+func toolArgumentsErrorResult(err error) (*mcp.CallToolResult, bool) {
+	var argsErr *ToolArgumentsError
+	if !errors.As(err, &argsErr) {
+		return nil, false
+	}
+	result := mcp.NewToolResultError(argsErr.Error())
+	result.Meta = mcp.NewMetaFromMap(map[string]any{"validationErrors": argsErr.Errors})
+	return result, true
+}
+
+// compiledSchema is a compiled input schema along with the JSON schema it was compiled from.
+type compiledSchema struct {
+	raw    []byte
+	schema *jsonschema.Schema
+}
+
+// validateToolArguments checks the arguments of a call to the given tool against the tool's input schema.
+// It returns a *ToolArgumentsError if the arguments are invalid.
+// Validation is skipped if the server's policy disables it or if the tool's schema cannot be compiled,
+// in which case it is left to the upstream MCP server to reject bad arguments.
+func (m *MCPService) validateToolArguments(s *model.McpServer, toolName string, args any) error {
+	policy, err := s.GetPolicy()
+	if err != nil {
+		return fmt.Errorf("failed to get policy of MCP server %s: %w", s.Name, err)
+	}
+	if policy.DisableInputValidation {
+		return nil
+	}
+
+	var tool model.Tool
+	if err := m.db.Where("server_id = ? AND name = ?", s.ID, toolName).First(&tool).Error; err != nil {
+		// an unknown tool is reported by the upstream server
+		return nil
+	}
+	name := mergeServerToolNames(s.Name, toolName)
+
+	schema, err := m.inputSchema(name, tool.InputSchema)
+	if err != nil {
+		log.Printf("[WARN] skipping argument validation for tool %s: %v", name, err)
+		return nil
+	}
+	return validateArguments(name, schema, args)
+}
+
+// validateArguments validates the arguments of a call to the named tool against its compiled input schema.
+// It returns a *ToolArgumentsError if the arguments are invalid.
+func validateArguments(name string, schema *jsonschema.Schema, args any) error {
+	if args == nil {
+		// a call without arguments is equivalent to a call with an empty arguments object
+		args = map[string]any{}
+	}
+	// round-trip the arguments through JSON so that they are in the form expected by the validator
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("failed to serialize arguments for tool %s: %w", name, err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("failed to parse arguments for tool %s: %w", name, err)
+	}
+
+	err = schema.Validate(instance)
+	if err == nil {
+		return nil
+	}
+	var vErr *jsonschema.ValidationError
+	if !errors.As(err, &vErr) {
+		return fmt.Errorf("failed to validate arguments for tool %s: %w", name, err)
+	}
+	return &ToolArgumentsError{Tool: name, Errors: argumentErrors(vErr)}
+}
+
+// inputSchema returns the compiled input schema of a tool, compiling it only if it has not been compiled before.
+// A schema that differs from the one that was compiled is compiled again, so a stale schema is never used.
+func (m *MCPService) inputSchema(name string, inputSchema []byte) (*jsonschema.Schema, error) {
+	m.schemasMu.Lock()
+	c, ok := m.schemas[name]
+	m.schemasMu.Unlock()
+	if ok && bytes.Equal(c.raw, inputSchema) {
+		return c.schema, nil
+	}
+
+	schema, err := compileInputSchema(name, inputSchema)
+	if err != nil {
+		return nil, err
+	}
+	m.schemasMu.Lock()
+	m.schemas[name] = &compiledSchema{raw: bytes.Clone(inputSchema), schema: schema}
+	m.schemasMu.Unlock()
+	return schema, nil
+}
+
+// forgetInputSchemas discards the compiled input schemas of tools, eg- when they are deleted.
+func (m *MCPService) forgetInputSchemas(names ...string) {
+	m.schemasMu.Lock()
+	defer m.schemasMu.Unlock()
+	for _, name := range names {
+		delete(m.schemas, name)
+	}
+}
+
+// compileInputSchema compiles the JSON schema stored for a tool.
+func compileInputSchema(name string, inputSchema []byte) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(inputSchema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input schema: %w", err)
+	}
+	url := "mcpjungle:///tools/" + name + "/input_schema.json"
+
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(url, doc); err != nil {
+		return nil, fmt.Errorf("failed to load input schema: %w", err)
+	}
+	schema, err := c.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile input schema: %w", err)
+	}
+	return schema, nil
+}
+
+// argumentErrors flattens a validation error into a list of errors, one per offending field.
+func argumentErrors(vErr *jsonschema.ValidationError) []types.ToolArgumentError {
+	var errs []types.ToolArgumentError
+	for _, unit := range vErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		field := fieldPath(unit.InstanceLocation)
+
+		// report each missing property as an error of that property, not of its parent object
+		if required, ok := unit.Error.Kind.(*kind.Required); ok {
+			for _, p := range required.Missing {
+				errs = append(errs, types.ToolArgumentError{Field: joinFieldPath(field, p), Message: "is required"})
+			}
+			continue
+		}
+		errs = append(errs, types.ToolArgumentError{Field: field, Message: unit.Error.String()})
+	}
+	if len(errs) == 0 {
+		// should not happen, but never return a validation error without any explanation
+		errs = append(errs, types.ToolArgumentError{Message: vErr.Error()})
+	}
+	return errs
+}
+
+// fieldPath converts a JSON pointer (eg- /repo/files/2) into a field path (eg- repo.files[2]).
+func fieldPath(pointer string) string {
+	if pointer == "" {
+		return ""
+	}
+	var field string
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if _, err := strconv.Atoi(token); err == nil && field != "" {
+			field += "[" + token + "]"
+			continue
+		}
+		field = joinFieldPath(field, token)
+	}
+	return field
+}
+
+func joinFieldPath(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	return parent + "." + child
+}
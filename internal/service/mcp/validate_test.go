@@ -0,0 +1,145 @@
+package mcp
+
+import (
+	"errors"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestArgumentErrors(t *testing.T) {
+	inputSchema := `{
+		"type": "object",
+		"properties": {
+			"repo": {
+				"type": "object",
+				"properties": {
+					"owner": {"type": "string"},
+					"name": {"type": "string"}
+				},
+				"required": ["owner", "name"]
+			},
+			"files": {"type": "array", "items": {"type": "string"}},
+			"count": {"type": "integer", "minimum": 1}
+		},
+		"required": ["repo"]
+	}`
+	schema, err := compileInputSchema("github__commit", []byte(inputSchema))
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		args   string
+		fields []string
+	}{
+		{
+			name:   "valid arguments",
+			args:   `{"repo": {"owner": "mcpjungle", "name": "mcpjungle"}, "files": ["a.go"], "count": 2}`,
+			fields: nil,
+		},
+		{
+			name:   "missing required argument",
+			args:   `{}`,
+			fields: []string{"repo"},
+		},
+		{
+			name:   "missing nested arguments",
+			args:   `{"repo": {}}`,
+			fields: []string{"repo.owner", "repo.name"},
+		},
+		{
+			name:   "wrong types",
+			args:   `{"repo": {"owner": "a", "name": "b"}, "files": ["a.go", 3], "count": 0}`,
+			fields: []string{"count", "files[1]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance, err := jsonschema.UnmarshalJSON(strings.NewReader(tt.args))
+			if err != nil {
+				t.Fatalf("failed to parse arguments: %v", err)
+			}
+			err = schema.Validate(instance)
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("expected arguments to be valid, got %v", err)
+				}
+				return
+			}
+			var vErr *jsonschema.ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected a validation error, got %v", err)
+			}
+			var fields []string
+			for _, e := range argumentErrors(vErr) {
+				fields = append(fields, e.Field)
+			}
+			slices.Sort(fields)
+			slices.Sort(tt.fields)
+			if !slices.Equal(fields, tt.fields) {
+				t.Errorf("expected errors for fields %v, got %v", tt.fields, argumentErrors(vErr))
+			}
+		})
+	}
+}
+
+func TestToolArgumentsError(t *testing.T) {
+	err := &ToolArgumentsError{
+		Tool: "github__commit",
+		Errors: []types.ToolArgumentError{
+			{Field: "repo.owner", Message: "is required"},
+			{Message: "additional properties 'foo' not allowed"},
+		},
+	}
+	expected := "invalid arguments for tool github__commit: repo.owner: is required; " +
+		"additional properties 'foo' not allowed"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestInputSchemaCache(t *testing.T) {
+	m := &MCPService{schemas: make(map[string]*compiledSchema)}
+	v1 := []byte(`{"type": "object", "required": ["repo"]}`)
+
+	first, err := m.inputSchema("github__commit", v1)
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+	if again, _ := m.inputSchema("github__commit", v1); again != first {
+		t.Errorf("expected the compiled schema to be reused")
+	}
+
+	// a changed schema is compiled again
+	v2 := []byte(`{"type": "object", "required": ["owner"]}`)
+	changed, err := m.inputSchema("github__commit", v2)
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+	if changed == first {
+		t.Errorf("expected a changed schema to be compiled again")
+	}
+
+	m.forgetInputSchemas("github__commit")
+	if again, _ := m.inputSchema("github__commit", v2); again == changed {
+		t.Errorf("expected a forgotten schema to be compiled again")
+	}
+}
+
+func TestToolArgumentsErrorResult(t *testing.T) {
+	if _, ok := toolArgumentsErrorResult(errors.New("connection refused")); ok {
+		t.Errorf("expected only invalid arguments to be reported as a tool result")
+	}
+	argErrs := []types.ToolArgumentError{{Field: "repo", Message: "is required"}}
+	result, ok := toolArgumentsErrorResult(&ToolArgumentsError{Tool: "github__commit", Errors: argErrs})
+	if !ok || !result.IsError {
+		t.Fatalf("expected an error result for invalid arguments, got %+v", result)
+	}
+	if got, _ := result.Meta.AdditionalFields["validationErrors"].([]types.ToolArgumentError); !slices.Equal(got, argErrs) {
+		t.Errorf("expected the argument errors in the metadata, got %v", result.Meta.AdditionalFields)
+	}
+}
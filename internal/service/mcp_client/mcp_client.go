@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"github.com/mcpjungle/mcpjungle/internal"
+	"github.com/mcpjungle/mcpjungle/internal/db"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"gorm.io/gorm"
 )
 
@@ -19,11 +21,37 @@ func NewMCPClientService(db *gorm.DB) *McpClientService {
 
 // ListClients retrieves all MCP clients known to mcpjungle from the database
 func (m *McpClientService) ListClients() ([]*model.McpClient, error) {
-	var clients []*model.McpClient
-	if err := m.db.Find(&clients).Error; err != nil {
-		return nil, err
+	clients, _, err := m.ListClientsPage(types.ListOptions{})
+	return clients, err
+}
+
+// clientSortFields are the fields that MCP clients can be sorted by.
+var clientSortFields = map[string]db.SortField[model.McpClient]{
+	"name": {
+		Columns: []db.SortColumn{{Expr: "name"}, {Expr: "id"}},
+		Values:  func(c model.McpClient) []any { return []any{c.Name, c.ID} },
+	},
+	"created_at": {
+		Columns: []db.SortColumn{{Expr: "created_at"}, {Expr: "id"}},
+		Values:  func(c model.McpClient) []any { return []any{c.CreatedAt, c.ID} },
+	},
+}
+
+// ListClientsPage retrieves a page of the MCP clients, along with the cursor of the next page,
+// which is empty on the last page. Clients are sorted by name unless the options specify otherwise.
+func (m *McpClientService) ListClientsPage(opts types.ListOptions) ([]*model.McpClient, string, error) {
+	if opts.Sort == "" {
+		opts.Sort = "name"
 	}
-	return clients, nil
+	rows, next, err := db.FindPage(m.db.Model(&model.McpClient{}), clientSortFields, opts, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	clients := make([]*model.McpClient, len(rows))
+	for i := range rows {
+		clients[i] = &rows[i]
+	}
+	return clients, next, nil
 }
 
 // CreateClient creates a new MCP client in the database.
@@ -59,3 +87,16 @@ func (m *McpClientService) DeleteClient(name string) error {
 	result := m.db.Unscoped().Where("name = ?", name).Delete(&model.McpClient{})
 	return result.Error
 }
+
+// GetClientByName retrieves an MCP client by its name from the database.
+// It returns an error if no such client is found.
+func (m *McpClientService) GetClientByName(name string) (*model.McpClient, error) {
+	var client model.McpClient
+	if err := m.db.Where("name = ?", name).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("client not found")
+		}
+		return nil, err
+	}
+	return &client, nil
+}
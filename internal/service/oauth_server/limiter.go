@@ -0,0 +1,75 @@
+package oauth_server
+
+import (
+	"sync"
+	"time"
+)
+
+// attemptLimiter limits the number of attempts made per key (eg- the IP address of the caller)
+// within a sliding time window.
+type attemptLimiter struct {
+	max    int
+	window time.Duration
+
+	mu       sync.Mutex
+	attempts map[string][]time.Time
+}
+
+func newAttemptLimiter(max int, window time.Duration) *attemptLimiter {
+	return &attemptLimiter{max: max, window: window, attempts: make(map[string][]time.Time)}
+}
+
+// allow returns true if fewer than the maximum number of attempts were recorded for the key within the window.
+func (l *attemptLimiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.recent(key, time.Now())) < l.max
+}
+
+// record records an attempt for the key.
+func (l *attemptLimiter) record(key string) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.add(key, now)
+}
+
+// take records an attempt for the key if fewer than the maximum number of attempts were recorded within
+// the window, and returns whether it did.
+func (l *attemptLimiter) take(key string) bool {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.recent(key, now)) >= l.max {
+		return false
+	}
+	l.add(key, now)
+	return true
+}
+
+// add adds an attempt made at the given time for the key.
+// l.mu must be held by the caller.
+func (l *attemptLimiter) add(key string, now time.Time) {
+	// forget the keys whose attempts are all outside the window so that the map doesn't grow forever
+	for k := range l.attempts {
+		l.recent(k, now)
+	}
+	l.attempts[key] = append(l.attempts[key], now)
+}
+
+// recent drops the attempts recorded for the key outside the window and returns the remaining ones.
+// l.mu must be held by the caller.
+func (l *attemptLimiter) recent(key string, now time.Time) []time.Time {
+	attempts := l.attempts[key]
+	i := 0
+	for i < len(attempts) && now.Sub(attempts[i]) > l.window {
+		i++
+	}
+	attempts = attempts[i:]
+	if len(attempts) == 0 {
+		delete(l.attempts, key)
+	} else {
+		l.attempts[key] = attempts
+	}
+	return attempts
+}
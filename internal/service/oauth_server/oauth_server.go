@@ -0,0 +1,540 @@
+package oauth_server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// authorizationCodeTTL is how long an MCP client has to exchange an authorization code for tokens.
+	authorizationCodeTTL = 5 * time.Minute
+
+	// ApprovalCodeTTL is how long an approval code issued by an admin can be entered on the consent page.
+	ApprovalCodeTTL = 10 * time.Minute
+	// maxFailedApprovals is the number of invalid approval codes that can be entered from an IP address
+	// within approvalFailureWindow. Further attempts are rejected until the window has passed.
+	maxFailedApprovals    = 5
+	approvalFailureWindow = 15 * time.Minute
+
+	// Anyone can register a client, so registrations are limited per IP address and in total.
+	// Clients that hold no tokens are deleted after unusedClientTTL, ie- clients that were never authorized
+	// or whose tokens have all expired.
+	maxRegistrationsPerIP = 10
+	registrationWindow    = time.Hour
+	maxOAuthClients       = 1000
+	unusedClientTTL       = 24 * time.Hour
+
+	accessTokenTTL  = time.Hour
+	refreshTokenTTL = 30 * 24 * time.Hour
+
+	// CodeChallengeMethodS256 is the only PKCE method supported. Plain challenges are rejected.
+	CodeChallengeMethodS256 = "S256"
+
+	TokenEndpointAuthNone              = "none"
+	TokenEndpointAuthClientSecretPost  = "client_secret_post"
+	TokenEndpointAuthClientSecretBasic = "client_secret_basic"
+
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+)
+
+// Error is an OAuth error response, identified by one of the error codes defined in RFC 6749.
+type Error struct {
+	Code        string
+	Description string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+func newError(code, format string, args ...any) *Error {
+	return &Error{Code: code, Description: fmt.Sprintf(format, args...)}
+}
+
+// ErrRegistrationLimitExceeded is returned when no more clients can be registered for now.
+var ErrRegistrationLimitExceeded = errors.New("client registration limit exceeded, try again later")
+
+// ErrTooManyFailedApprovals is returned when too many invalid approval codes were entered from an IP address.
+var ErrTooManyFailedApprovals = errors.New("too many invalid approval codes, try again later")
+
+// AuthorizationRequest contains the parameters of a request to the authorization endpoint.
+type AuthorizationRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// authorizationCode is an issued authorization code waiting to be exchanged for tokens.
+type authorizationCode struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	mcpClientID   uint
+	issuedAt      time.Time
+}
+
+// approval is a one-time approval code issued by an admin, waiting to be entered on the consent page.
+type approval struct {
+	mcpClient *model.McpClient
+	issuedAt  time.Time
+}
+
+// OAuthServerService lets MCP clients obtain access tokens for the MCP proxy using OAuth.
+// An admin approves each authorization by issuing a one-time approval code, which picks the MCP client
+// identity that the issued tokens grant.
+type OAuthServerService struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	codes     map[string]*authorizationCode
+	approvals map[string]*approval
+
+	// failedApprovals counts the invalid approval codes entered per IP address
+	failedApprovals *attemptLimiter
+	// registrations counts the clients registered per IP address
+	registrations *attemptLimiter
+}
+
+func NewOAuthServerService(db *gorm.DB) *OAuthServerService {
+	return &OAuthServerService{
+		db:              db,
+		codes:           make(map[string]*authorizationCode),
+		approvals:       make(map[string]*approval),
+		failedApprovals: newAttemptLimiter(maxFailedApprovals, approvalFailureWindow),
+		registrations:   newAttemptLimiter(maxRegistrationsPerIP, registrationWindow),
+	}
+}
+
+// RegisterClient registers a new OAuth client through dynamic client registration, on behalf of a caller
+// at the given IP address. It returns ErrRegistrationLimitExceeded if the caller or all callers together
+// have registered too many clients.
+// Clients that use the "none" auth method are public clients and don't receive a secret.
+func (s *OAuthServerService) RegisterClient(
+	req *types.OAuthClientRegistrationRequest, remoteIP string,
+) (*types.OAuthClientRegistrationResponse, error) {
+	if len(req.RedirectURIs) == 0 {
+		return nil, newError("invalid_redirect_uri", "at least one redirect_uri is required")
+	}
+	for _, u := range req.RedirectURIs {
+		if err := validateRedirectURI(u); err != nil {
+			return nil, err
+		}
+	}
+	for _, g := range req.GrantTypes {
+		if g != GrantTypeAuthorizationCode && g != GrantTypeRefreshToken {
+			return nil, newError("invalid_client_metadata", "unsupported grant_type %s", g)
+		}
+	}
+	for _, r := range req.ResponseTypes {
+		if r != "code" {
+			return nil, newError("invalid_client_metadata", "unsupported response_type %s", r)
+		}
+	}
+
+	authMethod := req.TokenEndpointAuthMethod
+	if authMethod == "" {
+		authMethod = TokenEndpointAuthClientSecretBasic
+	}
+	var secret string
+	switch authMethod {
+	case TokenEndpointAuthNone:
+	case TokenEndpointAuthClientSecretBasic, TokenEndpointAuthClientSecretPost:
+		var err error
+		if secret, err = internal.GenerateAccessToken(); err != nil {
+			return nil, fmt.Errorf("failed to generate client secret: %w", err)
+		}
+	default:
+		return nil, newError("invalid_client_metadata", "unsupported token_endpoint_auth_method %s", authMethod)
+	}
+
+	if !s.registrations.take(remoteIP) {
+		return nil, fmt.Errorf("%w: too many clients were registered from %s", ErrRegistrationLimitExceeded, remoteIP)
+	}
+	if err := s.deleteUnusedClients(); err != nil {
+		return nil, err
+	}
+	var count int64
+	if err := s.db.Model(&model.OAuthClient{}).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count oauth clients: %w", err)
+	}
+	if count >= maxOAuthClients {
+		return nil, fmt.Errorf("%w: %d clients are registered", ErrRegistrationLimitExceeded, count)
+	}
+
+	clientID, err := internal.GenerateAccessToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate client id: %w", err)
+	}
+	redirectURIs, err := json.Marshal(req.RedirectURIs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode redirect uris: %w", err)
+	}
+	client := &model.OAuthClient{
+		ClientID:     clientID,
+		ClientSecret: secret,
+		ClientName:   req.ClientName,
+		RedirectURIs: redirectURIs,
+	}
+	if err := s.db.Create(client).Error; err != nil {
+		return nil, fmt.Errorf("failed to register oauth client: %w", err)
+	}
+
+	return &types.OAuthClientRegistrationResponse{
+		ClientID:                clientID,
+		ClientSecret:            secret,
+		ClientIDIssuedAt:        client.CreatedAt.Unix(),
+		ClientName:              req.ClientName,
+		RedirectURIs:            req.RedirectURIs,
+		TokenEndpointAuthMethod: authMethod,
+		GrantTypes:              []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken},
+		ResponseTypes:           []string{"code"},
+	}, nil
+}
+
+// validateRedirectURI checks that authorization responses can only be sent to a redirect URI that belongs to
+// the client: an https URL, an http URL of the loopback interface or a private-use URI scheme in reverse domain
+// name notation (eg- com.example.app:/callback), as recommended for native apps by RFC 8252.
+// Other schemes, like javascript: and data:, are rejected.
+func validateRedirectURI(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+		return newError("invalid_redirect_uri", "redirect_uri %s must be an absolute URI without a fragment", u)
+	}
+	switch scheme := strings.ToLower(parsed.Scheme); {
+	case scheme == "https":
+		if parsed.Host == "" {
+			return newError("invalid_redirect_uri", "redirect_uri %s must have a host", u)
+		}
+	case scheme == "http":
+		if !isLoopbackHost(parsed.Hostname()) {
+			return newError("invalid_redirect_uri", "redirect_uri %s must use https unless it is a loopback address", u)
+		}
+	case strings.Contains(scheme, "."):
+		// a private-use scheme of a native app
+	default:
+		return newError(
+			"invalid_redirect_uri",
+			"redirect_uri %s must use https, http on a loopback address or a private-use scheme (eg- com.example.app)", u,
+		)
+	}
+	return nil
+}
+
+// isLoopbackHost returns true if the host of a URL refers to the loopback interface.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// deleteUnusedClients deletes the clients that were registered more than unusedClientTTL ago and hold no tokens.
+func (s *OAuthServerService) deleteUnusedClients() error {
+	now := time.Now()
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// expired tokens don't keep a client alive
+		if err := tx.Unscoped().Where("expires_at < ?", now).Delete(&model.OAuthIssuedToken{}).Error; err != nil {
+			return fmt.Errorf("failed to delete expired tokens: %w", err)
+		}
+		err := tx.Unscoped().
+			Where("created_at < ?", now.Add(-unusedClientTTL)).
+			Where("client_id NOT IN (?)", tx.Model(&model.OAuthIssuedToken{}).Select("client_id")).
+			Delete(&model.OAuthClient{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete unused oauth clients: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetClient returns the registered OAuth client with the given client ID.
+func (s *OAuthServerService) GetClient(clientID string) (*model.OAuthClient, error) {
+	var c model.OAuthClient
+	if err := s.db.Where("client_id = ?", clientID).First(&c).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newError("invalid_client", "unknown client_id")
+		}
+		return nil, fmt.Errorf("failed to get oauth client: %w", err)
+	}
+	return &c, nil
+}
+
+// ValidateRedirect checks that the authorization request names a registered client and one of its redirect URIs.
+// Until this succeeds, errors must be shown to the user instead of being sent to the redirect URI.
+func (s *OAuthServerService) ValidateRedirect(req *AuthorizationRequest) (*model.OAuthClient, error) {
+	client, err := s.GetClient(req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if !client.HasRedirectURI(req.RedirectURI) {
+		return nil, newError("invalid_request", "redirect_uri is not registered for this client")
+	}
+	return client, nil
+}
+
+// ValidateAuthorizationRequest checks the remaining parameters of an authorization request.
+// Every authorization must use PKCE with the S256 method.
+func (s *OAuthServerService) ValidateAuthorizationRequest(req *AuthorizationRequest) error {
+	if req.ResponseType != "code" {
+		return newError("unsupported_response_type", "only the code response type is supported")
+	}
+	if req.CodeChallenge == "" {
+		return newError("invalid_request", "code_challenge is required")
+	}
+	if req.CodeChallengeMethod != CodeChallengeMethodS256 {
+		return newError("invalid_request", "code_challenge_method must be %s", CodeChallengeMethodS256)
+	}
+	return nil
+}
+
+// CreateApproval issues a one-time approval code for an authorization that grants the identity of the
+// given MCP client. Admins hand the code to the user, who enters it on the consent page instead of a static token.
+func (s *OAuthServerService) CreateApproval(mcpClient *model.McpClient) (*types.OAuthApproval, error) {
+	code, err := internal.GenerateAccessToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate approval code: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.approvals {
+		if time.Since(v.issuedAt) > ApprovalCodeTTL {
+			delete(s.approvals, k)
+		}
+	}
+	s.approvals[code] = &approval{mcpClient: mcpClient, issuedAt: time.Now()}
+	return &types.OAuthApproval{
+		ApprovalCode: code,
+		McpClient:    mcpClient.Name,
+		ExpiresIn:    int64(ApprovalCodeTTL.Seconds()),
+	}, nil
+}
+
+// RedeemApproval uses up an approval code entered on the consent page by a user at the given IP address
+// and returns the MCP client whose identity the authorization grants.
+// Failed attempts are throttled per IP address, it returns ErrTooManyFailedApprovals once there were too many.
+func (s *OAuthServerService) RedeemApproval(code, remoteIP string) (*model.McpClient, error) {
+	if !s.failedApprovals.allow(remoteIP) {
+		return nil, ErrTooManyFailedApprovals
+	}
+
+	s.mu.Lock()
+	a, ok := s.approvals[code]
+	delete(s.approvals, code)
+	s.mu.Unlock()
+
+	if !ok || time.Since(a.issuedAt) > ApprovalCodeTTL {
+		s.failedApprovals.record(remoteIP)
+		return nil, newError("access_denied", "invalid or expired approval code")
+	}
+	return a.mcpClient, nil
+}
+
+// CreateAuthorizationCode issues an authorization code for an approved authorization request.
+// The tokens obtained with the code grant the identity of the given MCP client.
+func (s *OAuthServerService) CreateAuthorizationCode(req *AuthorizationRequest, mcpClient *model.McpClient) (string, error) {
+	code, err := internal.GenerateAccessToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate authorization code: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.codes {
+		if time.Since(v.issuedAt) > authorizationCodeTTL {
+			delete(s.codes, k)
+		}
+	}
+	s.codes[code] = &authorizationCode{
+		clientID:      req.ClientID,
+		redirectURI:   req.RedirectURI,
+		codeChallenge: req.CodeChallenge,
+		mcpClientID:   mcpClient.ID,
+		issuedAt:      time.Now(),
+	}
+	return code, nil
+}
+
+// AuthenticateClient verifies the credentials that a client presented to the token endpoint.
+func (s *OAuthServerService) AuthenticateClient(clientID, clientSecret string) (*model.OAuthClient, error) {
+	if clientID == "" {
+		return nil, newError("invalid_client", "client_id is required")
+	}
+	client, err := s.GetClient(clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client.ClientSecret != "" &&
+		subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(clientSecret)) != 1 {
+		return nil, newError("invalid_client", "invalid client credentials")
+	}
+	return client, nil
+}
+
+// ExchangeAuthorizationCode exchanges an authorization code for an access token and a refresh token.
+// A code can only be used once.
+func (s *OAuthServerService) ExchangeAuthorizationCode(
+	client *model.OAuthClient, code, redirectURI, codeVerifier string,
+) (*types.OAuthTokenResponse, error) {
+	s.mu.Lock()
+	c, ok := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+
+	if !ok || time.Since(c.issuedAt) > authorizationCodeTTL {
+		return nil, newError("invalid_grant", "unknown or expired authorization code")
+	}
+	if c.clientID != client.ClientID {
+		return nil, newError("invalid_grant", "authorization code was issued to another client")
+	}
+	if c.redirectURI != redirectURI {
+		return nil, newError("invalid_grant", "redirect_uri does not match the authorization request")
+	}
+	if codeVerifier == "" {
+		return nil, newError("invalid_request", "code_verifier is required")
+	}
+	sum := sha256.Sum256([]byte(codeVerifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(c.codeChallenge)) != 1 {
+		return nil, newError("invalid_grant", "code_verifier does not match the code_challenge")
+	}
+
+	return s.issueTokens(s.db, client.ClientID, c.mcpClientID)
+}
+
+// RefreshAccessToken issues new tokens in exchange for a refresh token.
+// Refresh tokens are rotated, so the presented token stops working.
+func (s *OAuthServerService) RefreshAccessToken(
+	client *model.OAuthClient, refreshToken string,
+) (*types.OAuthTokenResponse, error) {
+	hash := hashToken(refreshToken)
+	var t model.OAuthIssuedToken
+	res := s.db.Where("token_hash = ? AND kind = ?", hash, model.OAuthTokenKindRefresh).Limit(1).Find(&t)
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to look up refresh token: %w", res.Error)
+	}
+	if res.RowsAffected == 0 || time.Now().After(t.ExpiresAt) || t.ClientID != client.ClientID {
+		return nil, newError("invalid_grant", "invalid or expired refresh token")
+	}
+
+	var resp *types.OAuthTokenResponse
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// only one of the requests that present the same refresh token at once gets to revoke it,
+		// so a replayed token never yields a second set of tokens
+		res := tx.Unscoped().Where("token_hash = ? AND kind = ?", hash, model.OAuthTokenKindRefresh).
+			Delete(&model.OAuthIssuedToken{})
+		if res.Error != nil {
+			return fmt.Errorf("failed to revoke refresh token: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return newError("invalid_grant", "invalid or expired refresh token")
+		}
+		var err error
+		resp, err = s.issueTokens(tx, client.ClientID, t.McpClientID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetMcpClientByAccessToken returns the MCP client whose identity an issued access token grants.
+func (s *OAuthServerService) GetMcpClientByAccessToken(token string) (*model.McpClient, error) {
+	var t model.OAuthIssuedToken
+	res := s.db.Where(
+		"token_hash = ? AND kind = ?", hashToken(token), model.OAuthTokenKindAccess,
+	).Limit(1).Find(&t)
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to look up access token: %w", res.Error)
+	}
+	if res.RowsAffected == 0 || time.Now().After(t.ExpiresAt) {
+		return nil, errors.New("invalid or expired access token")
+	}
+
+	// the MCP client may have been deleted since, which revokes all tokens issued for it
+	var client model.McpClient
+	if err := s.db.First(&client, t.McpClientID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("client not found")
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+// issueTokens creates a new access token and refresh token for the MCP client and stores their hashes using db,
+// which may be a transaction.
+func (s *OAuthServerService) issueTokens(
+	db *gorm.DB, clientID string, mcpClientID uint,
+) (*types.OAuthTokenResponse, error) {
+	accessToken, err := internal.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := internal.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tokens := []*model.OAuthIssuedToken{
+		{
+			TokenHash:   hashToken(accessToken),
+			Kind:        model.OAuthTokenKindAccess,
+			ClientID:    clientID,
+			McpClientID: mcpClientID,
+			ExpiresAt:   now.Add(accessTokenTTL),
+		},
+		{
+			TokenHash:   hashToken(refreshToken),
+			Kind:        model.OAuthTokenKindRefresh,
+			ClientID:    clientID,
+			McpClientID: mcpClientID,
+			ExpiresAt:   now.Add(refreshTokenTTL),
+		},
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// expired tokens are of no use to anyone, clean them up while we're at it
+		if err := tx.Unscoped().Where("expires_at < ?", now).Delete(&model.OAuthIssuedToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&tokens).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store issued tokens: %w", err)
+	}
+
+	return &types.OAuthTokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(accessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// hashToken returns the hash under which an issued token is stored.
+// Tokens are random 256-bit values, so a plain SHA-256 is enough.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
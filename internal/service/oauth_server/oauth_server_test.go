@@ -0,0 +1,292 @@
+package oauth_server
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestDB opens a SQLite database in a temporary directory and creates the tables of the given models.
+func newTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func newTestService(t *testing.T) *OAuthServerService {
+	db := newTestDB(t, &model.McpClient{}, &model.OAuthClient{}, &model.OAuthIssuedToken{})
+	return NewOAuthServerService(db)
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	s := newTestService(t)
+
+	mcpClient := &model.McpClient{Name: "cursor", AccessToken: "static", AllowList: datatypes.JSON(`["github"]`)}
+	if err := s.db.Create(mcpClient).Error; err != nil {
+		t.Fatalf("failed to create mcp client: %v", err)
+	}
+
+	reg, err := s.RegisterClient(&types.OAuthClientRegistrationRequest{
+		RedirectURIs:            []string{"http://127.0.0.1:3000/callback"},
+		TokenEndpointAuthMethod: TokenEndpointAuthNone,
+	}, "192.0.2.1")
+	if err != nil {
+		t.Fatalf("failed to register client: %v", err)
+	}
+	if reg.ClientSecret != "" {
+		t.Errorf("expected no secret for a public client")
+	}
+
+	verifier := "a-sufficiently-long-code-verifier-for-the-test"
+	sum := sha256.Sum256([]byte(verifier))
+	req := &AuthorizationRequest{
+		ResponseType:        "code",
+		ClientID:            reg.ClientID,
+		RedirectURI:         "http://127.0.0.1:3000/callback",
+		CodeChallenge:       base64.RawURLEncoding.EncodeToString(sum[:]),
+		CodeChallengeMethod: CodeChallengeMethodS256,
+	}
+	client, err := s.ValidateRedirect(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.ValidateAuthorizationRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a wrong verifier is rejected and the code can't be reused afterwards
+	code, err := s.CreateAuthorizationCode(req, mcpClient)
+	if err != nil {
+		t.Fatalf("failed to create code: %v", err)
+	}
+	if _, err := s.ExchangeAuthorizationCode(client, code, req.RedirectURI, "wrong"); err == nil {
+		t.Errorf("expected an error for a wrong code_verifier")
+	}
+	if _, err := s.ExchangeAuthorizationCode(client, code, req.RedirectURI, verifier); err == nil {
+		t.Errorf("expected an error for a used code")
+	}
+
+	code, err = s.CreateAuthorizationCode(req, mcpClient)
+	if err != nil {
+		t.Fatalf("failed to create code: %v", err)
+	}
+	tokens, err := s.ExchangeAuthorizationCode(client, code, req.RedirectURI, verifier)
+	if err != nil {
+		t.Fatalf("failed to exchange code: %v", err)
+	}
+	got, err := s.GetMcpClientByAccessToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("failed to look up access token: %v", err)
+	}
+	if got.Name != "cursor" || !got.CheckHasServerAccess("github") {
+		t.Errorf("expected the token to grant the identity of cursor, got %s", got.Name)
+	}
+	if _, err := s.GetMcpClientByAccessToken(tokens.RefreshToken); err == nil {
+		t.Errorf("expected a refresh token not to be accepted as an access token")
+	}
+
+	// refresh tokens are rotated
+	refreshed, err := s.RefreshAccessToken(client, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if _, err := s.GetMcpClientByAccessToken(refreshed.AccessToken); err != nil {
+		t.Errorf("failed to look up refreshed access token: %v", err)
+	}
+	_, err = s.RefreshAccessToken(client, tokens.RefreshToken)
+	var oauthErr *Error
+	if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_grant" {
+		t.Errorf("expected invalid_grant for a used refresh token, got %v", err)
+	}
+
+	// deleting the MCP client revokes its tokens
+	if err := s.db.Unscoped().Delete(mcpClient).Error; err != nil {
+		t.Fatalf("failed to delete mcp client: %v", err)
+	}
+	if _, err := s.GetMcpClientByAccessToken(refreshed.AccessToken); err == nil {
+		t.Errorf("expected an error after the MCP client was deleted")
+	}
+}
+
+func TestValidateAuthorizationRequest(t *testing.T) {
+	s := newTestService(t)
+	cases := []struct {
+		name string
+		req  AuthorizationRequest
+		ok   bool
+	}{
+		{"valid", AuthorizationRequest{ResponseType: "code", CodeChallenge: "c", CodeChallengeMethod: "S256"}, true},
+		{"token response type", AuthorizationRequest{ResponseType: "token", CodeChallenge: "c", CodeChallengeMethod: "S256"}, false},
+		{"missing challenge", AuthorizationRequest{ResponseType: "code", CodeChallengeMethod: "S256"}, false},
+		{"plain challenge", AuthorizationRequest{ResponseType: "code", CodeChallenge: "c", CodeChallengeMethod: "plain"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := s.ValidateAuthorizationRequest(&tc.req)
+			if (err == nil) != tc.ok {
+				t.Errorf("expected ok=%v, got error %v", tc.ok, err)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenReplay(t *testing.T) {
+	s := newTestService(t)
+
+	mcpClient := &model.McpClient{Name: "cursor", AccessToken: "static", AllowList: datatypes.JSON(`[]`)}
+	if err := s.db.Create(mcpClient).Error; err != nil {
+		t.Fatalf("failed to create mcp client: %v", err)
+	}
+	client := &model.OAuthClient{ClientID: "app"}
+	tokens, err := s.issueTokens(s.db, client.ClientID, mcpClient.ID)
+	if err != nil {
+		t.Fatalf("failed to issue tokens: %v", err)
+	}
+
+	// a refresh token presented by several requests at once is only exchanged once
+	const n = 8
+	var (
+		wg        sync.WaitGroup
+		successes atomic.Int32
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.RefreshAccessToken(client, tokens.RefreshToken); err == nil {
+				successes.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if successes.Load() != 1 {
+		t.Errorf("expected the refresh token to be exchanged once, got %d", successes.Load())
+	}
+
+	var count int64
+	if err := s.db.Model(&model.OAuthIssuedToken{}).Where("kind = ?", model.OAuthTokenKindRefresh).
+		Count(&count).Error; err != nil {
+		t.Fatalf("failed to count refresh tokens: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 live refresh token, got %d", count)
+	}
+}
+
+func TestRedeemApproval(t *testing.T) {
+	s := newTestService(t)
+	mcpClient := &model.McpClient{Name: "cursor"}
+
+	a, err := s.CreateApproval(mcpClient)
+	if err != nil {
+		t.Fatalf("failed to create approval: %v", err)
+	}
+	if a.McpClient != "cursor" || a.ApprovalCode == "" {
+		t.Fatalf("unexpected approval: %+v", a)
+	}
+
+	// an approval code grants the identity of its MCP client and can only be used once
+	got, err := s.RedeemApproval(a.ApprovalCode, "192.0.2.1")
+	if err != nil || got != mcpClient {
+		t.Fatalf("expected the approval to grant the identity of the MCP client, got %v, %v", got, err)
+	}
+	if _, err := s.RedeemApproval(a.ApprovalCode, "192.0.2.1"); err == nil {
+		t.Errorf("expected an error when an approval code is reused")
+	}
+
+	// invalid codes are throttled per IP address, even a valid code is then rejected
+	a, err = s.CreateApproval(mcpClient)
+	if err != nil {
+		t.Fatalf("failed to create approval: %v", err)
+	}
+	for i := 0; i < maxFailedApprovals; i++ {
+		if _, err := s.RedeemApproval("guess", "203.0.113.7"); err == nil || errors.Is(err, ErrTooManyFailedApprovals) {
+			t.Fatalf("attempt %d: expected an invalid approval code error, got %v", i+1, err)
+		}
+	}
+	if _, err := s.RedeemApproval(a.ApprovalCode, "203.0.113.7"); !errors.Is(err, ErrTooManyFailedApprovals) {
+		t.Errorf("expected further attempts to be rejected, got %v", err)
+	}
+	if _, err := s.RedeemApproval(a.ApprovalCode, "192.0.2.1"); err != nil {
+		t.Errorf("expected attempts from other IP addresses to be allowed, got %v", err)
+	}
+}
+
+func TestValidateRedirectURI(t *testing.T) {
+	cases := []struct {
+		uri string
+		ok  bool
+	}{
+		{"https://claude.ai/api/mcp/auth_callback", true},
+		{"http://127.0.0.1:3000/callback", true},
+		{"http://localhost/callback", true},
+		{"http://[::1]:8000/callback", true},
+		{"com.example.app:/oauth/callback", true},
+		{"http://example.com/callback", false},
+		{"javascript:alert(document.cookie)", false},
+		{"data:text/html,<script>alert(1)</script>", false},
+		{"https://claude.ai/callback#fragment", false},
+		{"/relative/callback", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.uri, func(t *testing.T) {
+			if err := validateRedirectURI(tc.uri); (err == nil) != tc.ok {
+				t.Errorf("expected ok=%v, got error %v", tc.ok, err)
+			}
+		})
+	}
+}
+
+func TestRegisterClientLimits(t *testing.T) {
+	s := newTestService(t)
+	req := &types.OAuthClientRegistrationRequest{RedirectURIs: []string{"http://127.0.0.1:3000/callback"}}
+
+	// registrations are limited per IP address
+	for i := 0; i < maxRegistrationsPerIP; i++ {
+		if _, err := s.RegisterClient(req, "203.0.113.7"); err != nil {
+			t.Fatalf("registration %d: unexpected error: %v", i+1, err)
+		}
+	}
+	if _, err := s.RegisterClient(req, "203.0.113.7"); !errors.Is(err, ErrRegistrationLimitExceeded) {
+		t.Fatalf("expected the registration limit to be exceeded, got %v", err)
+	}
+
+	// clients that were registered long ago and hold no tokens are deleted
+	used, err := s.RegisterClient(req, "192.0.2.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.issueTokens(s.db, used.ClientID, 1); err != nil {
+		t.Fatalf("failed to issue tokens: %v", err)
+	}
+	old := time.Now().Add(-2 * unusedClientTTL)
+	if err := s.db.Model(&model.OAuthClient{}).Where("1 = 1").Update("created_at", old).Error; err != nil {
+		t.Fatalf("failed to age clients: %v", err)
+	}
+	if err := s.deleteUnusedClients(); err != nil {
+		t.Fatalf("failed to delete unused clients: %v", err)
+	}
+	var remaining []model.OAuthClient
+	if err := s.db.Find(&remaining).Error; err != nil {
+		t.Fatalf("failed to list clients: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].ClientID != used.ClientID {
+		t.Errorf("expected only the client holding tokens to be kept, got %d clients", len(remaining))
+	}
+}
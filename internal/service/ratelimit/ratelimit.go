@@ -0,0 +1,311 @@
+package ratelimit
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	dayPeriodLayout   = "2006-01-02"
+	monthPeriodLayout = "2006-01"
+)
+
+// RateLimitError is returned when a tool call is rejected because it exceeds a rate limit or quota.
+type RateLimitError struct {
+	// Limit is the name of the rate limit that was exceeded.
+	Limit string
+	// Reason describes which restriction of the limit was exceeded.
+	Reason string
+	// RetryAfter is the time after which the call may succeed.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf(
+		"%s exceeded (rate limit '%s'), retry after %s", e.Reason, e.Limit, e.RetryAfter.Round(time.Second),
+	)
+}
+
+// RetryAfterSeconds returns RetryAfter rounded up to whole seconds, suitable for the Retry-After HTTP header.
+func (e *RateLimitError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// RateLimitService manages rate limits & quotas and enforces them on tool calls.
+// Token buckets are kept in memory while quota usage counters are persisted in the database.
+type RateLimitService struct {
+	db *gorm.DB
+
+	// mu guards the map of token buckets, keyed by rate limit ID. Each bucket has its own lock,
+	// so that calls under different limits never wait for each other.
+	mu      sync.Mutex
+	buckets map[uint]*tokenBucket
+
+	// now returns the current time, it can be replaced in tests
+	now func() time.Time
+}
+
+func NewRateLimitService(db *gorm.DB) *RateLimitService {
+	return &RateLimitService{
+		db:      db,
+		buckets: make(map[uint]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// ListRateLimits returns all rate limits from the database.
+func (r *RateLimitService) ListRateLimits() ([]model.RateLimit, error) {
+	var limits []model.RateLimit
+	if err := r.db.Find(&limits).Error; err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// CreateRateLimit validates and creates a new rate limit in the database.
+func (r *RateLimitService) CreateRateLimit(l *model.RateLimit) error {
+	if err := validateRateLimit(l); err != nil {
+		return err
+	}
+	if err := r.db.Create(l).Error; err != nil {
+		return fmt.Errorf("failed to create rate limit: %w", err)
+	}
+	return nil
+}
+
+// DeleteRateLimit deletes a rate limit along with its usage counters.
+// It is an idempotent operation. Deleting a rate limit that does not exist will not return an error.
+func (r *RateLimitService) DeleteRateLimit(name string) error {
+	var l model.RateLimit
+	if err := r.db.Where("name = ?", name).First(&l).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := r.db.Where("rate_limit_id = ?", l.ID).Delete(&model.RateLimitUsage{}).Error; err != nil {
+		return fmt.Errorf("failed to delete usage counters of rate limit %s: %w", name, err)
+	}
+	if err := r.db.Unscoped().Delete(&l).Error; err != nil {
+		return fmt.Errorf("failed to delete rate limit %s: %w", name, err)
+	}
+
+	r.mu.Lock()
+	delete(r.buckets, l.ID)
+	r.mu.Unlock()
+	return nil
+}
+
+// GetUsage returns the number of calls counted against the rate limit today and in the current month.
+func (r *RateLimitService) GetUsage(l *model.RateLimit) (int64, int64, error) {
+	now := r.now().UTC()
+	today, err := r.usage(l.ID, now.Format(dayPeriodLayout))
+	if err != nil {
+		return 0, 0, err
+	}
+	thisMonth, err := r.usage(l.ID, now.Format(monthPeriodLayout))
+	if err != nil {
+		return 0, 0, err
+	}
+	return today, thisMonth, nil
+}
+
+// Allow checks all rate limits that apply to a call of the given tool and consumes from them.
+// client is the name of the calling MCP client, it is empty if the call was not made by an MCP client
+// (eg- calls made by an admin via the HTTP API), in which case only tool-scoped limits apply.
+// It returns a *RateLimitError if any of the limits is exceeded. In that case, nothing is consumed.
+func (r *RateLimitService) Allow(client, server, tool string) error {
+	limits, err := r.matchingLimits(client, server, tool)
+	if err != nil {
+		return fmt.Errorf("failed to look up rate limits: %w", err)
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+
+	now := r.now().UTC()
+	buckets, err := r.takeTokens(limits, now)
+	if err != nil {
+		return err
+	}
+	if err := r.consumeQuotas(limits, now); err != nil {
+		// the call is rejected, so it must not use up the request rate of the other limits
+		for _, b := range buckets {
+			b.mu.Lock()
+			b.giveBack(now)
+			b.mu.Unlock()
+		}
+		return err
+	}
+	return nil
+}
+
+// takeTokens takes a token from the bucket of every limit that restricts the request rate and returns those buckets.
+// It returns a *RateLimitError if any of the buckets is empty, in which case no token is taken.
+func (r *RateLimitService) takeTokens(limits []model.RateLimit, now time.Time) ([]*tokenBucket, error) {
+	var (
+		rated   []*model.RateLimit
+		buckets []*tokenBucket
+	)
+	for i := range limits {
+		if limits[i].RequestsPerMinute > 0 {
+			rated = append(rated, &limits[i])
+		}
+	}
+	// buckets are always locked in the order of their limits so that concurrent calls cannot deadlock
+	slices.SortFunc(rated, func(a, b *model.RateLimit) int { return cmp.Compare(a.ID, b.ID) })
+
+	r.mu.Lock()
+	for _, l := range rated {
+		buckets = append(buckets, r.bucket(l, now))
+	}
+	r.mu.Unlock()
+
+	for _, b := range buckets {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+	}
+	for i, b := range buckets {
+		if wait := b.wait(now); wait > 0 {
+			return nil, &RateLimitError{Limit: rated[i].Name, Reason: "request rate", RetryAfter: wait}
+		}
+	}
+	for _, b := range buckets {
+		b.take(now)
+	}
+	return buckets, nil
+}
+
+// consumeQuotas counts the call against the daily and monthly usage of every limit.
+// It returns a *RateLimitError if any quota is exhausted, in which case nothing is counted.
+func (r *RateLimitService) consumeQuotas(limits []model.RateLimit, now time.Time) error {
+	day := now.Format(dayPeriodLayout)
+	month := now.Format(monthPeriodLayout)
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range limits {
+			l := &limits[i]
+			ok, err := incrementUsage(tx, l.ID, day, l.DailyQuota)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				nextDay := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+				return &RateLimitError{Limit: l.Name, Reason: "daily quota", RetryAfter: nextDay.Sub(now)}
+			}
+			ok, err = incrementUsage(tx, l.ID, month, l.MonthlyQuota)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				nextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+				return &RateLimitError{Limit: l.Name, Reason: "monthly quota", RetryAfter: nextMonth.Sub(now)}
+			}
+		}
+		return nil
+	})
+}
+
+// matchingLimits returns all rate limits that apply to a call of the given tool by the given client.
+func (r *RateLimitService) matchingLimits(client, server, tool string) ([]model.RateLimit, error) {
+	var limits []model.RateLimit
+	q := r.db.Where("scope = ? AND tool = ?", model.RateLimitScopeTool, tool)
+	if client != "" {
+		q = q.Or("scope = ? AND client = ?", model.RateLimitScopeClient, client).
+			Or("scope = ? AND client = ? AND server = ?", model.RateLimitScopeClientServer, client, server)
+	}
+	if err := q.Find(&limits).Error; err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// bucket returns the token bucket of a rate limit, creating it if necessary.
+// The caller must hold r.mu, but not the lock of the bucket.
+func (r *RateLimitService) bucket(l *model.RateLimit, now time.Time) *tokenBucket {
+	b, ok := r.buckets[l.ID]
+	if !ok {
+		b = newTokenBucket(l.RequestsPerMinute, l.Burst, now)
+		r.buckets[l.ID] = b
+	}
+	return b
+}
+
+// usage returns the value of the usage counter of a rate limit for the given period.
+func (r *RateLimitService) usage(limitID uint, period string) (int64, error) {
+	var u model.RateLimitUsage
+	err := r.db.Where("rate_limit_id = ? AND period = ?", limitID, period).First(&u).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get usage of rate limit: %w", err)
+	}
+	return u.Count, nil
+}
+
+// incrementUsage increments the usage counter of a rate limit for the given period, unless it has reached the quota.
+// A quota of 0 means that the usage is unlimited. It returns false if the quota is exhausted.
+// The check and the increment are a single upsert, so concurrent calls cannot exceed the quota.
+func incrementUsage(db *gorm.DB, limitID uint, period string, quota int) (bool, error) {
+	onConflict := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "rate_limit_id"}, {Name: "period"}},
+		DoUpdates: clause.Assignments(map[string]any{"count": gorm.Expr("rate_limit_usages.count + 1")}),
+	}
+	if quota > 0 {
+		onConflict.Where = clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "rate_limit_usages.count < ?", Vars: []any{quota}},
+		}}
+	}
+	res := db.Clauses(onConflict).Create(&model.RateLimitUsage{RateLimitID: limitID, Period: period, Count: 1})
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to update usage of rate limit: %w", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// validateRateLimit checks that the rate limit targets exactly what its scope requires
+// and that it restricts something.
+func validateRateLimit(l *model.RateLimit) error {
+	if l.Name == "" {
+		return errors.New("name is required")
+	}
+	switch l.Scope {
+	case model.RateLimitScopeClient:
+		if l.Client == "" || l.Server != "" || l.Tool != "" {
+			return errors.New("a client-scoped rate limit requires only the client name")
+		}
+	case model.RateLimitScopeClientServer:
+		if l.Client == "" || l.Server == "" || l.Tool != "" {
+			return errors.New("a client_server-scoped rate limit requires only the client and server names")
+		}
+	case model.RateLimitScopeTool:
+		if l.Tool == "" || l.Client != "" || l.Server != "" {
+			return errors.New("a tool-scoped rate limit requires only the tool name")
+		}
+		if !strings.Contains(l.Tool, "__") {
+			return fmt.Errorf("tool '%s' must be the canonical tool name, eg- <server>__<tool>", l.Tool)
+		}
+	default:
+		return fmt.Errorf(
+			"invalid scope '%s' (acceptable values: '%s', '%s', '%s')",
+			l.Scope, model.RateLimitScopeClient, model.RateLimitScopeClientServer, model.RateLimitScopeTool,
+		)
+	}
+	if l.RequestsPerMinute < 0 || l.Burst < 0 || l.DailyQuota < 0 || l.MonthlyQuota < 0 {
+		return errors.New("limits must not be negative")
+	}
+	if l.RequestsPerMinute == 0 && l.DailyQuota == 0 && l.MonthlyQuota == 0 {
+		return errors.New("at least one of requests per minute, daily quota or monthly quota must be set")
+	}
+	return nil
+}
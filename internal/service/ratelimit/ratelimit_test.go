@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"errors"
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestDB opens a SQLite database in a temporary directory and creates the tables of the given models.
+func newTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func newTestService(t *testing.T) *RateLimitService {
+	db := newTestDB(t, &model.RateLimit{}, &model.RateLimitUsage{})
+	return NewRateLimitService(db)
+}
+
+func TestAllow(t *testing.T) {
+	r := newTestService(t)
+	now := time.Date(2025, 7, 14, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	limits := []*model.RateLimit{
+		{Name: "rate", Scope: model.RateLimitScopeClient, Client: "cursor", RequestsPerMinute: 60, Burst: 2},
+		{Name: "quota", Scope: model.RateLimitScopeTool, Tool: "github__search", DailyQuota: 3},
+	}
+	for _, l := range limits {
+		if err := r.CreateRateLimit(l); err != nil {
+			t.Fatalf("failed to create rate limit: %v", err)
+		}
+	}
+
+	// the burst of the client is used up
+	for i := 0; i < 2; i++ {
+		if err := r.Allow("cursor", "github", "github__search"); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i+1, err)
+		}
+	}
+	var rateErr *RateLimitError
+	if err := r.Allow("cursor", "github", "github__search"); !errors.As(err, &rateErr) || rateErr.Limit != "rate" {
+		t.Fatalf("expected the request rate to be exceeded, got %v", err)
+	}
+	if rateErr.RetryAfter != time.Second {
+		t.Errorf("expected to retry after 1s, got %s", rateErr.RetryAfter)
+	}
+
+	// a call rejected by the rate limit is not counted against the quota
+	today, _, err := r.GetUsage(limits[1])
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if today != 2 {
+		t.Errorf("expected 2 calls to be counted today, got %d", today)
+	}
+
+	// calls without a client are only subject to tool-scoped limits
+	if err := r.Allow("", "github", "github__search"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.Allow("", "github", "github__search"); !errors.As(err, &rateErr) || rateErr.Limit != "quota" {
+		t.Fatalf("expected the daily quota to be exhausted, got %v", err)
+	}
+	if rateErr.RetryAfter != 12*time.Hour {
+		t.Errorf("expected to retry after 12h, got %s", rateErr.RetryAfter)
+	}
+
+	// a call rejected by the quota does not use up the request rate
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		if err := r.Allow("cursor", "github", "github__search"); !errors.As(err, &rateErr) || rateErr.Limit != "quota" {
+			t.Fatalf("call %d: expected the daily quota to be exhausted, got %v", i+1, err)
+		}
+	}
+	if err := r.Allow("cursor", "github", "github__other"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the quota is reset the next day
+	now = now.Add(12 * time.Hour)
+	if err := r.Allow("", "github", "github__search"); err != nil {
+		t.Fatalf("unexpected error on the next day: %v", err)
+	}
+}
+
+func TestAllowQuotaConcurrency(t *testing.T) {
+	r := newTestService(t)
+	l := &model.RateLimit{Name: "quota", Scope: model.RateLimitScopeClient, Client: "cursor", MonthlyQuota: 5}
+	if err := r.CreateRateLimit(l); err != nil {
+		t.Fatalf("failed to create rate limit: %v", err)
+	}
+
+	// concurrent calls never exceed the quota
+	var (
+		wg      sync.WaitGroup
+		allowed atomic.Int32
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.Allow("cursor", "github", "github__search"); err == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed.Load() != 5 {
+		t.Errorf("expected 5 calls to be allowed, got %d", allowed.Load())
+	}
+	_, thisMonth, err := r.GetUsage(l)
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if thisMonth != int64(allowed.Load()) {
+		t.Errorf("expected the usage to match the %d allowed calls, got %d", allowed.Load(), thisMonth)
+	}
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket is a classic token bucket rate limiter.
+// The bucket holds up to capacity tokens and is refilled at a constant rate.
+// Every call consumes one token.
+// The methods don't lock the bucket themselves, callers must hold mu.
+type tokenBucket struct {
+	mu sync.Mutex
+
+	capacity   float64
+	ratePerSec float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+// newTokenBucket creates a full token bucket that is refilled at requestsPerMinute.
+// If burst is 0, the capacity of the bucket is equal to requestsPerMinute.
+func newTokenBucket(requestsPerMinute, burst int, now time.Time) *tokenBucket {
+	capacity := float64(burst)
+	if burst == 0 {
+		capacity = float64(requestsPerMinute)
+	}
+	return &tokenBucket{
+		capacity:   capacity,
+		ratePerSec: float64(requestsPerMinute) / 60,
+		tokens:     capacity,
+		lastRefill: now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = min(b.capacity, b.tokens+elapsed*b.ratePerSec)
+	b.lastRefill = now
+}
+
+// wait returns how long the caller has to wait until a token is available.
+// It returns 0 if a token is available right now.
+func (b *tokenBucket) wait(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	missing := 1 - b.tokens
+	return time.Duration(missing / b.ratePerSec * float64(time.Second))
+}
+
+// take consumes a token from the bucket.
+// The caller must make sure that a token is available using wait().
+func (b *tokenBucket) take(now time.Time) {
+	b.refill(now)
+	b.tokens--
+}
+
+// giveBack returns a token taken from the bucket, eg- because the call was rejected by another limit.
+func (b *tokenBucket) giveBack(now time.Time) {
+	b.refill(now)
+	b.tokens = min(b.capacity, b.tokens+1)
+}
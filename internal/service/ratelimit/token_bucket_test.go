@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	// 60 requests per minute = 1 token per second, with a burst of 3
+	b := newTokenBucket(60, 3, now)
+
+	for i := 0; i < 3; i++ {
+		if wait := b.wait(now); wait != 0 {
+			t.Fatalf("call %d: wait() = %s, want 0", i+1, wait)
+		}
+		b.take(now)
+	}
+	if wait := b.wait(now); wait != time.Second {
+		t.Fatalf("wait() on empty bucket = %s, want 1s", wait)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if wait := b.wait(now); wait != 500*time.Millisecond {
+		t.Fatalf("wait() after 500ms = %s, want 500ms", wait)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if wait := b.wait(now); wait != 0 {
+		t.Fatalf("wait() after 1s = %s, want 0", wait)
+	}
+
+	// the bucket never holds more than its capacity
+	now = now.Add(time.Hour)
+	b.refill(now)
+	if b.tokens != 3 {
+		t.Fatalf("tokens after 1h = %v, want 3", b.tokens)
+	}
+}
+
+func TestTokenBucketDefaultBurst(t *testing.T) {
+	b := newTokenBucket(10, 0, time.Now())
+	if b.capacity != 10 {
+		t.Fatalf("capacity = %v, want 10", b.capacity)
+	}
+}
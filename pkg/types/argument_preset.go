@@ -0,0 +1,16 @@
+package types
+
+// ArgumentPreset pins arguments of a tool to fixed values (eg- owner=our-org).
+// Preset parameters are removed from the tool's input schema shown to MCP clients and
+// their values are injected by mcpjungle into every call made through the MCP proxy.
+type ArgumentPreset struct {
+	// Tool is the canonical name of the tool.
+	Tool string `json:"tool"`
+
+	// Client is the name of the MCP client the preset applies to.
+	// If empty, the preset applies to all clients. A client's own preset takes precedence for the same parameter.
+	Client string `json:"client,omitempty"`
+
+	// Arguments maps parameter names to their values.
+	Arguments map[string]any `json:"arguments"`
+}
@@ -0,0 +1,23 @@
+package types
+
+// CachePolicy configures caching of tool results for an MCP server.
+type CachePolicy struct {
+	// TTLSec is the time (in seconds) a cached result stays valid.
+	TTLSec int `json:"ttl_sec"`
+
+	// MaxEntries is the maximum number of results cached for the MCP server.
+	// When the cache is full, the least recently used result is evicted.
+	MaxEntries int `json:"max_entries,omitempty"`
+
+	// Tools is the list of tools (without the server name prefix) whose results are cached.
+	// If empty, the results of all tools annotated as read-only or idempotent by the MCP server are cached.
+	Tools []string `json:"tools,omitempty"`
+}
+
+// CacheStats describes the tool result cache of an MCP server.
+type CacheStats struct {
+	Server  string `json:"server"`
+	Entries int    `json:"entries"`
+	Hits    int64  `json:"hits"`
+	Misses  int64  `json:"misses"`
+}
@@ -0,0 +1,42 @@
+package types
+
+import "encoding/json"
+
+// CompositeTool is a tool defined in mcpjungle whose implementation is a pipeline of calls to existing tools.
+// The MCP proxy exposes it like any other tool.
+//
+// The arguments of each step and the output are templates (Go text/template syntax) that can refer to
+// the caller's input as {{.input.<param>}} and to the result of an earlier step as {{.steps.<id>}}.
+// A step's result is its structured content if the tool returns one, otherwise its text content,
+// parsed as JSON if possible. So fields of a result can be referred to as {{.steps.<id>.<field>}}.
+// A string argument that consists of a single reference keeps the type of the referenced value.
+type CompositeTool struct {
+	// Name is the name under which the tool is exposed. It must not contain a double underscore,
+	// which is reserved for the canonical names of the tools of MCP servers.
+	Name string `json:"name"`
+
+	Description string `json:"description,omitempty"`
+
+	// InputSchema is the JSON schema of the tool's input. Defaults to an object without properties.
+	InputSchema json.RawMessage `json:"input_schema,omitempty"`
+
+	// Steps are the tool calls made, in order, when the tool is called.
+	Steps []CompositeToolStep `json:"steps"`
+
+	// Output is the template of the text returned by the tool.
+	// If empty, the result of the last step is returned as is.
+	Output string `json:"output,omitempty"`
+}
+
+// CompositeToolStep is a call to a tool made by a composite tool.
+type CompositeToolStep struct {
+	// ID identifies the step so that later steps and the output can refer to its result.
+	ID string `json:"id"`
+
+	// Tool is the canonical name (or alias) of the called tool.
+	Tool string `json:"tool"`
+
+	// Arguments are the arguments of the call. String values, including the ones nested in objects
+	// and arrays, are templates.
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxLabelLength is the maximum length of a label key or value.
+const maxLabelLength = 63
+
+var (
+	validLabelKey   = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_./-]*[a-zA-Z0-9])?$`)
+	validLabelValue = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9_.-]*[a-zA-Z0-9])?)?$`)
+)
+
+// ValidateLabels checks that the keys and values of labels are well-formed.
+// Keys must start and end with an alphanumeric character and may contain '-', '_', '.' and '/' in between.
+// Values follow the same rules except that '/' is not allowed, and may be empty.
+func ValidateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if len(k) > maxLabelLength || !validLabelKey.MatchString(k) {
+			return fmt.Errorf("invalid label key '%s'", k)
+		}
+		if len(v) > maxLabelLength || !validLabelValue.MatchString(v) {
+			return fmt.Errorf("invalid value '%s' for label %s", v, k)
+		}
+	}
+	return nil
+}
+
+// LabelOperator is the comparison applied by a requirement of a label selector.
+type LabelOperator string
+
+const (
+	LabelEquals       LabelOperator = "="
+	LabelNotEquals    LabelOperator = "!="
+	LabelExists       LabelOperator = "exists"
+	LabelDoesNotExist LabelOperator = "!"
+)
+
+// LabelRequirement is a single condition on the labels of an entity, eg- team=data.
+type LabelRequirement struct {
+	Key      string
+	Operator LabelOperator
+	Value    string
+}
+
+// LabelSelector selects entities by their labels. An entity matches if it satisfies all requirements.
+// An empty selector matches everything.
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector parses a comma-separated list of requirements.
+// Each requirement takes one of the forms 'key=value' (or 'key==value'), 'key!=value',
+// 'key' (the label is set) and '!key' (the label is not set).
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	var sel LabelSelector
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var r LabelRequirement
+		switch {
+		case strings.HasPrefix(part, "!"):
+			r = LabelRequirement{Key: strings.TrimSpace(part[1:]), Operator: LabelDoesNotExist}
+		case strings.Contains(part, "!="):
+			k, v, _ := strings.Cut(part, "!=")
+			r = LabelRequirement{Key: strings.TrimSpace(k), Operator: LabelNotEquals, Value: strings.TrimSpace(v)}
+		case strings.Contains(part, "="):
+			k, v, _ := strings.Cut(part, "=")
+			v = strings.TrimPrefix(v, "=")
+			r = LabelRequirement{Key: strings.TrimSpace(k), Operator: LabelEquals, Value: strings.TrimSpace(v)}
+		default:
+			r = LabelRequirement{Key: part, Operator: LabelExists}
+		}
+		if err := ValidateLabels(map[string]string{r.Key: r.Value}); err != nil {
+			return nil, fmt.Errorf("invalid label selector '%s': %w", s, err)
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+// Matches returns true if the labels satisfy all requirements of the selector.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		v, ok := labels[r.Key]
+		switch r.Operator {
+		case LabelEquals:
+			if !ok || v != r.Value {
+				return false
+			}
+		case LabelNotEquals:
+			if ok && v == r.Value {
+				return false
+			}
+		case LabelExists:
+			if !ok {
+				return false
+			}
+		case LabelDoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String returns the selector in the form accepted by ParseLabelSelector.
+func (s LabelSelector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		switch r.Operator {
+		case LabelExists:
+			parts[i] = r.Key
+		case LabelDoesNotExist:
+			parts[i] = "!" + r.Key
+		default:
+			parts[i] = r.Key + string(r.Operator) + r.Value
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// FormatLabels returns labels in the form 'key=value', sorted by key.
+func FormatLabels(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	formatted := make([]string, len(keys))
+	for i, k := range keys {
+		formatted[i] = k + "=" + labels[k]
+	}
+	return formatted
+}
+
+// LabelsUpdate changes the labels of an MCP server or tool.
+// Labels set to null are removed, all other labels are added or overwritten.
+type LabelsUpdate struct {
+	Labels map[string]*string `json:"labels"`
+}
@@ -8,4 +8,8 @@ type McpClient struct {
 
 	// AllowList is a list of MCP Servers that this client is allowed to access from MCPJungle.
 	AllowList []string `json:"allow_list"`
+
+	// AllowSelectors is a list of label selectors (eg- team=data).
+	// The client is also allowed to access the MCP Servers whose labels match any of them.
+	AllowSelectors []string `json:"allow_selectors,omitempty"`
 }
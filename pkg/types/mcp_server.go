@@ -1,6 +1,9 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // McpServerTransport represents the transport protocol used by an MCP server.
 // All transport types supported by mcpjungle are defined in this file with this type.
@@ -9,8 +12,164 @@ type McpServerTransport string
 const (
 	TransportStdio          McpServerTransport = "stdio"
 	TransportStreamableHTTP McpServerTransport = "streamable_http"
+	TransportSSE            McpServerTransport = "sse"
+
+	// TransportOpenAPI is not an MCP transport. mcpjungle itself serves one tool per operation of an
+	// OpenAPI spec and executes the calls to those tools as HTTP requests to the API.
+	TransportOpenAPI McpServerTransport = "openapi"
+)
+
+// McpServerHealth represents the health of an MCP server as observed by the mcpjungle health checker.
+type McpServerHealth string
+
+const (
+	// HealthUnknown means that the server has not been probed yet or health checking is disabled.
+	HealthUnknown McpServerHealth = "unknown"
+	// HealthHealthy means that the server responded to the latest probe.
+	HealthHealthy McpServerHealth = "healthy"
+	// HealthUnhealthy means that the server failed multiple consecutive probes.
+	// Its tools are temporarily hidden from the MCP proxy until it recovers.
+	HealthUnhealthy McpServerHealth = "unhealthy"
+)
+
+// CircuitBreakerState represents the state of the circuit breaker guarding calls to an MCP server.
+type CircuitBreakerState string
+
+const (
+	// CircuitClosed means that calls are forwarded to the MCP server as usual.
+	CircuitClosed CircuitBreakerState = "closed"
+	// CircuitOpen means that calls to the MCP server fail fast without being forwarded.
+	CircuitOpen CircuitBreakerState = "open"
+	// CircuitHalfOpen means that the cooldown has elapsed and a trial call is allowed through.
+	CircuitHalfOpen CircuitBreakerState = "half_open"
 )
 
+// McpServerStatus describes the runtime status of an MCP server registered in mcpjungle.
+type McpServerStatus struct {
+	Health        McpServerHealth `json:"health"`
+	LastCheckedAt *time.Time      `json:"last_checked_at,omitempty"`
+	LastError     string          `json:"last_error,omitempty"`
+
+	// CircuitBreaker is the state of the server's circuit breaker.
+	// It is empty if the server does not have a circuit breaker configured.
+	CircuitBreaker CircuitBreakerState `json:"circuit_breaker,omitempty"`
+
+	// ActiveCalls and QueuedCalls are only reported if the server has a concurrency limit configured.
+	ActiveCalls int `json:"active_calls,omitempty"`
+	QueuedCalls int `json:"queued_calls,omitempty"`
+}
+
+// ServerPolicy configures how mcpjungle interacts with an upstream MCP server.
+// All fields are optional, mcpjungle falls back to sensible defaults for the ones not set.
+type ServerPolicy struct {
+	// InitTimeoutSec is the timeout (in seconds) for the initialization request to the MCP server.
+	InitTimeoutSec int `json:"init_timeout_sec,omitempty"`
+
+	// CallTimeoutSec is the timeout (in seconds) for a single tool call to the MCP server.
+	CallTimeoutSec int `json:"call_timeout_sec,omitempty"`
+
+	// Retry configures retries of failed tool calls.
+	// If not set, failed calls are never retried.
+	Retry *RetryPolicy `json:"retry,omitempty"`
+
+	// CircuitBreaker configures a circuit breaker that fails calls fast after repeated errors.
+	// If not set, calls are always forwarded to the MCP server.
+	CircuitBreaker *CircuitBreakerPolicy `json:"circuit_breaker,omitempty"`
+
+	// MaxConcurrency is the maximum number of tool calls that may be in progress on the MCP server at once.
+	// Calls beyond this limit wait in a queue. If not set, the number of concurrent calls is unlimited.
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+
+	// MaxQueueSize is the maximum number of calls that may wait for the MCP server at once.
+	// Calls arriving while the queue is full are rejected immediately.
+	// If not set, calls are never rejected because of the queue size.
+	MaxQueueSize int `json:"max_queue_size,omitempty"`
+
+	// QueueTimeoutSec is the maximum time (in seconds) a call may wait in the queue before it is rejected.
+	// If not set, calls wait until the caller gives up.
+	QueueTimeoutSec int `json:"queue_timeout_sec,omitempty"`
+
+	// DisableInputValidation turns off the validation of tool call arguments against the tools' input schemas.
+	// This is useful if the MCP server publishes schemas that are stricter than what it actually accepts.
+	DisableInputValidation bool `json:"disable_input_validation,omitempty"`
+
+	// Cache configures caching of tool results.
+	// If not set, tool results are never cached.
+	Cache *CachePolicy `json:"cache,omitempty"`
+}
+
+// RetryPolicy describes how failed tool calls are retried.
+// Only calls that failed to reach the MCP server (eg- connection errors, timeouts) are retried.
+// A tool that returns an error result is never retried.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a failed call is retried.
+	MaxRetries int `json:"max_retries"`
+
+	// BackoffMs is the delay (in milliseconds) before the first retry.
+	// The delay is doubled for every subsequent retry.
+	BackoffMs int `json:"backoff_ms,omitempty"`
+
+	// Tools is the list of tools (without the server name prefix) that are safe to retry.
+	// Tools that are annotated as idempotent or read-only by the MCP server are always safe to retry.
+	Tools []string `json:"tools,omitempty"`
+}
+
+// CircuitBreakerPolicy describes when calls to an MCP server should fail fast.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failed calls after which the circuit opens.
+	FailureThreshold int `json:"failure_threshold"`
+
+	// CooldownSec is the time (in seconds) the circuit stays open before a trial call is allowed.
+	CooldownSec int `json:"cooldown_sec,omitempty"`
+}
+
+// Validate returns an error if the policy contains invalid values.
+func (p *ServerPolicy) Validate() error {
+	if p.InitTimeoutSec < 0 {
+		return fmt.Errorf("init_timeout_sec must not be negative")
+	}
+	if p.CallTimeoutSec < 0 {
+		return fmt.Errorf("call_timeout_sec must not be negative")
+	}
+	if p.Retry != nil {
+		if p.Retry.MaxRetries < 0 {
+			return fmt.Errorf("retry.max_retries must not be negative")
+		}
+		if p.Retry.BackoffMs < 0 {
+			return fmt.Errorf("retry.backoff_ms must not be negative")
+		}
+	}
+	if p.CircuitBreaker != nil {
+		if p.CircuitBreaker.FailureThreshold < 1 {
+			return fmt.Errorf("circuit_breaker.failure_threshold must be at least 1")
+		}
+		if p.CircuitBreaker.CooldownSec < 0 {
+			return fmt.Errorf("circuit_breaker.cooldown_sec must not be negative")
+		}
+	}
+	if p.MaxConcurrency < 0 {
+		return fmt.Errorf("max_concurrency must not be negative")
+	}
+	if p.MaxQueueSize < 0 {
+		return fmt.Errorf("max_queue_size must not be negative")
+	}
+	if p.QueueTimeoutSec < 0 {
+		return fmt.Errorf("queue_timeout_sec must not be negative")
+	}
+	if p.MaxConcurrency == 0 && (p.MaxQueueSize > 0 || p.QueueTimeoutSec > 0) {
+		return fmt.Errorf("max_queue_size and queue_timeout_sec require max_concurrency to be set")
+	}
+	if p.Cache != nil {
+		if p.Cache.TTLSec < 1 {
+			return fmt.Errorf("cache.ttl_sec must be at least 1")
+		}
+		if p.Cache.MaxEntries < 0 {
+			return fmt.Errorf("cache.max_entries must not be negative")
+		}
+	}
+	return nil
+}
+
 // McpServer represents an MCP server registered in the MCPJungle registry.
 type McpServer struct {
 	Name        string `json:"name"`
@@ -19,9 +178,22 @@ type McpServer struct {
 
 	URL string `json:"url"`
 
+	// SpecURL is the URL the OpenAPI spec of an openapi server was fetched from, if it was registered by URL.
+	SpecURL string `json:"spec_url,omitempty"`
+
+	// Headers, TLS and OAuth describe the connection to an HTTP-based server, with all secrets redacted.
+	Headers map[string]string    `json:"headers,omitempty"`
+	TLS     *UpstreamTLSConfig   `json:"tls,omitempty"`
+	OAuth   *UpstreamOAuthConfig `json:"oauth,omitempty"`
+
 	Command string            `json:"command"`
 	Args    []string          `json:"args"`
 	Env     map[string]string `json:"env"`
+
+	Policy *ServerPolicy    `json:"policy,omitempty"`
+	Status *McpServerStatus `json:"status,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // RegisterServerInput is the input structure for registering a new MCP server with mcpjungle.
@@ -31,21 +203,43 @@ type RegisterServerInput struct {
 	Name string `json:"name"`
 
 	// Transport is the transport protocol used by the MCP server.
-	// valid values are "stdio", "streamable_http""
+	// valid values are "stdio", "streamable_http", "sse", "openapi"
 	Transport string `json:"transport"`
 
 	Description string `json:"description"`
 
 	// URL is the URL of the remote mcp server
-	// It is mandatory when transport is streamable_http and must be a valid
-	//  http/https URL (e.g., https://example.com/mcp).
+	// It is mandatory when transport is streamable_http or sse and must be a valid
+	//  http/https URL (e.g., https://example.com/mcp or https://example.com/sse).
+	// When transport is openapi, it is the base URL of the API, which defaults to the first server in the spec.
 	URL string `json:"url"`
 
-	// BearerToken is an optional token used for authenticating requests to the remote MCP server.
+	// Spec is the location of the OpenAPI spec (JSON or YAML) when the transport is "openapi".
+	// mcpjungle fetches it if it is an http/https URL. The CLI also accepts the path of a local file,
+	// which it reads into SpecContent.
+	Spec string `json:"spec,omitempty"`
+
+	// SpecContent is the OpenAPI spec document itself. It takes precedence over Spec.
+	SpecContent string `json:"spec_content,omitempty"`
+
+	// BearerToken is an optional token used for authenticating requests to the remote MCP server (or API).
 	// It is useful when the upstream MCP server requires static tokens (e.g., API tokens) for authentication.
 	// If the transport is "stdio", this field is ignored.
 	BearerToken string `json:"bearer_token"`
 
+	// Headers are custom headers sent in all requests to the remote MCP server, eg- API keys or tenant IDs.
+	// An Authorization header is overridden by BearerToken if that is set.
+	// If the transport is "stdio", this field is ignored.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// TLS optionally configures mutual TLS and verification of the remote MCP server's certificate.
+	// If the transport is "stdio", this field is ignored.
+	TLS *UpstreamTLSConfig `json:"tls,omitempty"`
+
+	// OAuth optionally makes mcpjungle authenticate with the remote MCP server as an OAuth client.
+	// It cannot be combined with BearerToken. If the transport is "stdio" or "openapi", this field is ignored.
+	OAuth *UpstreamOAuthConfig `json:"oauth,omitempty"`
+
 	// Command is the command to run the mcp server.
 	// It is mandatory when the transport is "stdio".
 	Command string `json:"command"`
@@ -56,18 +250,32 @@ type RegisterServerInput struct {
 	// Env is the set of environment variables to pass to the mcp server when the transport is "stdio".
 	// Both the key and value must be of type string.
 	Env map[string]string `json:"env"`
+
+	// Policy optionally configures timeouts, retries, the circuit breaker and the concurrency limit for this MCP server.
+	Policy *ServerPolicy `json:"policy,omitempty"`
+
+	// Labels are optional key/value pairs used to organise servers, eg- {"team": "data", "env": "prod"}.
+	// The tools of the server inherit its labels.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // ValidateTransport validates the input string and returns the corresponding model.McpServerTransport.
 // It returns an error if the input is invalid or empty.
 func ValidateTransport(input string) (McpServerTransport, error) {
-	errMsgExt := fmt.Sprintf("(acceptable values: '%s', '%s')", TransportStreamableHTTP, TransportStdio)
+	errMsgExt := fmt.Sprintf(
+		"(acceptable values: '%s', '%s', '%s', '%s')",
+		TransportStreamableHTTP, TransportStdio, TransportSSE, TransportOpenAPI,
+	)
 
 	switch input {
 	case string(TransportStreamableHTTP):
 		return TransportStreamableHTTP, nil
 	case string(TransportStdio):
 		return TransportStdio, nil
+	case string(TransportSSE):
+		return TransportSSE, nil
+	case string(TransportOpenAPI):
+		return TransportOpenAPI, nil
 	case "":
 		return "", fmt.Errorf("transport is required %s", errMsgExt)
 	default:
@@ -9,16 +9,69 @@ type ToolInputSchema struct {
 
 // Tool represents a tool provided by an MCP Server registered in the registry.
 type Tool struct {
-	Name        string          `json:"name"`
-	Enabled     bool            `json:"enabled"`
-	Description string          `json:"description"`
-	InputSchema ToolInputSchema `json:"input_schema"`
+	Name         string           `json:"name"`
+	Title        string           `json:"title,omitempty"`
+	Enabled      bool             `json:"enabled"`
+	Description  string           `json:"description"`
+	InputSchema  ToolInputSchema  `json:"input_schema"`
+	OutputSchema map[string]any   `json:"output_schema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+	Meta         map[string]any   `json:"_meta,omitempty"`
+
+	// Labels are the labels set on the tool itself. The tool also inherits the labels of its server.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// ToolFilter selects the tools returned by a tool search. Empty fields do not filter anything.
+type ToolFilter struct {
+	// Query is a list of keywords matched against the names, titles and descriptions of tools.
+	// Tools matching more keywords, or matching them in their name, are returned first.
+	Query string
+
+	// Server restricts the search to the tools of an MCP server.
+	Server string
+
+	// Enabled restricts the search to enabled (true) or disabled (false) tools.
+	Enabled *bool
+
+	// Labels is a label selector (eg- team=data,env!=prod) matched against the labels of tools,
+	// including the ones they inherit from their server.
+	Labels string
+
+	// Tag restricts the search to the tools that have this label, whatever its value.
+	Tag string
+}
+
+// ToolAnnotations are the hints supplied by an MCP server that describe the behaviour of a tool.
+// The hints are not guaranteed to be accurate, they should never be relied upon for security decisions.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
 }
 
 // ToolInvokeResult represents the result of a Tool call.
 // It is designed to be passed down to the end user.
 type ToolInvokeResult struct {
-	Meta    map[string]any   `json:"_meta,omitempty"`
-	IsError bool             `json:"isError,omitempty"`
+	Meta    map[string]any `json:"_meta,omitempty"`
+	IsError bool           `json:"isError,omitempty"`
+
+	// Content contains the content items returned by the tool as generic JSON objects.
+	// Every item has a "type" field, eg- text, image, audio, resource (embedded resource)
+	// or resource_link (a link to a resource that the client can fetch).
 	Content []map[string]any `json:"content"`
+
+	// StructuredContent is the structured output of the tool, if the tool returned any.
+	// It conforms to the output schema of the tool, if the tool has one.
+	StructuredContent any `json:"structuredContent,omitempty"`
+}
+
+// ToolArgumentError describes why an argument of a tool call does not match the tool's input schema.
+type ToolArgumentError struct {
+	// Field is the path of the offending argument, eg- "repo.owner" or "files[2]".
+	// It is empty if the error concerns the arguments object as a whole.
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,65 @@
+package types
+
+// OAuthProtectedResourceMetadata describes the MCP proxy as an OAuth protected resource (RFC 9728).
+// MCP clients use it to discover mcpjungle's authorization server.
+type OAuthProtectedResourceMetadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported"`
+}
+
+// OAuthAuthorizationServerMetadata describes mcpjungle's authorization server (RFC 8414).
+type OAuthAuthorizationServerMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	RegistrationEndpoint              string   `json:"registration_endpoint"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// OAuthClientRegistrationRequest is a dynamic client registration request (RFC 7591).
+type OAuthClientRegistrationRequest struct {
+	RedirectURIs            []string `json:"redirect_uris"`
+	ClientName              string   `json:"client_name,omitempty"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes              []string `json:"grant_types,omitempty"`
+	ResponseTypes           []string `json:"response_types,omitempty"`
+}
+
+// OAuthClientRegistrationResponse contains the credentials of a dynamically registered client.
+type OAuthClientRegistrationResponse struct {
+	ClientID                string   `json:"client_id"`
+	ClientSecret            string   `json:"client_secret,omitempty"`
+	ClientIDIssuedAt        int64    `json:"client_id_issued_at"`
+	ClientSecretExpiresAt   int64    `json:"client_secret_expires_at"`
+	ClientName              string   `json:"client_name,omitempty"`
+	RedirectURIs            []string `json:"redirect_uris"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method"`
+	GrantTypes              []string `json:"grant_types"`
+	ResponseTypes           []string `json:"response_types"`
+}
+
+// OAuthTokenResponse is the successful response of mcpjungle's token endpoint.
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// OAuthApprovalInput is the input for issuing a one-time approval code for the consent page.
+type OAuthApprovalInput struct {
+	// McpClient is the name of the MCP client whose identity the approved application gets.
+	McpClient string `json:"mcp_client"`
+}
+
+// OAuthApproval is a one-time code that an admin hands to a user to approve a single OAuth authorization.
+type OAuthApproval struct {
+	ApprovalCode string `json:"approval_code"`
+	McpClient    string `json:"mcp_client"`
+	// ExpiresIn is the number of seconds for which the code can be used.
+	ExpiresIn int64 `json:"expires_in"`
+}
@@ -0,0 +1,16 @@
+package types
+
+// NextCursorHeader is the HTTP response header of the list APIs that holds the cursor of the next page.
+// It is absent on the last page.
+const NextCursorHeader = "X-Next-Cursor"
+
+// ListOptions controls the order of the results of a list API and how they are split into pages.
+type ListOptions struct {
+	// Sort is the field to sort by (eg- name, created_at), prefixed with '-' for descending order.
+	// If empty, results are sorted by name, or by relevance when searching tools by keywords.
+	Sort string
+	// Limit is the maximum number of results in a page. If 0, all results are returned at once.
+	Limit int
+	// Cursor is the cursor of the next page returned along with the previous page, if any.
+	Cursor string
+}
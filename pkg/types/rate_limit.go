@@ -0,0 +1,36 @@
+package types
+
+// RateLimit restricts how often tools can be called via mcpjungle.
+type RateLimit struct {
+	// Name uniquely identifies the rate limit.
+	Name string `json:"name"`
+
+	// Scope is one of "client", "client_server" or "tool".
+	Scope string `json:"scope"`
+
+	// Client is the name of the MCP client the limit applies to (client & client_server scopes).
+	Client string `json:"client,omitempty"`
+	// Server is the name of the MCP server the limit applies to (client_server scope).
+	Server string `json:"server,omitempty"`
+	// Tool is the canonical name of the tool the limit applies to (tool scope).
+	Tool string `json:"tool,omitempty"`
+
+	// RequestsPerMinute and Burst configure a token bucket.
+	// Burst defaults to RequestsPerMinute if not set.
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+	Burst             int `json:"burst,omitempty"`
+
+	// DailyQuota and MonthlyQuota limit the number of calls per calendar day and month (UTC).
+	DailyQuota   int `json:"daily_quota,omitempty"`
+	MonthlyQuota int `json:"monthly_quota,omitempty"`
+
+	// Usage contains the number of calls made in the current quota periods.
+	// It is only populated in responses from the server.
+	Usage *RateLimitUsage `json:"usage,omitempty"`
+}
+
+// RateLimitUsage contains the number of calls counted against a rate limit's quotas.
+type RateLimitUsage struct {
+	Today     int64 `json:"today"`
+	ThisMonth int64 `json:"this_month"`
+}
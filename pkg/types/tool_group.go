@@ -0,0 +1,21 @@
+package types
+
+// ToolGroup is a curated set of tools across MCP servers.
+// Each group is served as a separate MCP server at /mcp/groups/<name>.
+type ToolGroup struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// IncludedTools contains the canonical names of the tools in the group (eg- github__git_commit).
+	IncludedTools []string `json:"included_tools,omitempty"`
+
+	// IncludedServers contains the names of MCP servers whose tools all belong to the group.
+	IncludedServers []string `json:"included_servers,omitempty"`
+
+	// Selector is a label selector (eg- team=data). All tools whose labels match it belong to the group,
+	// including tools whose labels change after the group is created.
+	Selector string `json:"selector,omitempty"`
+
+	// Endpoint is the path of the group's MCP endpoint. It is only populated in responses from the server.
+	Endpoint string `json:"endpoint,omitempty"`
+}
@@ -0,0 +1,19 @@
+package types
+
+// ToolOverride customizes how the MCP proxy presents a tool to MCP clients.
+// Overrides are kept separately from the tool definitions supplied by MCP servers, so they are preserved
+// when the tools of a server are fetched again.
+type ToolOverride struct {
+	// Tool is the canonical name of the tool (eg- github__search_issues_and_pull_requests_v2).
+	Tool string `json:"tool"`
+
+	// Alias is an alternate name under which the tool is also exposed by the proxy (eg- search_issues).
+	// It must not contain a double underscore, which is reserved for canonical tool names.
+	Alias string `json:"alias,omitempty"`
+
+	// Description replaces the description of the tool supplied by its MCP server.
+	Description string `json:"description,omitempty"`
+
+	// ParameterDescriptions replaces the descriptions of input parameters, keyed by parameter name.
+	ParameterDescriptions map[string]string `json:"parameter_descriptions,omitempty"`
+}
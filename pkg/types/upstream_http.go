@@ -0,0 +1,96 @@
+package types
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RedactedValue replaces secrets in the outputs of mcpjungle.
+const RedactedValue = "[REDACTED]"
+
+// UpstreamTLSConfig configures TLS for connections to an HTTP-based MCP server.
+// Certificates and keys are PEM-encoded.
+type UpstreamTLSConfig struct {
+	// ClientCert and ClientKey are the certificate & private key that mcpjungle presents to the
+	// MCP server for mutual TLS. Both must be set together.
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+
+	// CACert is a bundle of CA certificates used to verify the MCP server's certificate
+	// instead of the system's root CAs, eg- if the server uses a private CA.
+	CACert string `json:"ca_cert,omitempty"`
+
+	// InsecureSkipVerify disables verification of the MCP server's certificate.
+	// It must only be used for development.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+}
+
+// Validate checks that the certificates and keys can be loaded.
+func (c *UpstreamTLSConfig) Validate() error {
+	_, err := c.ClientTLSConfig()
+	return err
+}
+
+// ClientTLSConfig returns the crypto/tls configuration for connecting to the MCP server.
+func (c *UpstreamTLSConfig) ClientTLSConfig() (*tls.Config, error) {
+	conf := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+
+	if (c.ClientCert == "") != (c.ClientKey == "") {
+		return nil, errors.New("client certificate and client key must be provided together")
+	}
+	if c.ClientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(c.ClientCert), []byte(c.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.CACert)) {
+			return nil, errors.New("CA certificate bundle does not contain any valid PEM certificates")
+		}
+		conf.RootCAs = pool
+	}
+
+	return conf, nil
+}
+
+// Redacted returns a copy of the configuration that is safe to show, ie, without the client key.
+func (c *UpstreamTLSConfig) Redacted() *UpstreamTLSConfig {
+	r := *c
+	if r.ClientKey != "" {
+		r.ClientKey = RedactedValue
+	}
+	return &r
+}
+
+// ValidateHeaders checks that the custom headers to send to an MCP server are well-formed.
+func ValidateHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("invalid header name '%s'", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("value of header '%s' must not contain line breaks", name)
+		}
+	}
+	return nil
+}
+
+// RedactHeaders returns a copy of the headers with all values redacted.
+// Header values often carry secrets like API keys, so none of them are shown.
+func RedactHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	r := make(map[string]string, len(headers))
+	for name := range headers {
+		r[name] = RedactedValue
+	}
+	return r
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OAuthGrantType is the OAuth 2.1 grant that mcpjungle uses to obtain access tokens for an MCP server.
+type OAuthGrantType string
+
+const (
+	// OAuthGrantClientCredentials lets mcpjungle obtain tokens on its own using its client credentials.
+	OAuthGrantClientCredentials OAuthGrantType = "client_credentials"
+	// OAuthGrantAuthorizationCode requires a user to authorize mcpjungle in their browser once.
+	// mcpjungle then keeps the tokens fresh using the refresh token.
+	OAuthGrantAuthorizationCode OAuthGrantType = "authorization_code"
+)
+
+// UpstreamOAuthConfig configures mcpjungle as an OAuth client of an HTTP-based MCP server.
+type UpstreamOAuthConfig struct {
+	GrantType OAuthGrantType `json:"grant_type"`
+
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+
+	// TokenURL is the token endpoint of the authorization server.
+	TokenURL string `json:"token_url"`
+
+	// AuthURL is the authorization endpoint of the authorization server.
+	// It is required for the authorization_code grant.
+	AuthURL string `json:"auth_url,omitempty"`
+
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// Validate checks that the configuration contains everything its grant type needs.
+func (c *UpstreamOAuthConfig) Validate() error {
+	if c.ClientID == "" {
+		return errors.New("client_id is required")
+	}
+	if c.TokenURL == "" {
+		return errors.New("token_url is required")
+	}
+	switch c.GrantType {
+	case OAuthGrantClientCredentials:
+		if c.ClientSecret == "" {
+			return errors.New("client_secret is required for the client_credentials grant")
+		}
+	case OAuthGrantAuthorizationCode:
+		if c.AuthURL == "" {
+			return errors.New("auth_url is required for the authorization_code grant")
+		}
+	default:
+		return fmt.Errorf(
+			"invalid grant_type '%s' (acceptable values: '%s', '%s')",
+			c.GrantType, OAuthGrantClientCredentials, OAuthGrantAuthorizationCode,
+		)
+	}
+	return nil
+}
+
+// Redacted returns a copy of the configuration that is safe to show, ie, without the client secret.
+func (c *UpstreamOAuthConfig) Redacted() *UpstreamOAuthConfig {
+	r := *c
+	if r.ClientSecret != "" {
+		r.ClientSecret = RedactedValue
+	}
+	return &r
+}
+
+// OAuthAuthorizeInput is the input for starting the authorization of mcpjungle by a user
+// to access an MCP server that uses the authorization_code grant.
+type OAuthAuthorizeInput struct {
+	// Server is the name of the MCP server.
+	Server string `json:"server"`
+
+	// RedirectURI is where the user's browser is sent after they authorize mcpjungle.
+	// It is served by the CLI which then completes the authorization.
+	RedirectURI string `json:"redirect_uri"`
+
+	// OAuth is the OAuth configuration of the MCP server.
+	// It is only required if the server is not registered yet, otherwise its registered configuration is used.
+	OAuth *UpstreamOAuthConfig `json:"oauth,omitempty"`
+}
+
+// OAuthAuthorizeResponse contains the URL that the user must visit to authorize mcpjungle.
+type OAuthAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+// OAuthCallbackInput completes an authorization with the code returned by the authorization server.
+type OAuthCallbackInput struct {
+	State string `json:"state"`
+	Code  string `json:"code"`
+}